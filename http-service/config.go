@@ -1,6 +1,7 @@
 package httpservice
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -34,6 +35,7 @@ type Config struct {
 	EnableCompression   bool   `json:"enable_compression"`    // Enable response compression
 	EnableValidation    bool   `json:"enable_validation"`     // Enable request validation
 	EnableRateLimiting  bool   `json:"enable_rate_limiting"`  // Enable rate limiting
+	EnableSecurity      bool   `json:"enable_security"`       // Enable security headers middleware
 
 	// CORS settings
 	CORSAllowOrigins     []string `json:"cors_allow_origins"`
@@ -47,8 +49,26 @@ type Config struct {
 	RateLimitRequests int           `json:"rate_limit_requests"` // requests per window
 	RateLimitWindow   time.Duration `json:"rate_limit_window"`   // time window
 
+	// API versioning
+	DefaultAPIVersion int `json:"default_api_version"` // version used when a request names none, 0 selects the latest
+
+	// Security headers
+	Security SecurityConfig `json:"security"`
+
+	// JSON request body parsing
+	JSON JSONOptions `json:"json"`
+
+	// Logging
+	LogLevel string `json:"log_level"` // debug, info, warn, error
+
 	// Debug
-	EnableDebug bool `json:"enable_debug"`
+	EnableDebug bool                            `json:"enable_debug"` // Enable /debug endpoints
+	DebugToken  string                          `json:"debug_token"`  // Bearer token required for /debug endpoints
+	DebugAuth   func(ctx context.Context) error `json:"-"`            // Custom authorization for /debug endpoints
+
+	// Contract testing
+	EnableContractValidation bool                              `json:"enable_contract_validation"` // Validate responses against declared responses
+	OnContractViolation      func(violation ContractViolation) `json:"-"`                          // Called for each violation, default logs a warning
 }
 
 // DefaultConfig returns the default configuration
@@ -81,6 +101,7 @@ func DefaultConfig() *Config {
 		EnableCompression:  true,
 		EnableValidation:   true,
 		EnableRateLimiting: false,
+		EnableSecurity:     false,
 
 		// CORS defaults
 		CORSAllowOrigins:     []string{"*"},
@@ -94,6 +115,17 @@ func DefaultConfig() *Config {
 		RateLimitRequests: 100,
 		RateLimitWindow:   time.Minute,
 
+		// Security defaults
+		Security: DefaultSecurityConfig(),
+
+		// JSON defaults
+		JSON: JSONOptions{
+			MaxDepth: 32,
+		},
+
+		// Logging
+		LogLevel: "info",
+
 		// Debug
 		EnableDebug: false,
 	}
@@ -138,6 +170,24 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.Security.HSTSMaxAge < 0 {
+		return fmt.Errorf("hsts max age cannot be negative")
+	}
+
+	if c.DefaultAPIVersion < 0 {
+		return fmt.Errorf("default api version cannot be negative")
+	}
+
+	if c.JSON.MaxDepth < 0 {
+		return fmt.Errorf("json max depth cannot be negative")
+	}
+
+	if c.LogLevel != "" {
+		if _, err := ParseLogLevel(c.LogLevel); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -14,6 +14,12 @@ const (
 	contextKeyRequestCtx contextKey = "request_ctx"
 	contextKeyPathParams contextKey = "path_params"
 	contextKeyRequestID  contextKey = "request_id"
+	contextKeyCSRFToken  contextKey = "csrf_token"
+	contextKeyJSON       contextKey = "json_options"
+	contextKeyAPIVersion contextKey = "api_version"
+	contextKeyLocale     contextKey = "locale"
+	contextKeyLogLevel   contextKey = "log_level"
+	contextKeyRoutePath  contextKey = "route_path"
 )
 
 // GetRequestCtx retrieves the fasthttp.RequestCtx from context
@@ -55,6 +61,71 @@ func SetRequestID(ctx context.Context, id string) context.Context {
 	return context.WithValue(ctx, contextKeyRequestID, id)
 }
 
+// GetCSRFToken retrieves the CSRF token from context
+func GetCSRFToken(ctx context.Context) string {
+	if token, ok := ctx.Value(contextKeyCSRFToken).(string); ok {
+		return token
+	}
+	return ""
+}
+
+// SetCSRFToken sets the CSRF token in context
+func SetCSRFToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, contextKeyCSRFToken, token)
+}
+
+// GetJSONOptions retrieves the JSON binding options from context
+func GetJSONOptions(ctx context.Context) JSONOptions {
+	if opts, ok := ctx.Value(contextKeyJSON).(JSONOptions); ok {
+		return opts
+	}
+	return JSONOptions{}
+}
+
+// SetJSONOptions sets the JSON binding options in context
+func SetJSONOptions(ctx context.Context, opts JSONOptions) context.Context {
+	return context.WithValue(ctx, contextKeyJSON, opts)
+}
+
+// GetAPIVersion retrieves the resolved API version from context, 0 if unversioned
+func GetAPIVersion(ctx context.Context) int {
+	if version, ok := ctx.Value(contextKeyAPIVersion).(int); ok {
+		return version
+	}
+	return 0
+}
+
+// SetAPIVersion sets the resolved API version in context
+func SetAPIVersion(ctx context.Context, version int) context.Context {
+	return context.WithValue(ctx, contextKeyAPIVersion, version)
+}
+
+// GetLocale retrieves the locale set for the request, empty if none was set
+func GetLocale(ctx context.Context) string {
+	if locale, ok := ctx.Value(contextKeyLocale).(string); ok {
+		return locale
+	}
+	return ""
+}
+
+// SetLocale sets the locale used for validation messages, overriding Accept-Language
+func SetLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKeyLocale, locale)
+}
+
+// setRoutePath stores the request path the route matched, without any version prefix
+func setRoutePath(ctx context.Context, path string) context.Context {
+	return context.WithValue(ctx, contextKeyRoutePath, path)
+}
+
+// routePath retrieves the path the route matched, falling back to the raw request path
+func routePath(ctx context.Context, reqCtx *fasthttp.RequestCtx) string {
+	if path, ok := ctx.Value(contextKeyRoutePath).(string); ok {
+		return path
+	}
+	return string(reqCtx.Path())
+}
+
 // PathParam retrieves a path parameter by name
 func PathParam(ctx context.Context, name string) string {
 	params := GetPathParams(ctx)
@@ -0,0 +1,613 @@
+package httpservice
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/valyala/fasthttp"
+)
+
+// uuidPattern matches a canonical UUID string
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// pathParamPattern matches {name} path parameters
+var pathParamPattern = regexp.MustCompile(`\{[^/}]+\}`)
+
+// ContractViolation describes a response that does not match the route's declared responses
+type ContractViolation struct {
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	StatusCode int      `json:"status_code"`
+	Errors     []string `json:"errors"`
+}
+
+// Error implements the error interface
+func (v *ContractViolation) Error() string {
+	return fmt.Sprintf("contract violation: %s %s -> %d: %s", v.Method, v.Path, v.StatusCode, strings.Join(v.Errors, "; "))
+}
+
+// ValidateResponse checks a response status and JSON body against the responses
+// declared with WithResponse. It returns nil when the route declares no responses.
+func (r *Route) ValidateResponse(statusCode int, body []byte) *ContractViolation {
+	if len(r.Responses) == 0 {
+		return nil
+	}
+
+	violation := &ContractViolation{
+		Method:     r.Method,
+		Path:       versionedPath(r),
+		StatusCode: statusCode,
+	}
+
+	declared, ok := r.Responses[statusCode]
+	if !ok {
+		violation.Errors = append(violation.Errors, fmt.Sprintf("status %d is not declared", statusCode))
+		return violation
+	}
+
+	// A nil response declares the status only
+	if declared == nil {
+		return nil
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		violation.Errors = append(violation.Errors, "response body is empty")
+		return violation
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		violation.Errors = append(violation.Errors, fmt.Sprintf("response body is not valid JSON: %v", err))
+		return violation
+	}
+
+	violation.Errors = validateSchemaValue(value, generateSchema(declared), "$")
+	if len(violation.Errors) == 0 {
+		return nil
+	}
+
+	return violation
+}
+
+// checkResponseContract validates the written response and reports violations
+func (s *Service) checkResponseContract(route *Route, ctx *fasthttp.RequestCtx) {
+	if route.matchPrefix {
+		return
+	}
+
+	violation := route.ValidateResponse(ctx.Response.StatusCode(), ctx.Response.Body())
+	if violation == nil {
+		return
+	}
+
+	if s.config.OnContractViolation != nil {
+		s.config.OnContractViolation(*violation)
+		return
+	}
+
+	s.logf(LogLevelWarn, "%v", violation)
+}
+
+// validateSchemaValue validates a decoded JSON value against a schema and
+// returns one message per mismatch, prefixed with the JSON path
+func validateSchemaValue(value interface{}, schema map[string]interface{}, path string) []string {
+	if value == nil {
+		if schema["nullable"] == true || schema["type"] == nil {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s: expected %v, got null", path, schema["type"])}
+	}
+
+	schemaType, _ := schema["type"].(string)
+	if schemaType == "" {
+		return nil
+	}
+
+	if actual := jsonTypeOf(value); actual != schemaType && !(schemaType == "number" && actual == "integer") {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", path, schemaType, actual)}
+	}
+
+	var errs []string
+
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsValue(enum, value) {
+		errs = append(errs, fmt.Sprintf("%s: value %v is not one of %v", path, value, enum))
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if min, ok := schemaNumber(schema, "minLength"); ok && float64(length) < min {
+			errs = append(errs, fmt.Sprintf("%s: length %d is less than %v", path, length, min))
+		}
+		if max, ok := schemaNumber(schema, "maxLength"); ok && float64(length) > max {
+			errs = append(errs, fmt.Sprintf("%s: length %d is greater than %v", path, length, max))
+		}
+		if msg := checkStringFormat(v, schema["format"]); msg != "" {
+			errs = append(errs, fmt.Sprintf("%s: %s", path, msg))
+		}
+	case float64:
+		if min, ok := schemaNumber(schema, "minimum"); ok {
+			if v < min || (v == min && schema["exclusiveMinimum"] == true) {
+				errs = append(errs, fmt.Sprintf("%s: %v is below the minimum %v", path, v, min))
+			}
+		}
+		if max, ok := schemaNumber(schema, "maximum"); ok {
+			if v > max || (v == max && schema["exclusiveMaximum"] == true) {
+				errs = append(errs, fmt.Sprintf("%s: %v is above the maximum %v", path, v, max))
+			}
+		}
+	case []interface{}:
+		if min, ok := schemaNumber(schema, "minItems"); ok && float64(len(v)) < min {
+			errs = append(errs, fmt.Sprintf("%s: has %d items, expected at least %v", path, len(v), min))
+		}
+		if max, ok := schemaNumber(schema, "maxItems"); ok && float64(len(v)) > max {
+			errs = append(errs, fmt.Sprintf("%s: has %d items, expected at most %v", path, len(v), max))
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				errs = append(errs, validateSchemaValue(item, items, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case map[string]interface{}:
+		errs = append(errs, validateSchemaObject(v, schema, path)...)
+	}
+
+	return errs
+}
+
+// validateSchemaObject validates required, declared and additional properties
+func validateSchemaObject(value map[string]interface{}, schema map[string]interface{}, path string) []string {
+	var errs []string
+
+	if required, ok := schema["required"].([]string); ok {
+		for _, name := range required {
+			if _, present := value[name]; !present {
+				errs = append(errs, fmt.Sprintf("%s.%s: is required", path, name))
+			}
+		}
+	}
+
+	properties, hasProperties := schema["properties"].(map[string]interface{})
+	additional, hasAdditional := schema["additionalProperties"].(map[string]interface{})
+
+	// Visit keys in order so messages are stable
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPath := path + "." + key
+		if propSchema, ok := properties[key].(map[string]interface{}); ok {
+			errs = append(errs, validateSchemaValue(value[key], propSchema, childPath)...)
+		} else if hasAdditional {
+			errs = append(errs, validateSchemaValue(value[key], additional, childPath)...)
+		} else if hasProperties {
+			errs = append(errs, fmt.Sprintf("%s: unexpected property", childPath))
+		}
+	}
+
+	return errs
+}
+
+// jsonTypeOf returns the schema type name of a decoded JSON value
+func jsonTypeOf(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return "null"
+	}
+}
+
+// schemaNumber reads a numeric schema keyword
+func schemaNumber(schema map[string]interface{}, key string) (float64, bool) {
+	switch v := schema[key].(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// checkStringFormat validates the formats the schema generator emits
+func checkStringFormat(value string, format interface{}) string {
+	switch format {
+	case "email":
+		if at := strings.Index(value, "@"); at <= 0 || at == len(value)-1 {
+			return fmt.Sprintf("%q is not a valid email", value)
+		}
+	case "uuid":
+		if !uuidPattern.MatchString(value) {
+			return fmt.Sprintf("%q is not a valid uuid", value)
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339Nano, value); err != nil {
+			return fmt.Sprintf("%q is not a valid date-time", value)
+		}
+	}
+	return ""
+}
+
+// containsValue checks if a decoded JSON value is in an enum
+func containsValue(enum []interface{}, value interface{}) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// FuzzOptions configures FuzzRoutes
+type FuzzOptions struct {
+	Iterations int               // Valid requests generated per route, default 5
+	Seed       int64             // Random seed, 0 uses the current time
+	Headers    map[string]string // Headers sent with every request, e.g. Authorization
+	Methods    []string          // Methods to fuzz, default GET, HEAD and OPTIONS; list mutating methods explicitly
+	Filter     func(*Route) bool // Fuzzes only routes it returns true for, optional
+}
+
+// defaultFuzzMethods are the methods fuzzed when FuzzOptions.Methods is empty
+var defaultFuzzMethods = []string{"GET", "HEAD", "OPTIONS"}
+
+// FuzzResult is the outcome of a single fuzzed request
+type FuzzResult struct {
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Case       string   `json:"case"`
+	Valid      bool     `json:"valid"`
+	Body       string   `json:"body,omitempty"`
+	StatusCode int      `json:"status_code"`
+	Problems   []string `json:"problems,omitempty"`
+}
+
+// Passed reports whether the request behaved as expected
+func (r FuzzResult) Passed() bool {
+	return len(r.Problems) == 0
+}
+
+// FuzzRoutes exercises the routes in-process with requests generated from the
+// request body schema. Valid requests must not fail with 5xx and must match the
+// declared responses; invalid requests must be rejected with a 4xx status.
+// Only safe methods are fuzzed unless opts.Methods lists others.
+func (s *Service) FuzzRoutes(opts FuzzOptions) []FuzzResult {
+	if opts.Iterations <= 0 {
+		opts.Iterations = 5
+	}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+	if len(opts.Methods) == 0 {
+		opts.Methods = defaultFuzzMethods
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	var results []FuzzResult
+
+	for _, route := range s.routes {
+		if route.matchPrefix || route.hidden || !slices.Contains(opts.Methods, route.Method) {
+			continue
+		}
+		if opts.Filter != nil && !opts.Filter(route) {
+			continue
+		}
+
+		path := pathParamPattern.ReplaceAllString(versionedPath(route), "1")
+
+		if route.RequestBody == nil {
+			results = append(results, s.fuzzRequest(route, path, "no body", true, nil, opts.Headers))
+			continue
+		}
+
+		schema := generateSchema(route.RequestBody)
+
+		for i := 0; i < opts.Iterations; i++ {
+			body, _ := json.Marshal(generateSample(schema, rng, true))
+			results = append(results, s.fuzzRequest(route, path, fmt.Sprintf("valid #%d", i+1), true, body, opts.Headers))
+		}
+
+		for _, invalid := range generateInvalidSamples(schema, rng) {
+			results = append(results, s.fuzzRequest(route, path, invalid.name, false, invalid.body, opts.Headers))
+		}
+	}
+
+	return results
+}
+
+// fuzzRequest sends a single request through the service handler and checks the outcome
+func (s *Service) fuzzRequest(route *Route, path, name string, valid bool, body []byte, headers map[string]string) FuzzResult {
+	reqCtx := &fasthttp.RequestCtx{}
+	reqCtx.Request.Header.SetMethod(route.Method)
+	reqCtx.Request.SetRequestURI(path)
+	for key, value := range headers {
+		reqCtx.Request.Header.Set(key, value)
+	}
+	if body != nil {
+		reqCtx.Request.Header.SetContentType("application/json")
+		reqCtx.Request.SetBody(body)
+	}
+
+	s.handler(reqCtx)
+
+	result := FuzzResult{
+		Method:     route.Method,
+		Path:       path,
+		Case:       name,
+		Valid:      valid,
+		Body:       string(body),
+		StatusCode: reqCtx.Response.StatusCode(),
+	}
+
+	status := result.StatusCode
+	switch {
+	case valid && status >= 500:
+		result.Problems = append(result.Problems, fmt.Sprintf("valid request failed with status %d", status))
+	case valid && status < 400:
+		if violation := route.ValidateResponse(status, reqCtx.Response.Body()); violation != nil {
+			result.Problems = append(result.Problems, violation.Errors...)
+		}
+	case !valid && (status < 400 || status >= 500):
+		result.Problems = append(result.Problems, fmt.Sprintf("invalid request was answered with status %d, expected 4xx", status))
+	}
+
+	return result
+}
+
+// invalidSample is a request body expected to be rejected
+type invalidSample struct {
+	name string
+	body []byte
+}
+
+// generateInvalidSamples builds malformed bodies, bodies missing required fields
+// and bodies with wrongly typed or out-of-range values
+func generateInvalidSamples(schema map[string]interface{}, rng *rand.Rand) []invalidSample {
+	samples := []invalidSample{
+		{name: "malformed JSON", body: []byte(`{"`)},
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	if properties == nil {
+		return samples
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	mutate := func(name string, fn func(map[string]interface{})) invalidSample {
+		base, _ := generateSample(schema, rng, false).(map[string]interface{})
+		fn(base)
+		body, _ := json.Marshal(base)
+		return invalidSample{name: name, body: body}
+	}
+
+	if required, ok := schema["required"].([]string); ok {
+		for _, field := range required {
+			field := field
+			samples = append(samples, mutate("missing "+field, func(m map[string]interface{}) {
+				delete(m, field)
+			}))
+		}
+	}
+
+	for _, field := range names {
+		field := field
+		propSchema, _ := properties[field].(map[string]interface{})
+
+		if wrong, ok := wrongTypeValue(propSchema); ok {
+			samples = append(samples, mutate("wrong type for "+field, func(m map[string]interface{}) {
+				m[field] = wrong
+			}))
+		}
+
+		if value, ok := outOfRangeValue(propSchema); ok {
+			samples = append(samples, mutate("out of range "+field, func(m map[string]interface{}) {
+				m[field] = value
+			}))
+		}
+	}
+
+	return samples
+}
+
+// wrongTypeValue returns a value of a different JSON type than the schema expects
+func wrongTypeValue(schema map[string]interface{}) (interface{}, bool) {
+	switch schema["type"] {
+	case "string":
+		return 12345, true
+	case "integer", "number":
+		return "not-a-number", true
+	case "boolean":
+		return "yes", true
+	case "array":
+		return map[string]interface{}{}, true
+	case "object":
+		return []interface{}{}, true
+	}
+	return nil, false
+}
+
+// outOfRangeValue returns a value violating the schema's length or range limits
+func outOfRangeValue(schema map[string]interface{}) (interface{}, bool) {
+	switch schema["type"] {
+	case "string":
+		if max, ok := schemaNumber(schema, "maxLength"); ok {
+			return strings.Repeat("x", int(max)+1), true
+		}
+		if min, ok := schemaNumber(schema, "minLength"); ok && min > 1 {
+			return strings.Repeat("x", int(min)-1), true
+		}
+	case "integer", "number":
+		if max, ok := schemaNumber(schema, "maximum"); ok {
+			return max + 1, true
+		}
+		if min, ok := schemaNumber(schema, "minimum"); ok {
+			return min - 1, true
+		}
+	case "array":
+		if max, ok := schemaNumber(schema, "maxItems"); ok {
+			items, _ := schema["items"].(map[string]interface{})
+			values := make([]interface{}, int(max)+1)
+			for i := range values {
+				values[i] = generateSample(items, rand.New(rand.NewSource(int64(i))), true)
+			}
+			return values, true
+		}
+	}
+	return nil, false
+}
+
+// generateSample generates a random value satisfying the schema. Required values
+// avoid zero values so they pass "required" validation.
+func generateSample(schema map[string]interface{}, rng *rand.Rand, required bool) interface{} {
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[rng.Intn(len(enum))]
+	}
+
+	switch schema["type"] {
+	case "string":
+		return generateString(schema, rng, required)
+	case "integer":
+		min, max := sampleRange(schema, required)
+		low, high := math.Ceil(min), math.Floor(max)
+		// No whole number fits the range (e.g. 0.2 to 0.8); the closest one is
+		// the best the generator can do
+		if high < low {
+			return int64(math.Round(min))
+		}
+		return int64(low) + rng.Int63n(int64(high-low)+1)
+	case "number":
+		min, max := sampleRange(schema, required)
+		return min + rng.Float64()*(max-min)
+	case "boolean":
+		return required || rng.Intn(2) == 0
+	case "array":
+		minItems, _ := schemaNumber(schema, "minItems")
+		maxItems, ok := schemaNumber(schema, "maxItems")
+		if !ok {
+			maxItems = minItems + 3
+		}
+		if required && minItems < 1 && maxItems >= 1 {
+			minItems = 1
+		}
+		maxItems = max(maxItems, minItems)
+		n := int(minItems) + rng.Intn(int(maxItems-minItems)+1)
+		items, _ := schema["items"].(map[string]interface{})
+		values := make([]interface{}, n)
+		for i := range values {
+			values[i] = generateSample(items, rng, true)
+		}
+		return values
+	case "object":
+		obj := make(map[string]interface{})
+		requiredFields := make(map[string]bool)
+		if fields, ok := schema["required"].([]string); ok {
+			for _, f := range fields {
+				requiredFields[f] = true
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, prop := range properties {
+			propSchema, _ := prop.(map[string]interface{})
+			if requiredFields[name] || rng.Intn(2) == 0 {
+				obj[name] = generateSample(propSchema, rng, requiredFields[name])
+			}
+		}
+		return obj
+	default:
+		return "sample"
+	}
+}
+
+// generateString generates a string honouring format and length constraints
+func generateString(schema map[string]interface{}, rng *rand.Rand, required bool) string {
+	switch schema["format"] {
+	case "email":
+		return fmt.Sprintf("user%d@example.com", rng.Intn(10000))
+	case "uuid":
+		b := make([]byte, 16)
+		rng.Read(b)
+		b[6] = (b[6] & 0x0f) | 0x40 // version 4
+		b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	case "url", "uri":
+		return fmt.Sprintf("https://example.com/%d", rng.Intn(10000))
+	case "date-time":
+		return time.Unix(rng.Int63n(2000000000), 0).UTC().Format(time.RFC3339)
+	case "byte":
+		return "c2FtcGxl"
+	}
+
+	minLen, _ := schemaNumber(schema, "minLength")
+	maxLen, ok := schemaNumber(schema, "maxLength")
+	if !ok {
+		maxLen = minLen + 12
+	}
+	if required && minLen < 1 {
+		minLen = 1
+	}
+	maxLen = max(maxLen, minLen)
+
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	n := int(minLen) + rng.Intn(int(maxLen-minLen)+1)
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[rng.Intn(len(letters))]
+	}
+	return string(b)
+}
+
+// sampleRange returns the numeric range for generated values
+func sampleRange(schema map[string]interface{}, required bool) (float64, float64) {
+	min, hasMin := schemaNumber(schema, "minimum")
+	max, hasMax := schemaNumber(schema, "maximum")
+
+	if !hasMin {
+		min = 0
+		if required {
+			min = 1
+		}
+	}
+	if !hasMax {
+		max = min + 100
+	}
+
+	if schema["exclusiveMinimum"] == true {
+		min += 1
+	}
+	if schema["exclusiveMaximum"] == true {
+		max -= 1
+	}
+
+	if max < min {
+		max = min
+	}
+
+	return min, max
+}
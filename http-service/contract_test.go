@@ -0,0 +1,241 @@
+package httpservice
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+type contractItem struct {
+	SKU      string `json:"sku" validate:"required,min=3,max=12"`
+	Quantity int    `json:"quantity" validate:"required,min=1,max=100"`
+}
+
+type contractOrder struct {
+	Email  string         `json:"email" validate:"required,email"`
+	Status string         `json:"status" validate:"oneof=new paid"`
+	Items  []contractItem `json:"items" validate:"required,min=1,max=5,dive"`
+}
+
+func TestValidateResponse(t *testing.T) {
+	route := &Route{Method: "GET", Path: "/orders/{id}"}
+	WithResponse(200, contractOrder{})(route)
+	WithResponse(204, nil)(route)
+
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantErrors []string
+	}{
+		{
+			name:   "valid",
+			status: 200,
+			body:   `{"email":"a@example.com","status":"new","items":[{"sku":"ABC","quantity":2}]}`,
+		},
+		{
+			name:   "declared without body",
+			status: 204,
+		},
+		{
+			name:       "undeclared status",
+			status:     500,
+			body:       `{}`,
+			wantErrors: []string{"status 500 is not declared"},
+		},
+		{
+			name:       "invalid JSON",
+			status:     200,
+			body:       `{"email"`,
+			wantErrors: []string{"not valid JSON"},
+		},
+		{
+			name:   "nested errors",
+			status: 200,
+			body:   `{"email":"nope","status":"lost","items":[{"sku":"ABC","quantity":2},{"sku":"A","quantity":"2"}],"extra":true}`,
+			wantErrors: []string{
+				`$.email: "nope" is not a valid email`,
+				"$.extra: unexpected property",
+				"$.items[1].quantity: expected integer, got string",
+				"$.items[1].sku: length 1 is less than 3",
+				"$.status: value lost is not one of",
+			},
+		},
+		{
+			name:       "missing required",
+			status:     200,
+			body:       `{"email":"a@example.com","status":"new"}`,
+			wantErrors: []string{"$.items: is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violation := route.ValidateResponse(tt.status, []byte(tt.body))
+			if len(tt.wantErrors) == 0 {
+				if violation != nil {
+					t.Fatalf("Expected no violation, got %v", violation)
+				}
+				return
+			}
+
+			if violation == nil {
+				t.Fatal("Expected a violation")
+			}
+			if len(violation.Errors) != len(tt.wantErrors) {
+				t.Fatalf("Expected %d errors, got %v", len(tt.wantErrors), violation.Errors)
+			}
+			for i, want := range tt.wantErrors {
+				if !strings.Contains(violation.Errors[i], want) {
+					t.Errorf("Error %d = %q, want it to contain %q", i, violation.Errors[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestContractValidationHook(t *testing.T) {
+	var violations []ContractViolation
+
+	service, err := New(
+		WithLogger(false),
+		WithContractValidation(true),
+		WithContractViolationHandler(func(v ContractViolation) {
+			violations = append(violations, v)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	service.GET("/good", func(ctx context.Context) (interface{}, error) {
+		return contractItem{SKU: "ABC", Quantity: 1}, nil
+	}, WithResponse(200, contractItem{}))
+
+	service.GET("/bad", func(ctx context.Context) (interface{}, error) {
+		return map[string]interface{}{"sku": 42}, nil
+	}, WithResponse(200, contractItem{}))
+
+	service.GET("/undocumented", func(ctx context.Context) (interface{}, error) {
+		return "anything", nil
+	})
+
+	for _, uri := range []string{"/good", "/bad", "/undocumented"} {
+		serveTestRequest(service, "GET", uri, nil)
+	}
+
+	if len(violations) != 1 {
+		t.Fatalf("Expected 1 violation, got %d: %v", len(violations), violations)
+	}
+
+	v := violations[0]
+	if v.Path != "/bad" || v.StatusCode != 200 {
+		t.Errorf("Unexpected violation %+v", v)
+	}
+	if strings.Join(v.Errors, "; ") != "$.quantity: is required; $.sku: expected string, got integer" {
+		t.Errorf("Unexpected errors %v", v.Errors)
+	}
+}
+
+func TestFuzzRoutes(t *testing.T) {
+	service, err := New(WithLogger(false))
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	service.POST("/orders", func(ctx context.Context, req *contractOrder) (interface{}, error) {
+		return req, nil
+	}, WithRequestBody(contractOrder{}), WithResponse(200, contractOrder{}))
+
+	// Accepts anything, so invalid bodies are not rejected
+	service.POST("/lenient", func(ctx context.Context) (interface{}, error) {
+		return map[string]string{"ok": "yes"}, nil
+	}, WithRequestBody(contractItem{}))
+
+	// Safe methods only by default
+	for _, result := range service.FuzzRoutes(FuzzOptions{Iterations: 10, Seed: 1}) {
+		if result.Method != "GET" {
+			t.Errorf("Expected only GET routes to be fuzzed by default, got %s %s", result.Method, result.Path)
+		}
+	}
+
+	results := service.FuzzRoutes(FuzzOptions{Iterations: 10, Seed: 1, Methods: []string{"POST"}})
+
+	cases := make(map[string]int)
+	for _, result := range results {
+		cases[result.Path]++
+
+		switch result.Path {
+		case "/orders":
+			if !result.Passed() {
+				t.Errorf("%s %q: %v (body %s)", result.Path, result.Case, result.Problems, result.Body)
+			}
+		case "/lenient":
+			if !result.Valid && result.Passed() {
+				t.Errorf("Expected %q to be flagged on /lenient", result.Case)
+			}
+		}
+	}
+
+	// 10 valid + malformed + 2 missing + 3 wrong type + 1 out of range
+	if cases["/orders"] != 17 {
+		t.Errorf("Expected 17 cases for /orders, got %d", cases["/orders"])
+	}
+	if cases["/lenient"] == 0 {
+		t.Error("Expected cases for /lenient")
+	}
+
+	filtered := service.FuzzRoutes(FuzzOptions{
+		Seed:    1,
+		Methods: []string{"POST"},
+		Filter:  func(route *Route) bool { return route.Path == "/orders" },
+	})
+	for _, result := range filtered {
+		if result.Path != "/orders" {
+			t.Errorf("Expected only /orders to be fuzzed, got %s", result.Path)
+		}
+	}
+	if len(filtered) == 0 {
+		t.Error("Expected cases for /orders")
+	}
+}
+
+func TestGenerateSampleDegenerateSchemas(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema map[string]interface{}
+		check  func(v interface{}) bool
+	}{
+		{
+			name:   "required string with maxLength 0",
+			schema: map[string]interface{}{"type": "string", "maxLength": 0},
+			check:  func(v interface{}) bool { return len(v.(string)) == 1 },
+		},
+		{
+			name:   "maxLength below minLength",
+			schema: map[string]interface{}{"type": "string", "minLength": 5, "maxLength": 2},
+			check:  func(v interface{}) bool { return len(v.(string)) == 5 },
+		},
+		{
+			name:   "integer range without a whole number",
+			schema: map[string]interface{}{"type": "integer", "minimum": 0.2, "maximum": 0.8},
+			check:  func(v interface{}) bool { return v.(int64) == 0 },
+		},
+		{
+			name:   "maxItems below minItems",
+			schema: map[string]interface{}{"type": "array", "minItems": 3, "maxItems": 1, "items": map[string]interface{}{"type": "boolean"}},
+			check:  func(v interface{}) bool { return len(v.([]interface{})) == 3 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(0); seed < 20; seed++ {
+				if v := generateSample(tt.schema, rand.New(rand.NewSource(seed)), true); !tt.check(v) {
+					t.Fatalf("Unexpected sample %#v", v)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package httpservice
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp/pprofhandler"
+)
+
+// secretKeyPattern matches config keys whose values are redacted in /debug/config
+var secretKeyPattern = regexp.MustCompile(`(?i)(secret|password|token|credential|private|api_key)`)
+
+// funcSuffixPattern matches the suffix Go adds to closure names
+var funcSuffixPattern = regexp.MustCompile(`(\.func\d+)+$`)
+
+// RouteInfo describes a registered route
+type RouteInfo struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	Version     int      `json:"version,omitempty"`
+	Prefix      bool     `json:"prefix,omitempty"`
+	Handler     string   `json:"handler"`
+	HandlerType string   `json:"handler_type"`
+	Middleware  []string `json:"middleware"`
+}
+
+// RuntimeStats holds runtime statistics of the process
+type RuntimeStats struct {
+	GoVersion  string      `json:"go_version"`
+	NumCPU     int         `json:"num_cpu"`
+	GOMAXPROCS int         `json:"gomaxprocs"`
+	Goroutines int         `json:"goroutines"`
+	Uptime     string      `json:"uptime"`
+	Memory     MemoryStats `json:"memory"`
+	GC         GCStats     `json:"gc"`
+}
+
+// MemoryStats holds memory statistics in bytes
+type MemoryStats struct {
+	Alloc       uint64 `json:"alloc"`
+	TotalAlloc  uint64 `json:"total_alloc"`
+	Sys         uint64 `json:"sys"`
+	HeapAlloc   uint64 `json:"heap_alloc"`
+	HeapInuse   uint64 `json:"heap_inuse"`
+	HeapObjects uint64 `json:"heap_objects"`
+	StackInuse  uint64 `json:"stack_inuse"`
+}
+
+// GCStats holds garbage collector statistics
+type GCStats struct {
+	NumGC        uint32    `json:"num_gc"`
+	PauseTotal   string    `json:"pause_total"`
+	LastPause    string    `json:"last_pause"`
+	LastGC       time.Time `json:"last_gc"`
+	CPUFraction  float64   `json:"cpu_fraction"`
+	NextGCTarget uint64    `json:"next_gc_target"`
+}
+
+// logLevelRequest is the body of PUT /debug/loglevel
+type logLevelRequest struct {
+	Level string `json:"level" validate:"required"`
+}
+
+// Routes returns information about all registered routes
+func (s *Service) Routes() []RouteInfo {
+	global := middlewareNames(s.globalMiddleware)
+
+	routes := make([]RouteInfo, 0, len(s.routes))
+	for _, route := range s.routes {
+		middleware := append(append([]string{}, global...), middlewareNames(route.Middlewares)...)
+		routes = append(routes, RouteInfo{
+			Method:      route.Method,
+			Path:        versionedPath(route),
+			Version:     route.Version,
+			Prefix:      route.matchPrefix,
+			Handler:     route.handlerName,
+			HandlerType: route.handlerType,
+			Middleware:  middleware,
+		})
+	}
+	return routes
+}
+
+// RuntimeStats returns current runtime statistics
+func (s *Service) RuntimeStats() *RuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	stats := &RuntimeStats{
+		GoVersion:  runtime.Version(),
+		NumCPU:     runtime.NumCPU(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		Goroutines: runtime.NumGoroutine(),
+		Uptime:     time.Since(s.startedAt).Round(time.Second).String(),
+		Memory: MemoryStats{
+			Alloc:       mem.Alloc,
+			TotalAlloc:  mem.TotalAlloc,
+			Sys:         mem.Sys,
+			HeapAlloc:   mem.HeapAlloc,
+			HeapInuse:   mem.HeapInuse,
+			HeapObjects: mem.HeapObjects,
+			StackInuse:  mem.StackInuse,
+		},
+		GC: GCStats{
+			NumGC:        mem.NumGC,
+			PauseTotal:   time.Duration(mem.PauseTotalNs).String(),
+			CPUFraction:  mem.GCCPUFraction,
+			NextGCTarget: mem.NextGC,
+		},
+	}
+
+	if mem.NumGC > 0 {
+		stats.GC.LastPause = time.Duration(mem.PauseNs[(mem.NumGC+255)%256]).String()
+		stats.GC.LastGC = time.Unix(0, int64(mem.LastGC))
+	}
+
+	return stats
+}
+
+// RedactedConfig returns the effective configuration with secret values redacted
+func (s *Service) RedactedConfig() map[string]interface{} {
+	data, err := json.Marshal(s.config)
+	if err != nil {
+		return map[string]interface{}{}
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return map[string]interface{}{}
+	}
+
+	redactSecrets(config)
+	return config
+}
+
+// redactSecrets replaces non-empty values of secret-looking keys in place
+func redactSecrets(m map[string]interface{}) {
+	for key, value := range m {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			redactSecrets(v)
+		case string:
+			if v != "" && secretKeyPattern.MatchString(key) {
+				m[key] = "[REDACTED]"
+			}
+		}
+	}
+}
+
+// registerDebugRoutes registers the /debug endpoints
+func (s *Service) registerDebugRoutes() {
+	opts := []RouteOption{
+		WithTags("Debug"),
+		WithMiddleware(s.debugAuth()),
+		withHidden(),
+	}
+
+	s.GET("/debug", s.debugIndexHandler(), append(opts, WithSummary("List debug endpoints"))...)
+	s.GET("/debug/routes", s.debugRoutesHandler(), append(opts, WithSummary("Route table"))...)
+	s.GET("/debug/runtime", s.debugRuntimeHandler(), append(opts, WithSummary("Runtime statistics"))...)
+	s.GET("/debug/config", s.debugConfigHandler(), append(opts, WithSummary("Effective configuration"))...)
+	s.GET("/debug/loglevel", s.debugGetLogLevelHandler(), append(opts, WithSummary("Get log level"))...)
+	s.PUT("/debug/loglevel", s.debugSetLogLevelHandler(), append(opts, WithSummary("Set log level"))...)
+
+	// pprof profiles; symbol lookups may be POSTed
+	s.addPrefixRoute("GET", "/debug/pprof", debugPprofHandler(), opts...)
+	s.addPrefixRoute("POST", "/debug/pprof", debugPprofHandler(), opts...)
+}
+
+// debugAuth protects the debug endpoints. DebugAuth takes precedence, then
+// DebugToken; without either only loopback clients are allowed.
+func (s *Service) debugAuth() Middleware {
+	return Auth(func(ctx context.Context) error {
+		if s.config.DebugAuth != nil {
+			return s.config.DebugAuth(ctx)
+		}
+
+		if s.config.DebugToken != "" {
+			token := strings.TrimPrefix(Header(ctx, "Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(token), []byte(s.config.DebugToken)) != 1 {
+				return Unauthorized("Invalid debug token")
+			}
+			return nil
+		}
+
+		reqCtx := GetRequestCtx(ctx)
+		if reqCtx == nil || !reqCtx.RemoteIP().IsLoopback() {
+			return Forbidden("Debug endpoints are only available from localhost")
+		}
+		return nil
+	})
+}
+
+// debugIndexHandler lists the debug endpoints
+func (s *Service) debugIndexHandler() SimpleHandler {
+	return func(ctx context.Context) (interface{}, error) {
+		return map[string]string{
+			"routes":   "/debug/routes",
+			"runtime":  "/debug/runtime",
+			"config":   "/debug/config",
+			"loglevel": "/debug/loglevel",
+			"pprof":    "/debug/pprof/",
+		}, nil
+	}
+}
+
+// debugRoutesHandler returns the route table
+func (s *Service) debugRoutesHandler() SimpleHandler {
+	return func(ctx context.Context) (interface{}, error) {
+		return s.Routes(), nil
+	}
+}
+
+// debugRuntimeHandler returns runtime statistics
+func (s *Service) debugRuntimeHandler() SimpleHandler {
+	return func(ctx context.Context) (interface{}, error) {
+		return s.RuntimeStats(), nil
+	}
+}
+
+// debugConfigHandler returns the redacted effective configuration
+func (s *Service) debugConfigHandler() SimpleHandler {
+	return func(ctx context.Context) (interface{}, error) {
+		return s.RedactedConfig(), nil
+	}
+}
+
+// debugGetLogLevelHandler returns the current log level
+func (s *Service) debugGetLogLevelHandler() SimpleHandler {
+	return func(ctx context.Context) (interface{}, error) {
+		return map[string]string{"level": s.LogLevel().String()}, nil
+	}
+}
+
+// debugSetLogLevelHandler changes the log level at runtime
+func (s *Service) debugSetLogLevelHandler() HandlerFunc {
+	return func(ctx context.Context) error {
+		var req logLevelRequest
+		if err := BindAndValidate(ctx, &req, s.validator); err != nil {
+			return err
+		}
+
+		level, err := ParseLogLevel(req.Level)
+		if err != nil {
+			return BadRequest(err.Error())
+		}
+
+		previous := s.LogLevel()
+		s.SetLogLevel(level)
+		s.logf(LogLevelInfo, "Log level changed from %s to %s", previous, level)
+
+		return WriteResponse(GetRequestCtx(ctx), OK(map[string]string{"level": level.String()}))
+	}
+}
+
+// debugPprofHandler serves net/http/pprof profiles under /debug/pprof
+func debugPprofHandler() HandlerFunc {
+	return func(ctx context.Context) error {
+		pprofhandler.PprofHandler(GetRequestCtx(ctx))
+		return nil
+	}
+}
+
+// middlewareNames returns the function names of the given middleware
+func middlewareNames(middleware []Middleware) []string {
+	names := make([]string, 0, len(middleware))
+	for _, mw := range middleware {
+		names = append(names, funcName(mw))
+	}
+	return names
+}
+
+// funcName returns a short name for a function value, e.g. "Recovery" for the
+// closure returned by Recovery()
+func funcName(fn interface{}) string {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return ""
+	}
+
+	f := runtime.FuncForPC(v.Pointer())
+	if f == nil {
+		return ""
+	}
+
+	name := f.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	// Drop the package name
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return funcSuffixPattern.ReplaceAllString(name, "")
+}
+
+// typeName returns the Go type of a value as a string
+func typeName(v interface{}) string {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return ""
+	}
+	return t.String()
+}
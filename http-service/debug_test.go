@@ -0,0 +1,290 @@
+package httpservice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func newDebugTestService(t *testing.T, opts ...Option) *Service {
+	opts = append([]Option{WithLogger(false), WithDebug(true), WithDebugToken("s3cret")}, opts...)
+	service, err := New(opts...)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	return service
+}
+
+func debugHeaders() map[string]string {
+	return map[string]string{"Authorization": "Bearer s3cret"}
+}
+
+func TestDebugDisabledByDefault(t *testing.T) {
+	service, err := New(WithLogger(false))
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	reqCtx := serveTestRequest(service, "GET", "/debug/routes", nil)
+	if reqCtx.Response.StatusCode() != 404 {
+		t.Errorf("Expected status 404, got %d", reqCtx.Response.StatusCode())
+	}
+}
+
+func TestDebugAuth(t *testing.T) {
+	service := newDebugTestService(t)
+
+	reqCtx := serveTestRequest(service, "GET", "/debug/routes", nil)
+	if reqCtx.Response.StatusCode() != 401 {
+		t.Errorf("Expected status 401 without token, got %d", reqCtx.Response.StatusCode())
+	}
+
+	reqCtx = serveTestRequest(service, "GET", "/debug/routes", map[string]string{"Authorization": "Bearer wrong"})
+	if reqCtx.Response.StatusCode() != 401 {
+		t.Errorf("Expected status 401 with wrong token, got %d", reqCtx.Response.StatusCode())
+	}
+
+	reqCtx = serveTestRequest(service, "GET", "/debug/routes", debugHeaders())
+	if reqCtx.Response.StatusCode() != 200 {
+		t.Errorf("Expected status 200 with token, got %d", reqCtx.Response.StatusCode())
+	}
+}
+
+func TestDebugAuthLoopbackOnly(t *testing.T) {
+	service := newDebugTestService(t, WithDebugToken(""))
+
+	// A zero RequestCtx reports 0.0.0.0 as the remote address
+	reqCtx := serveTestRequest(service, "GET", "/debug/routes", nil)
+	if reqCtx.Response.StatusCode() != 403 {
+		t.Errorf("Expected status 403 for non-loopback client, got %d", reqCtx.Response.StatusCode())
+	}
+}
+
+func TestDebugCustomAuth(t *testing.T) {
+	service := newDebugTestService(t, WithDebugAuth(func(ctx context.Context) error {
+		if Header(ctx, "X-Admin") != "yes" {
+			return Forbidden("admins only")
+		}
+		return nil
+	}))
+
+	reqCtx := serveTestRequest(service, "GET", "/debug/runtime", debugHeaders())
+	if reqCtx.Response.StatusCode() != 403 {
+		t.Errorf("Expected custom auth to take precedence, got %d", reqCtx.Response.StatusCode())
+	}
+
+	reqCtx = serveTestRequest(service, "GET", "/debug/runtime", map[string]string{"X-Admin": "yes"})
+	if reqCtx.Response.StatusCode() != 200 {
+		t.Errorf("Expected status 200, got %d", reqCtx.Response.StatusCode())
+	}
+}
+
+func TestDebugRoutes(t *testing.T) {
+	service := newDebugTestService(t)
+	service.GET("/users/{id}", getDebugTestUser, WithMiddleware(Timeout(0)))
+
+	reqCtx := serveTestRequest(service, "GET", "/debug/routes", debugHeaders())
+
+	var routes []RouteInfo
+	if err := json.Unmarshal(reqCtx.Response.Body(), &routes); err != nil {
+		t.Fatalf("Failed to decode routes: %v", err)
+	}
+
+	var found *RouteInfo
+	for i := range routes {
+		if routes[i].Path == "/users/{id}" {
+			found = &routes[i]
+		}
+	}
+
+	if found == nil {
+		t.Fatal("Expected /users/{id} in route table")
+	}
+
+	if found.Handler != "getDebugTestUser" {
+		t.Errorf("Expected handler name getDebugTestUser, got %s", found.Handler)
+	}
+
+	if !strings.HasPrefix(found.HandlerType, "func(context.Context)") {
+		t.Errorf("Unexpected handler type: %s", found.HandlerType)
+	}
+
+	expected := []string{"Recovery", "RequestID", "CORS", "Timeout"}
+	if strings.Join(found.Middleware, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected middleware %v, got %v", expected, found.Middleware)
+	}
+}
+
+func TestDebugRoutesHidden(t *testing.T) {
+	service := newDebugTestService(t)
+	service.GET("/users/{id}", getDebugTestUser)
+
+	spec := GenerateOpenAPISpec(service.config, service.routes)
+	if _, ok := spec.Paths["/users/{id}"]; !ok {
+		t.Error("Expected /users/{id} in the OpenAPI spec")
+	}
+	for path := range spec.Paths {
+		if strings.HasPrefix(path, "/debug") {
+			t.Errorf("Debug route %s should not be in the OpenAPI spec", path)
+		}
+	}
+
+	for _, result := range service.FuzzRoutes(FuzzOptions{Iterations: 1, Seed: 1}) {
+		if strings.HasPrefix(result.Path, "/debug") {
+			t.Errorf("Debug route %s %s should not be fuzzed", result.Method, result.Path)
+		}
+	}
+}
+
+func getDebugTestUser(ctx context.Context) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestDebugRuntime(t *testing.T) {
+	service := newDebugTestService(t)
+
+	reqCtx := serveTestRequest(service, "GET", "/debug/runtime", debugHeaders())
+
+	var stats RuntimeStats
+	if err := json.Unmarshal(reqCtx.Response.Body(), &stats); err != nil {
+		t.Fatalf("Failed to decode runtime stats: %v", err)
+	}
+
+	if stats.Goroutines <= 0 {
+		t.Errorf("Expected positive goroutine count, got %d", stats.Goroutines)
+	}
+
+	if stats.Memory.Sys == 0 {
+		t.Error("Expected non-zero memory stats")
+	}
+}
+
+func TestDebugConfigRedactsSecrets(t *testing.T) {
+	service := newDebugTestService(t)
+
+	reqCtx := serveTestRequest(service, "GET", "/debug/config", debugHeaders())
+
+	var config map[string]interface{}
+	if err := json.Unmarshal(reqCtx.Response.Body(), &config); err != nil {
+		t.Fatalf("Failed to decode config: %v", err)
+	}
+
+	if config["debug_token"] != "[REDACTED]" {
+		t.Errorf("Expected debug token to be redacted, got %v", config["debug_token"])
+	}
+
+	if config["title"] != "HTTP Service" {
+		t.Errorf("Expected title to be present, got %v", config["title"])
+	}
+}
+
+func TestDebugLogLevel(t *testing.T) {
+	service := newDebugTestService(t)
+	other := newDebugTestService(t)
+
+	reqCtx := &fasthttp.RequestCtx{}
+	reqCtx.Request.Header.SetMethod("PUT")
+	reqCtx.Request.SetRequestURI("/debug/loglevel")
+	reqCtx.Request.Header.Set("Authorization", "Bearer s3cret")
+	reqCtx.Request.Header.SetContentType("application/json")
+	reqCtx.Request.SetBodyString(`{"level":"debug"}`)
+	service.handler(reqCtx)
+
+	if reqCtx.Response.StatusCode() != 200 {
+		t.Fatalf("Expected status 200, got %d: %s", reqCtx.Response.StatusCode(), reqCtx.Response.Body())
+	}
+
+	if service.LogLevel() != LogLevelDebug {
+		t.Errorf("Expected log level debug, got %s", service.LogLevel())
+	}
+
+	// Each service has its own log level
+	if other.LogLevel() != LogLevelInfo {
+		t.Errorf("Expected other service to keep log level info, got %s", other.LogLevel())
+	}
+
+	getCtx := serveTestRequest(service, "GET", "/debug/loglevel", debugHeaders())
+	if body := string(getCtx.Response.Body()); body != `{"level":"debug"}` {
+		t.Errorf("Unexpected body: %s", body)
+	}
+}
+
+func TestServiceLogLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	quiet, err := New(WithLogLevel("error"))
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	verbose, err := New(WithLogLevel("info"))
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	// Creating the verbose service must not change the quiet one
+	if quiet.LogLevel() != LogLevelError {
+		t.Errorf("Expected log level error, got %s", quiet.LogLevel())
+	}
+
+	serveTestRequest(quiet, "GET", "/health", nil)
+	if buf.Len() != 0 {
+		t.Errorf("Expected no request log at level error, got %q", buf.String())
+	}
+
+	serveTestRequest(verbose, "GET", "/health", nil)
+	if !strings.Contains(buf.String(), "GET /health") {
+		t.Errorf("Expected request log at level info, got %q", buf.String())
+	}
+
+	if _, err := New(WithLogLevel("loud")); err == nil {
+		t.Error("Expected error for invalid log level")
+	}
+}
+
+func TestDebugPprof(t *testing.T) {
+	service := newDebugTestService(t)
+
+	reqCtx := serveTestRequest(service, "GET", "/debug/pprof/goroutine?debug=1", debugHeaders())
+	if reqCtx.Response.StatusCode() != 200 {
+		t.Fatalf("Expected status 200, got %d", reqCtx.Response.StatusCode())
+	}
+
+	if !strings.Contains(string(reqCtx.Response.Body()), "goroutine profile") {
+		t.Error("Expected goroutine profile output")
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    LogLevel
+		wantErr bool
+	}{
+		{input: "debug", want: LogLevelDebug},
+		{input: "INFO", want: LogLevelInfo},
+		{input: "warning", want: LogLevelWarn},
+		{input: "error", want: LogLevelError},
+		{input: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			level, err := ParseLogLevel(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLogLevel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && level != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, level)
+			}
+		})
+	}
+}
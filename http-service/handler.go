@@ -8,9 +8,16 @@ import (
 type Route struct {
 	Method      string
 	Path        string
+	Version     int // API version, 0 for unversioned routes
 	Handler     HandlerFunc
 	Middlewares []Middleware
 
+	apiVersion  *APIVersion
+	matchPrefix bool   // Match the path and everything below it (proxy routes)
+	hidden      bool   // Internal route left out of the OpenAPI spec and FuzzRoutes
+	handlerName string // Name of the registered handler function
+	handlerType string // Go type of the registered handler
+
 	// OpenAPI documentation
 	Tags        []string
 	Summary     string
@@ -75,6 +82,14 @@ func WithMiddleware(middleware ...Middleware) RouteOption {
 	}
 }
 
+// withHidden marks an internal route, such as the debug endpoints, that is
+// not part of the documented API
+func withHidden() RouteOption {
+	return func(r *Route) {
+		r.hidden = true
+	}
+}
+
 // Handler types for different use cases
 
 // SimpleHandler is a handler that doesn't need request body
@@ -0,0 +1,94 @@
+package httpservice
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+)
+
+// LogLevel controls which messages the service logs
+type LogLevel int32
+
+const (
+	// LogLevelDebug logs everything
+	LogLevelDebug LogLevel = iota
+	// LogLevelInfo logs requests, startup and shutdown
+	LogLevelInfo
+	// LogLevelWarn logs warnings and errors
+	LogLevelWarn
+	// LogLevelError logs errors only
+	LogLevelError
+)
+
+// String returns the name of the log level
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "debug"
+	case LogLevelInfo:
+		return "info"
+	case LogLevelWarn:
+		return "warn"
+	case LogLevelError:
+		return "error"
+	default:
+		return fmt.Sprintf("LogLevel(%d)", l)
+	}
+}
+
+// ParseLogLevel parses a log level name (debug, info, warn, error)
+func ParseLogLevel(level string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return LogLevelDebug, nil
+	case "info":
+		return LogLevelInfo, nil
+	case "warn", "warning":
+		return LogLevelWarn, nil
+	case "error":
+		return LogLevelError, nil
+	default:
+		return LogLevelInfo, fmt.Errorf("unknown log level: %s", level)
+	}
+}
+
+// LogLevel returns the active log level of the service
+func (s *Service) LogLevel() LogLevel {
+	return LogLevel(s.logLevel.Load())
+}
+
+// SetLogLevel sets the active log level of the service
+func (s *Service) SetLogLevel(level LogLevel) {
+	s.logLevel.Store(int32(level))
+}
+
+// logf logs a message if the service's log level enables it
+func (s *Service) logf(level LogLevel, format string, args ...interface{}) {
+	logAt(&s.logLevel, level, format, args...)
+}
+
+// setLogLevelSource stores the log level that middleware logs against in context
+func setLogLevelSource(ctx context.Context, level *atomic.Int32) context.Context {
+	return context.WithValue(ctx, contextKeyLogLevel, level)
+}
+
+// logContextf logs a message if the log level of the service serving the
+// request enables it. Outside a service, info and above are logged.
+func logContextf(ctx context.Context, level LogLevel, format string, args ...interface{}) {
+	active, _ := ctx.Value(contextKeyLogLevel).(*atomic.Int32)
+	logAt(active, level, format, args...)
+}
+
+// logAt logs a message if level is at least the active level
+func logAt(active *atomic.Int32, level LogLevel, format string, args ...interface{}) {
+	threshold := LogLevelInfo
+	if active != nil {
+		threshold = LogLevel(active.Load())
+	}
+	if level < threshold {
+		return
+	}
+	log.Printf(format, args...)
+}
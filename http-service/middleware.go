@@ -3,7 +3,6 @@ package httpservice
 import (
 	"context"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
@@ -31,7 +30,7 @@ func Recovery() Middleware {
 			defer func() {
 				if r := recover(); r != nil {
 					err = InternalServerErrorf("panic recovered: %v", r)
-					log.Printf("PANIC: %v", r)
+					logContextf(ctx, LogLevelError, "PANIC: %v", r)
 				}
 			}()
 			return next(ctx)
@@ -67,9 +66,9 @@ func Logger() Middleware {
 			)
 
 			if err != nil {
-				log.Printf("%s - ERROR: %v", logMsg, err)
+				logContextf(ctx, LogLevelError, "%s - ERROR: %v", logMsg, err)
 			} else {
-				log.Println(logMsg)
+				logContextf(ctx, LogLevelInfo, "%s", logMsg)
 			}
 
 			return err
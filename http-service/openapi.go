@@ -3,7 +3,9 @@ package httpservice
 import (
 	"encoding/json"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // OpenAPISpec represents an OpenAPI 3.0 specification
@@ -49,15 +51,55 @@ func GenerateOpenAPISpec(config *Config, routes []*Route) *OpenAPISpec {
 
 	// Process routes
 	for _, route := range routes {
-		addRouteToSpec(spec, route)
+		// Proxy routes forward arbitrary paths and are documented by their
+		// upstreams; hidden routes are internal
+		if route.matchPrefix || route.hidden {
+			continue
+		}
+		addRouteToSpec(spec, route, versionedPath(route))
 	}
 
 	return spec
 }
 
-// addRouteToSpec adds a route to the OpenAPI spec
-func addRouteToSpec(spec *OpenAPISpec, route *Route) {
-	path := convertPathToOpenAPI(route.Path)
+// GenerateOpenAPISpecForVersion generates an OpenAPI specification for a single
+// API version. It contains the routes of that version plus unversioned routes,
+// with paths relative to the "/v{n}" server URL.
+func GenerateOpenAPISpecForVersion(config *Config, routes []*Route, version int) *OpenAPISpec {
+	prefix := "/v" + strconv.Itoa(version)
+
+	spec := &OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Info: OpenAPIInfo{
+			Title:       config.Title,
+			Description: config.Description,
+			Version:     "v" + strconv.Itoa(version),
+		},
+		Paths: make(map[string]interface{}),
+	}
+
+	spec.Servers = []OpenAPIServer{
+		{
+			URL:         "http://" + config.Addr() + prefix,
+			Description: "Development server",
+		},
+	}
+
+	for _, route := range routes {
+		if route.matchPrefix || route.hidden {
+			continue
+		}
+		if route.Version == version || route.Version == 0 {
+			addRouteToSpec(spec, route, route.Path)
+		}
+	}
+
+	return spec
+}
+
+// addRouteToSpec adds a route to the OpenAPI spec under the given path
+func addRouteToSpec(spec *OpenAPISpec, route *Route, routePath string) {
+	path := convertPathToOpenAPI(routePath)
 
 	// Get or create path item
 	var pathItem map[string]interface{}
@@ -83,7 +125,7 @@ func addRouteToSpec(spec *OpenAPISpec, route *Route) {
 		operation["tags"] = route.Tags
 	}
 
-	if route.Deprecated {
+	if route.Deprecated || (route.apiVersion != nil && route.apiVersion.Deprecated) {
 		operation["deprecated"] = true
 	}
 
@@ -136,6 +178,12 @@ func convertPathToOpenAPI(path string) string {
 	return path
 }
 
+// maxSchemaDepth limits recursion for nested and self-referencing types
+const maxSchemaDepth = 8
+
+// timeType is encoded by encoding/json as an RFC 3339 string
+var timeType = reflect.TypeOf(time.Time{})
+
 // generateSchema generates a JSON schema from a Go type
 func generateSchema(v interface{}) map[string]interface{} {
 	if v == nil {
@@ -144,36 +192,70 @@ func generateSchema(v interface{}) map[string]interface{} {
 		}
 	}
 
-	t := reflect.TypeOf(v)
-	if t.Kind() == reflect.Ptr {
+	return generateTypeSchema(reflect.TypeOf(v), 0)
+}
+
+// generateTypeSchema generates a JSON schema for a Go type, following nested
+// structs, slices and maps the way encoding/json serializes them
+func generateTypeSchema(t reflect.Type, depth int) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
 
-	if t.Kind() != reflect.Struct {
+	if t == timeType {
 		return map[string]interface{}{
-			"type": getJSONType(t),
+			"type":   "string",
+			"format": "date-time",
 		}
 	}
 
+	if t.Kind() == reflect.Interface {
+		// Any JSON value
+		return map[string]interface{}{}
+	}
+
 	schema := map[string]interface{}{
-		"type":       "object",
-		"properties": make(map[string]interface{}),
+		"type": getJSONType(t),
 	}
 
-	properties := schema["properties"].(map[string]interface{})
-	var required []string
+	if depth >= maxSchemaDepth {
+		return schema
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		var required []string
+		addStructProperties(t, depth, properties, &required)
+
+		schema["properties"] = properties
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte is encoded as a base64 string
+			schema["type"] = "string"
+			schema["format"] = "byte"
+			break
+		}
+		schema["items"] = generateTypeSchema(t.Elem(), depth+1)
+	case reflect.Map:
+		schema["additionalProperties"] = generateTypeSchema(t.Elem(), depth+1)
+	}
+
+	return schema
+}
 
+// addStructProperties adds the JSON properties of a struct type, flattening
+// embedded structs like encoding/json
+func addStructProperties(t reflect.Type, depth int, properties map[string]interface{}, required *[]string) {
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
 
-		// Skip unexported fields
-		if !field.IsExported() {
-			continue
-		}
-
 		// Get JSON tag
 		jsonTag := field.Tag.Get("json")
-		if jsonTag == "" || jsonTag == "-" {
+		if jsonTag == "-" {
 			continue
 		}
 
@@ -181,35 +263,49 @@ func generateSchema(v interface{}) map[string]interface{} {
 		parts := strings.Split(jsonTag, ",")
 		fieldName := parts[0]
 
+		if field.Anonymous && fieldName == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				addStructProperties(embedded, depth, properties, required)
+				continue
+			}
+		}
+
+		// Skip unexported fields
+		if !field.IsExported() {
+			continue
+		}
+
+		if fieldName == "" {
+			fieldName = field.Name
+		}
+
 		// Generate field schema
-		fieldSchema := generateFieldSchema(field)
-		properties[fieldName] = fieldSchema
+		properties[fieldName] = generateFieldSchema(field, depth+1)
 
 		// Check if required
 		validateTag := field.Tag.Get("validate")
-		if strings.Contains(validateTag, "required") {
-			required = append(required, fieldName)
+		if hasValidationTag(validateTag, "required") {
+			*required = append(*required, fieldName)
 		}
 	}
-
-	if len(required) > 0 {
-		schema["required"] = required
-	}
-
-	return schema
 }
 
 // generateFieldSchema generates schema for a struct field
-func generateFieldSchema(field reflect.StructField) map[string]interface{} {
-	schema := make(map[string]interface{})
-
-	fieldType := field.Type
-	if fieldType.Kind() == reflect.Ptr {
-		fieldType = fieldType.Elem()
+func generateFieldSchema(field reflect.StructField, depth int) map[string]interface{} {
+	schema := generateTypeSchema(field.Type, depth)
+
+	// nil pointers, slices and maps are encoded as null
+	switch field.Type.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		if schema["format"] != "byte" {
+			schema["nullable"] = true
+		}
 	}
 
-	schema["type"] = getJSONType(fieldType)
-
 	// Add validation constraints from validate tag
 	validateTag := field.Tag.Get("validate")
 	if validateTag != "" {
@@ -219,6 +315,19 @@ func generateFieldSchema(field reflect.StructField) map[string]interface{} {
 	return schema
 }
 
+// hasValidationTag checks if a validate tag contains the given rule before any dive
+func hasValidationTag(validateTag, rule string) bool {
+	for _, part := range strings.Split(validateTag, ",") {
+		if part == "dive" {
+			return false
+		}
+		if part == rule {
+			return true
+		}
+	}
+	return false
+}
+
 // getJSONType returns the JSON type for a Go type
 func getJSONType(t reflect.Type) string {
 	switch t.Kind() {
@@ -240,22 +349,52 @@ func getJSONType(t reflect.Type) string {
 	}
 }
 
-// addValidationConstraints adds validation constraints to schema
+// addValidationConstraints adds validation constraints to schema. Rules after
+// "dive" apply to the items of an array.
 func addValidationConstraints(schema map[string]interface{}, validateTag string) {
 	parts := strings.Split(validateTag, ",")
-	for _, part := range parts {
-		kv := strings.Split(part, "=")
-		key := kv[0]
+	for i, part := range parts {
+		if part == "dive" {
+			if items, ok := schema["items"].(map[string]interface{}); ok {
+				addValidationConstraints(items, strings.Join(parts[i+1:], ","))
+			}
+			return
+		}
+
+		key, value, _ := strings.Cut(part, "=")
 
 		switch key {
-		case "min":
-			if len(kv) > 1 {
-				schema["minLength"] = kv[1]
+		case "min", "max", "len":
+			addLengthConstraint(schema, key, value)
+		case "gt", "gte", "lt", "lte":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			if key == "gt" || key == "gte" {
+				schema["minimum"] = n
+				if key == "gt" {
+					schema["exclusiveMinimum"] = true
+				}
+			} else {
+				schema["maximum"] = n
+				if key == "lt" {
+					schema["exclusiveMaximum"] = true
+				}
 			}
-		case "max":
-			if len(kv) > 1 {
-				schema["maxLength"] = kv[1]
+		case "oneof":
+			values := strings.Fields(value)
+			enum := make([]interface{}, 0, len(values))
+			for _, v := range values {
+				if schema["type"] == "integer" || schema["type"] == "number" {
+					if n, err := strconv.ParseFloat(v, 64); err == nil {
+						enum = append(enum, n)
+						continue
+					}
+				}
+				enum = append(enum, v)
 			}
+			schema["enum"] = enum
 		case "email":
 			schema["format"] = "email"
 		case "url":
@@ -266,6 +405,44 @@ func addValidationConstraints(schema map[string]interface{}, validateTag string)
 	}
 }
 
+// addLengthConstraint maps min/max/len to the keyword matching the schema type
+func addLengthConstraint(schema map[string]interface{}, rule, value string) {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return
+	}
+
+	var minKey, maxKey string
+	switch schema["type"] {
+	case "string":
+		minKey, maxKey = "minLength", "maxLength"
+	case "array":
+		minKey, maxKey = "minItems", "maxItems"
+	case "object":
+		minKey, maxKey = "minProperties", "maxProperties"
+	case "integer", "number":
+		minKey, maxKey = "minimum", "maximum"
+	default:
+		return
+	}
+
+	isNumber := minKey == "minimum"
+	var v interface{} = int(n)
+	if isNumber {
+		v = n
+	}
+
+	switch rule {
+	case "min":
+		schema[minKey] = v
+	case "max":
+		schema[maxKey] = v
+	case "len":
+		schema[minKey] = v
+		schema[maxKey] = v
+	}
+}
+
 // MarshalOpenAPISpec marshals the spec to JSON
 func MarshalOpenAPISpec(spec *OpenAPISpec) ([]byte, error) {
 	return json.MarshalIndent(spec, "", "  ")
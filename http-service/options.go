@@ -1,6 +1,9 @@
 package httpservice
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // Option is a functional option for configuring the service
 type Option func(*Config)
@@ -148,9 +151,73 @@ func WithRateLimiting(enable bool, requests int, window time.Duration) Option {
 	}
 }
 
+// WithDefaultAPIVersion sets the API version used when a request does not specify one
+func WithDefaultAPIVersion(version int) Option {
+	return func(c *Config) {
+		c.DefaultAPIVersion = version
+	}
+}
+
+// WithSecurity enables or disables the security headers middleware
+func WithSecurity(enable bool) Option {
+	return func(c *Config) {
+		c.EnableSecurity = enable
+	}
+}
+
+// WithSecurityConfig sets the security header configuration
+func WithSecurityConfig(security SecurityConfig) Option {
+	return func(c *Config) {
+		c.Security = security
+	}
+}
+
+// WithJSONOptions sets the JSON request body parsing options
+func WithJSONOptions(opts JSONOptions) Option {
+	return func(c *Config) {
+		c.JSON = opts
+	}
+}
+
+// WithLogLevel sets the log level (debug, info, warn, error)
+func WithLogLevel(level string) Option {
+	return func(c *Config) {
+		c.LogLevel = level
+	}
+}
+
 // WithDebug enables or disables debug mode
 func WithDebug(enable bool) Option {
 	return func(c *Config) {
 		c.EnableDebug = enable
 	}
 }
+
+// WithDebugToken sets the bearer token required for /debug endpoints
+func WithDebugToken(token string) Option {
+	return func(c *Config) {
+		c.DebugToken = token
+	}
+}
+
+// WithDebugAuth sets a custom authorization function for /debug endpoints
+func WithDebugAuth(authFunc func(ctx context.Context) error) Option {
+	return func(c *Config) {
+		c.DebugAuth = authFunc
+	}
+}
+
+// WithContractValidation enables validation of responses against the responses
+// declared with WithResponse. Intended for development and testing.
+func WithContractValidation(enable bool) Option {
+	return func(c *Config) {
+		c.EnableContractValidation = enable
+	}
+}
+
+// WithContractViolationHandler sets the function called for each contract violation
+func WithContractViolationHandler(handler func(violation ContractViolation)) Option {
+	return func(c *Config) {
+		c.OnContractViolation = handler
+	}
+}
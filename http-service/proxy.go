@@ -0,0 +1,562 @@
+package httpservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// LoadBalancing selects how requests are spread across upstreams
+type LoadBalancing string
+
+const (
+	// RoundRobin sends requests to each healthy upstream in turn
+	RoundRobin LoadBalancing = "round_robin"
+
+	// LeastConnections sends requests to the healthy upstream with the fewest in-flight requests
+	LeastConnections LoadBalancing = "least_connections"
+)
+
+// hopByHopHeaders are connection-specific headers that must not be forwarded
+var hopByHopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"TE",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// proxyMethods are the methods a proxy route is registered for
+var proxyMethods = []string{"GET", "HEAD", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"}
+
+// ProxyConfig holds the settings for a reverse proxy route
+type ProxyConfig struct {
+	Upstreams     []string      // Upstream base URLs, e.g. http://users:8080
+	LoadBalancing LoadBalancing // Balancing strategy, defaults to RoundRobin
+	StripPrefix   bool          // Remove the route prefix before forwarding
+	PreserveHost  bool          // Forward the client's Host header instead of the upstream host
+	Timeout       time.Duration // Read/write timeout per upstream request
+	MaxRetries    int           // Retries on another upstream for idempotent methods
+
+	// Passive health checks
+	MaxFails    int           // Consecutive failures before an upstream is marked down
+	FailTimeout time.Duration // How long an upstream stays marked down
+
+	// TrustedProxies lists the IPs or CIDRs of proxies in front of this service.
+	// X-Forwarded-* headers are only extended when the client is one of them;
+	// from any other client they are replaced, so they cannot be spoofed.
+	TrustedProxies []string
+
+	// Header rewriting
+	SetRequestHeaders     map[string]string
+	RemoveRequestHeaders  []string
+	SetResponseHeaders    map[string]string
+	RemoveResponseHeaders []string
+}
+
+// DefaultProxyConfig returns the default proxy configuration for the given upstreams
+func DefaultProxyConfig(upstreams ...string) ProxyConfig {
+	return ProxyConfig{
+		Upstreams:     upstreams,
+		LoadBalancing: RoundRobin,
+		StripPrefix:   true,
+		PreserveHost:  false,
+		Timeout:       30 * time.Second,
+		MaxRetries:    2,
+		MaxFails:      3,
+		FailTimeout:   30 * time.Second,
+	}
+}
+
+// Validate validates the proxy configuration
+func (c *ProxyConfig) Validate() error {
+	if len(c.Upstreams) == 0 {
+		return fmt.Errorf("at least one upstream is required")
+	}
+
+	switch c.LoadBalancing {
+	case "", RoundRobin, LeastConnections:
+	default:
+		return fmt.Errorf("unknown load balancing strategy: %s", c.LoadBalancing)
+	}
+
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("max retries cannot be negative")
+	}
+
+	if c.MaxFails < 0 {
+		return fmt.Errorf("max fails cannot be negative")
+	}
+
+	if _, err := parseTrustedProxies(c.TrustedProxies); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// parseTrustedProxies parses IPs and CIDRs into networks
+func parseTrustedProxies(entries []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// Proxy forwards all requests under prefix to the given upstreams using the
+// default proxy configuration
+func (s *Service) Proxy(prefix string, upstreams ...string) error {
+	return s.ProxyWithConfig(prefix, DefaultProxyConfig(upstreams...))
+}
+
+// ProxyWithConfig forwards all requests under prefix to the configured upstreams.
+// Requests pass through the global and route middleware before being forwarded.
+// Request bodies are buffered; upstream response bodies are streamed.
+func (s *Service) ProxyWithConfig(prefix string, config ProxyConfig, opts ...RouteOption) error {
+	proxy, err := NewReverseProxy(prefix, config)
+	if err != nil {
+		return fmt.Errorf("invalid proxy config: %w", err)
+	}
+
+	for _, method := range proxyMethods {
+		s.addPrefixRoute(method, proxy.prefix, proxy.Handler(), opts...)
+	}
+
+	return nil
+}
+
+// ReverseProxy forwards requests to a set of upstream services
+type ReverseProxy struct {
+	prefix    string
+	config    ProxyConfig
+	upstreams []*upstream
+	trusted   []*net.IPNet
+	next      atomic.Uint64
+}
+
+// upstream is a single proxied backend with its health state
+type upstream struct {
+	scheme   string
+	host     string
+	basePath string
+	client   *fasthttp.HostClient
+	active   atomic.Int64
+
+	mu        sync.Mutex
+	fails     int
+	downUntil time.Time
+}
+
+// NewReverseProxy creates a reverse proxy for the given route prefix
+func NewReverseProxy(prefix string, config ProxyConfig) (*ReverseProxy, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	if config.LoadBalancing == "" {
+		config.LoadBalancing = RoundRobin
+	}
+
+	trusted, err := parseTrustedProxies(config.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := &ReverseProxy{
+		prefix:  "/" + strings.Trim(prefix, "/"),
+		config:  config,
+		trusted: trusted,
+	}
+
+	for _, raw := range config.Upstreams {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid upstream %q: %w", raw, err)
+		}
+
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("invalid upstream %q: scheme must be http or https", raw)
+		}
+
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid upstream %q: missing host", raw)
+		}
+
+		proxy.upstreams = append(proxy.upstreams, &upstream{
+			scheme:   u.Scheme,
+			host:     u.Host,
+			basePath: strings.TrimSuffix(u.Path, "/"),
+			client: &fasthttp.HostClient{
+				Addr:                     hostWithPort(u),
+				IsTLS:                    u.Scheme == "https",
+				ReadTimeout:              config.Timeout,
+				WriteTimeout:             config.Timeout,
+				StreamResponseBody:       true,
+				DisablePathNormalizing:   true,
+				NoDefaultUserAgentHeader: true,
+			},
+		})
+	}
+
+	return proxy, nil
+}
+
+// hostWithPort returns host:port for an upstream URL, adding the default port
+func hostWithPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	if u.Scheme == "https" {
+		return u.Host + ":443"
+	}
+	return u.Host + ":80"
+}
+
+// Handler returns a HandlerFunc that forwards the request to an upstream
+func (p *ReverseProxy) Handler() HandlerFunc {
+	return func(ctx context.Context) error {
+		reqCtx := GetRequestCtx(ctx)
+		if reqCtx == nil {
+			return ErrInvalidRequest
+		}
+
+		attempts := 1
+		if isIdempotentMethod(string(reqCtx.Method())) {
+			attempts += p.config.MaxRetries
+		}
+
+		tried := make(map[*upstream]bool)
+		var lastErr error
+
+		for attempt := 0; attempt < attempts; attempt++ {
+			// Don't retry once the request deadline has passed
+			if ctx.Err() != nil || errors.Is(lastErr, context.DeadlineExceeded) {
+				break
+			}
+
+			u := p.pick(tried)
+			if u == nil {
+				break
+			}
+			tried[u] = true
+
+			resp, err := p.forward(ctx, reqCtx, u)
+			if err == nil && !isUpstreamFailure(resp.StatusCode()) {
+				u.markSuccess()
+				p.writeResponse(reqCtx, resp)
+				return nil
+			}
+
+			if err != nil {
+				// A request that ran out of time says nothing about the upstream
+				if !errors.Is(err, context.DeadlineExceeded) {
+					u.markFailure(p.config.MaxFails, p.config.FailTimeout)
+				}
+				lastErr = err
+				continue
+			}
+
+			u.markFailure(p.config.MaxFails, p.config.FailTimeout)
+
+			// Out of attempts: pass the upstream's error response through
+			if attempt == attempts-1 || len(tried) == len(p.upstreams) {
+				p.writeResponse(reqCtx, resp)
+				return nil
+			}
+
+			lastErr = fmt.Errorf("upstream %s returned %d", u.host, resp.StatusCode())
+			releaseUpstreamResponse(resp)
+		}
+
+		if lastErr == nil && ctx.Err() != nil {
+			lastErr = ctx.Err()
+		}
+		if lastErr == nil {
+			return ServiceUnavailable("No healthy upstream available")
+		}
+		if errors.Is(lastErr, context.DeadlineExceeded) || errors.Is(lastErr, context.Canceled) {
+			return NewHTTPError(fasthttp.StatusGatewayTimeout, "Gateway timeout", lastErr)
+		}
+
+		return NewHTTPError(fasthttp.StatusBadGateway, "Bad gateway", lastErr)
+	}
+}
+
+// pick selects an upstream that is healthy and not yet tried
+func (p *ReverseProxy) pick(tried map[*upstream]bool) *upstream {
+	now := time.Now()
+	candidates := make([]*upstream, 0, len(p.upstreams))
+	for _, u := range p.upstreams {
+		if !tried[u] && u.isHealthy(now) {
+			candidates = append(candidates, u)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	if p.config.LoadBalancing == LeastConnections {
+		best := candidates[0]
+		for _, u := range candidates[1:] {
+			if u.active.Load() < best.active.Load() {
+				best = u
+			}
+		}
+		return best
+	}
+
+	n := p.next.Add(1) - 1
+	return candidates[n%uint64(len(candidates))]
+}
+
+// forward sends the request to an upstream. The caller owns the returned response.
+func (p *ReverseProxy) forward(ctx context.Context, reqCtx *fasthttp.RequestCtx, u *upstream) (*fasthttp.Response, error) {
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+
+	reqCtx.Request.CopyTo(req)
+
+	path := string(reqCtx.Path())
+	if p.config.StripPrefix {
+		// Strip from the path the route matched, so "/v1/api/x" on an "/api"
+		// proxy forwards "/x"
+		path = strings.TrimPrefix(routePath(ctx, reqCtx), strings.TrimSuffix(p.prefix, "/"))
+		if path == "" || path[0] != '/' {
+			path = "/" + path
+		}
+	}
+
+	uri := req.URI()
+	uri.SetScheme(u.scheme)
+	uri.SetHost(u.host)
+	uri.SetPath(u.basePath + path)
+
+	if p.config.PreserveHost {
+		req.UseHostHeader = true
+		req.Header.SetHostBytes(reqCtx.Host())
+	}
+
+	// Headers named in Connection are hop-by-hop as well (RFC 9110 section 7.6.1)
+	for _, value := range reqCtx.Request.Header.PeekAll("Connection") {
+		for _, name := range strings.Split(string(value), ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				req.Header.Del(name)
+			}
+		}
+	}
+	for _, header := range hopByHopHeaders {
+		req.Header.Del(header)
+	}
+
+	setForwardedHeaders(req, reqCtx, p.isTrusted(reqCtx.RemoteIP()))
+
+	if requestID := GetRequestID(ctx); requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
+
+	for _, header := range p.config.RemoveRequestHeaders {
+		req.Header.Del(header)
+	}
+	for key, value := range p.config.SetRequestHeaders {
+		req.Header.Set(key, value)
+	}
+
+	resp := fasthttp.AcquireResponse()
+
+	u.active.Add(1)
+	var err error
+	deadline, hasDeadline := ctx.Deadline()
+	if hasDeadline {
+		err = u.client.DoDeadline(req, resp, deadline)
+	} else {
+		err = u.client.Do(req, resp)
+	}
+	u.active.Add(-1)
+
+	if err != nil {
+		fasthttp.ReleaseResponse(resp)
+		// The timer behind ctx may not have fired yet when fasthttp gives up
+		if hasDeadline && errors.Is(err, fasthttp.ErrTimeout) && !time.Now().Before(deadline) {
+			err = fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+		}
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// isTrusted reports whether ip belongs to a trusted proxy
+func (p *ReverseProxy) isTrusted(ip net.IP) bool {
+	for _, network := range p.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// setForwardedHeaders sets the X-Forwarded-* headers on the upstream request.
+// Headers sent by a trusted proxy are extended; otherwise they are replaced.
+func setForwardedHeaders(req *fasthttp.Request, reqCtx *fasthttp.RequestCtx, trusted bool) {
+	clientIP := reqCtx.RemoteIP().String()
+	if prior := string(reqCtx.Request.Header.Peek("X-Forwarded-For")); prior != "" && trusted {
+		clientIP = prior + ", " + clientIP
+	}
+	req.Header.Set("X-Forwarded-For", clientIP)
+
+	if !trusted || len(reqCtx.Request.Header.Peek("X-Forwarded-Host")) == 0 {
+		req.Header.SetBytesV("X-Forwarded-Host", reqCtx.Host())
+	}
+
+	if !trusted || len(reqCtx.Request.Header.Peek("X-Forwarded-Proto")) == 0 {
+		proto := "http"
+		if reqCtx.IsTLS() {
+			proto = "https"
+		}
+		req.Header.Set("X-Forwarded-Proto", proto)
+	}
+}
+
+// writeResponse copies the upstream response onto the client response, streaming
+// the body. Headers already set by middleware are kept unless the upstream overrides them.
+func (p *ReverseProxy) writeResponse(reqCtx *fasthttp.RequestCtx, resp *fasthttp.Response) {
+	reqCtx.SetStatusCode(resp.StatusCode())
+
+	// Upstream headers replace headers set by middleware, but every value of
+	// a repeated header (Vary, Link, ...) is kept. Cookies are only added.
+	replaced := make(map[string]bool)
+	resp.Header.VisitAll(func(key, value []byte) {
+		k := string(key)
+		if strings.EqualFold(k, "Content-Length") || isHopByHopHeader(k) {
+			return
+		}
+		if !strings.EqualFold(k, "Set-Cookie") && !replaced[strings.ToLower(k)] {
+			replaced[strings.ToLower(k)] = true
+			reqCtx.Response.Header.Del(k)
+		}
+		reqCtx.Response.Header.Add(k, string(value))
+	})
+
+	for _, header := range p.config.RemoveResponseHeaders {
+		reqCtx.Response.Header.Del(header)
+	}
+	for key, value := range p.config.SetResponseHeaders {
+		reqCtx.Response.Header.Set(key, value)
+	}
+
+	if stream := resp.BodyStream(); stream != nil {
+		// The server closes the stream once the body has been written
+		reqCtx.Response.SetBodyStream(&upstreamBody{Reader: stream, resp: resp}, resp.Header.ContentLength())
+		return
+	}
+
+	reqCtx.Response.SetBody(resp.Body())
+	fasthttp.ReleaseResponse(resp)
+}
+
+// upstreamBody releases the upstream response once its body has been streamed
+type upstreamBody struct {
+	io.Reader
+	resp *fasthttp.Response
+}
+
+// Close closes the upstream body stream and releases the response
+func (b *upstreamBody) Close() error {
+	err := b.resp.CloseBodyStream()
+	fasthttp.ReleaseResponse(b.resp)
+	return err
+}
+
+// releaseUpstreamResponse discards an upstream response that will not be forwarded
+func releaseUpstreamResponse(resp *fasthttp.Response) {
+	_ = resp.CloseBodyStream()
+	fasthttp.ReleaseResponse(resp)
+}
+
+// isHealthy checks if the upstream is not marked down
+func (u *upstream) isHealthy(now time.Time) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return !now.Before(u.downUntil)
+}
+
+// markSuccess resets the failure count after a successful request
+func (u *upstream) markSuccess() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.fails = 0
+}
+
+// markFailure records a failed request and marks the upstream down when
+// maxFails consecutive failures are reached
+func (u *upstream) markFailure(maxFails int, failTimeout time.Duration) {
+	if maxFails <= 0 {
+		return
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.fails++
+	if u.fails >= maxFails {
+		u.fails = 0
+		u.downUntil = time.Now().Add(failTimeout)
+	}
+}
+
+// isUpstreamFailure checks if a status code indicates an unavailable upstream
+func isUpstreamFailure(statusCode int) bool {
+	return statusCode == fasthttp.StatusBadGateway ||
+		statusCode == fasthttp.StatusServiceUnavailable ||
+		statusCode == fasthttp.StatusGatewayTimeout
+}
+
+// isIdempotentMethod checks if a request can be safely retried
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
+
+// isHopByHopHeader checks if a header is connection-specific
+func isHopByHopHeader(name string) bool {
+	for _, header := range hopByHopHeaders {
+		if strings.EqualFold(header, name) {
+			return true
+		}
+	}
+	return false
+}
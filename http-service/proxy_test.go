@@ -0,0 +1,400 @@
+package httpservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func newUpstream(t *testing.T, name string, status int) (*httptest.Server, *atomic.Int64) {
+	var hits atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("X-Upstream", name)
+		w.Header().Set("X-Internal", "secret")
+		w.Header().Set("X-Seen-Path", r.URL.Path)
+		w.Header().Set("X-Seen-Query", r.URL.RawQuery)
+		w.Header().Set("X-Seen-Forwarded-For", r.Header.Get("X-Forwarded-For"))
+		w.Header().Set("X-Seen-Forwarded-Host", r.Header.Get("X-Forwarded-Host"))
+		w.Header().Set("X-Seen-Forwarded-Proto", r.Header.Get("X-Forwarded-Proto"))
+		w.Header().Set("X-Seen-Gateway", r.Header.Get("X-Gateway"))
+		w.Header().Set("X-Seen-Secret", r.Header.Get("X-Secret"))
+		w.WriteHeader(status)
+		fmt.Fprintf(w, "hello from %s", name)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func newProxyTestService(t *testing.T) *Service {
+	service, err := New(WithLogger(false))
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	return service
+}
+
+func TestProxyForwardsAndStripsPrefix(t *testing.T) {
+	upstream, _ := newUpstream(t, "a", http.StatusOK)
+
+	service := newProxyTestService(t)
+	config := DefaultProxyConfig(upstream.URL + "/base")
+	config.SetRequestHeaders = map[string]string{"X-Gateway": "http-service"}
+	config.RemoveResponseHeaders = []string{"X-Internal"}
+
+	if err := service.ProxyWithConfig("/api/users", config); err != nil {
+		t.Fatalf("Failed to register proxy: %v", err)
+	}
+
+	reqCtx := serveTestRequest(service, "GET", "/api/users/42?expand=true", nil)
+
+	if reqCtx.Response.StatusCode() != 200 {
+		t.Fatalf("Expected status 200, got %d", reqCtx.Response.StatusCode())
+	}
+
+	if body := string(reqCtx.Response.Body()); body != "hello from a" {
+		t.Errorf("Unexpected body: %s", body)
+	}
+
+	headers := map[string]string{
+		"X-Seen-Path":    "/base/42",
+		"X-Seen-Query":   "expand=true",
+		"X-Seen-Gateway": "http-service",
+	}
+	for header, want := range headers {
+		if got := string(reqCtx.Response.Header.Peek(header)); got != want {
+			t.Errorf("Expected %s '%s', got '%s'", header, want, got)
+		}
+	}
+
+	if got := reqCtx.Response.Header.Peek("X-Seen-Forwarded-For"); len(got) == 0 {
+		t.Error("Expected X-Forwarded-For to be set")
+	}
+
+	if got := reqCtx.Response.Header.Peek("X-Internal"); len(got) != 0 {
+		t.Errorf("Expected X-Internal to be removed, got '%s'", got)
+	}
+
+	// Middleware headers survive the upstream response
+	if got := reqCtx.Response.Header.Peek("X-Request-ID"); len(got) == 0 {
+		t.Error("Expected X-Request-ID from middleware")
+	}
+}
+
+func TestProxyStripsPrefixAfterVersion(t *testing.T) {
+	upstream, _ := newUpstream(t, "a", http.StatusOK)
+
+	service := newProxyTestService(t)
+	if err := service.Proxy("/api", upstream.URL); err != nil {
+		t.Fatalf("Failed to register proxy: %v", err)
+	}
+
+	reqCtx := serveTestRequest(service, "GET", "/v1/api/x", nil)
+	if reqCtx.Response.StatusCode() != 200 {
+		t.Fatalf("Expected status 200, got %d", reqCtx.Response.StatusCode())
+	}
+	if got := string(reqCtx.Response.Header.Peek("X-Seen-Path")); got != "/x" {
+		t.Errorf("Expected upstream path '/x', got '%s'", got)
+	}
+}
+
+func TestProxyRoundRobin(t *testing.T) {
+	upstreamA, hitsA := newUpstream(t, "a", http.StatusOK)
+	upstreamB, hitsB := newUpstream(t, "b", http.StatusOK)
+
+	service := newProxyTestService(t)
+	if err := service.Proxy("/api", upstreamA.URL, upstreamB.URL); err != nil {
+		t.Fatalf("Failed to register proxy: %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		serveTestRequest(service, "GET", "/api/items", nil)
+	}
+
+	if hitsA.Load() != 2 || hitsB.Load() != 2 {
+		t.Errorf("Expected 2 hits each, got a=%d b=%d", hitsA.Load(), hitsB.Load())
+	}
+}
+
+func TestProxyRetriesIdempotentRequests(t *testing.T) {
+	failing, failingHits := newUpstream(t, "failing", http.StatusServiceUnavailable)
+	healthy, _ := newUpstream(t, "healthy", http.StatusOK)
+
+	service := newProxyTestService(t)
+	config := DefaultProxyConfig(failing.URL, healthy.URL)
+	config.MaxFails = 0
+	if err := service.ProxyWithConfig("/api", config); err != nil {
+		t.Fatalf("Failed to register proxy: %v", err)
+	}
+
+	// GET starts on the failing upstream and is retried on the healthy one
+	reqCtx := serveTestRequest(service, "GET", "/api/items", nil)
+	if reqCtx.Response.StatusCode() != 200 {
+		t.Errorf("Expected retried GET to succeed, got %d", reqCtx.Response.StatusCode())
+	}
+
+	// POST lands on the failing upstream next and must not be retried
+	reqCtx = serveTestRequest(service, "POST", "/api/items", nil)
+	if reqCtx.Response.StatusCode() != 503 {
+		t.Errorf("Expected POST to return upstream 503, got %d", reqCtx.Response.StatusCode())
+	}
+
+	if failingHits.Load() != 2 {
+		t.Errorf("Expected failing upstream to be hit twice, got %d", failingHits.Load())
+	}
+}
+
+func TestProxyPassiveHealthCheck(t *testing.T) {
+	failing, failingHits := newUpstream(t, "failing", http.StatusBadGateway)
+	healthy, _ := newUpstream(t, "healthy", http.StatusOK)
+
+	service := newProxyTestService(t)
+	config := DefaultProxyConfig(failing.URL, healthy.URL)
+	config.MaxFails = 1
+	config.FailTimeout = time.Minute
+	if err := service.ProxyWithConfig("/api", config); err != nil {
+		t.Fatalf("Failed to register proxy: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		reqCtx := serveTestRequest(service, "GET", "/api/items", nil)
+		if reqCtx.Response.StatusCode() != 200 {
+			t.Errorf("Request %d: expected status 200, got %d", i, reqCtx.Response.StatusCode())
+		}
+	}
+
+	if failingHits.Load() != 1 {
+		t.Errorf("Expected marked-down upstream to be skipped after one failure, got %d hits", failingHits.Load())
+	}
+}
+
+func TestProxyUnreachableUpstream(t *testing.T) {
+	dead := httptest.NewServer(http.NotFoundHandler())
+	deadURL := dead.URL
+	dead.Close()
+
+	service := newProxyTestService(t)
+	config := DefaultProxyConfig(deadURL)
+	config.Timeout = time.Second
+	if err := service.ProxyWithConfig("/api", config); err != nil {
+		t.Fatalf("Failed to register proxy: %v", err)
+	}
+
+	reqCtx := serveTestRequest(service, "GET", "/api/items", nil)
+	if reqCtx.Response.StatusCode() != 502 {
+		t.Errorf("Expected status 502, got %d", reqCtx.Response.StatusCode())
+	}
+}
+
+func TestProxyLeastConnections(t *testing.T) {
+	proxy, err := NewReverseProxy("/api", ProxyConfig{
+		Upstreams:     []string{"http://a:80", "http://b:80"},
+		LoadBalancing: LeastConnections,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create proxy: %v", err)
+	}
+
+	proxy.upstreams[0].active.Store(5)
+	proxy.upstreams[1].active.Store(1)
+
+	if picked := proxy.pick(map[*upstream]bool{}); picked != proxy.upstreams[1] {
+		t.Errorf("Expected upstream with fewest connections, got %s", picked.host)
+	}
+}
+
+func TestProxySpecificRoutesTakePrecedence(t *testing.T) {
+	upstream, hits := newUpstream(t, "a", http.StatusOK)
+
+	service := newProxyTestService(t)
+	if err := service.Proxy("/", upstream.URL); err != nil {
+		t.Fatalf("Failed to register proxy: %v", err)
+	}
+	service.GET("/local", func(ctx context.Context) (interface{}, error) {
+		return "local", nil
+	})
+
+	serveTestRequest(service, "GET", "/local", nil)
+	serveTestRequest(service, "GET", "/health", nil)
+	if hits.Load() != 0 {
+		t.Errorf("Expected local routes to be served locally, got %d upstream hits", hits.Load())
+	}
+
+	serveTestRequest(service, "GET", "/elsewhere", nil)
+	if hits.Load() != 1 {
+		t.Errorf("Expected unmatched path to be proxied, got %d upstream hits", hits.Load())
+	}
+}
+
+func TestProxyStripsConnectionHeaders(t *testing.T) {
+	upstream, _ := newUpstream(t, "a", http.StatusOK)
+
+	service := newProxyTestService(t)
+	if err := service.Proxy("/api", upstream.URL); err != nil {
+		t.Fatalf("Failed to register proxy: %v", err)
+	}
+
+	reqCtx := serveTestRequest(service, "GET", "/api/items", map[string]string{
+		"Connection": "keep-alive, X-Secret",
+		"X-Secret":   "hop-by-hop",
+	})
+	if reqCtx.Response.StatusCode() != 200 {
+		t.Fatalf("Expected status 200, got %d", reqCtx.Response.StatusCode())
+	}
+	if got := reqCtx.Response.Header.Peek("X-Seen-Secret"); len(got) != 0 {
+		t.Errorf("Expected header named in Connection to be removed, got '%s'", got)
+	}
+}
+
+func TestProxyKeepsRepeatedResponseHeaders(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		w.Header().Add("Vary", "Accept-Encoding")
+		w.Header().Add("Link", "</a>; rel=preload")
+		w.Header().Add("Link", "</b>; rel=preload")
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(upstream.Close)
+
+	service := newProxyTestService(t)
+	if err := service.Proxy("/api", upstream.URL); err != nil {
+		t.Fatalf("Failed to register proxy: %v", err)
+	}
+
+	reqCtx := serveTestRequest(service, "GET", "/api/items", nil)
+	if reqCtx.Response.StatusCode() != 200 {
+		t.Fatalf("Expected status 200, got %d", reqCtx.Response.StatusCode())
+	}
+
+	for _, header := range []string{"Vary", "Link"} {
+		var values []string
+		for _, value := range reqCtx.Response.Header.PeekAll(header) {
+			values = append(values, string(value))
+		}
+		if len(values) != 2 {
+			t.Errorf("Expected 2 %s values, got %q", header, values)
+		}
+	}
+}
+
+func TestProxyForwardedHeadersTrust(t *testing.T) {
+	upstream, _ := newUpstream(t, "a", http.StatusOK)
+
+	spoofed := map[string]string{
+		"Host":              "gateway.local",
+		"X-Forwarded-For":   "1.2.3.4",
+		"X-Forwarded-Host":  "evil.example",
+		"X-Forwarded-Proto": "https",
+	}
+
+	tests := []struct {
+		name    string
+		trusted []string
+		want    map[string]string
+	}{
+		{
+			name: "untrusted client",
+			want: map[string]string{
+				"X-Seen-Forwarded-For":   "0.0.0.0",
+				"X-Seen-Forwarded-Host":  "gateway.local",
+				"X-Seen-Forwarded-Proto": "http",
+			},
+		},
+		{
+			// Test requests have no connection, so the remote IP is 0.0.0.0
+			name:    "trusted proxy",
+			trusted: []string{"0.0.0.0"},
+			want: map[string]string{
+				"X-Seen-Forwarded-For":   "1.2.3.4, 0.0.0.0",
+				"X-Seen-Forwarded-Host":  "evil.example",
+				"X-Seen-Forwarded-Proto": "https",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newProxyTestService(t)
+			config := DefaultProxyConfig(upstream.URL)
+			config.TrustedProxies = tt.trusted
+			if err := service.ProxyWithConfig("/api", config); err != nil {
+				t.Fatalf("Failed to register proxy: %v", err)
+			}
+
+			reqCtx := serveTestRequest(service, "GET", "/api/items", spoofed)
+			for header, want := range tt.want {
+				if got := string(reqCtx.Response.Header.Peek(header)); got != want {
+					t.Errorf("Expected %s '%s', got '%s'", header, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestProxyRequestDeadline(t *testing.T) {
+	var hits atomic.Int64
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		time.Sleep(500 * time.Millisecond)
+	}))
+	t.Cleanup(slow.Close)
+
+	config := DefaultProxyConfig(slow.URL, slow.URL)
+	config.MaxFails = 1
+	proxy, err := NewReverseProxy("/api", config)
+	if err != nil {
+		t.Fatalf("Failed to create proxy: %v", err)
+	}
+
+	reqCtx := &fasthttp.RequestCtx{}
+	reqCtx.Request.SetRequestURI("/api/items")
+	ctx, cancel := context.WithTimeout(SetRequestCtx(context.Background(), reqCtx), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = proxy.Handler()(ctx)
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Errorf("Expected the request deadline to cut the upstream call short, took %v", elapsed)
+	}
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Code != fasthttp.StatusGatewayTimeout {
+		t.Fatalf("Expected 504 error, got %v", err)
+	}
+	if hits.Load() != 1 {
+		t.Errorf("Expected no retry after the deadline, got %d upstream hits", hits.Load())
+	}
+	for _, u := range proxy.upstreams {
+		if !u.isHealthy(time.Now()) {
+			t.Errorf("Expected %s not to be marked down by a client deadline", u.host)
+		}
+	}
+}
+
+func TestProxyConfigValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		config ProxyConfig
+	}{
+		{name: "no upstreams", config: DefaultProxyConfig()},
+		{name: "bad scheme", config: DefaultProxyConfig("ftp://example.com")},
+		{name: "missing host", config: DefaultProxyConfig("http://")},
+		{name: "unknown balancing", config: ProxyConfig{Upstreams: []string{"http://a"}, LoadBalancing: "random"}},
+		{name: "bad trusted proxy", config: ProxyConfig{Upstreams: []string{"http://a"}, TrustedProxies: []string{"10.0.0.0/33"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewReverseProxy("/api", tt.config); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
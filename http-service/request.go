@@ -1,11 +1,20 @@
 package httpservice
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 )
 
+// JSONOptions controls how BindJSON parses request bodies
+type JSONOptions struct {
+	MaxDepth              int  `json:"max_depth"`               // Maximum nesting depth, 0 means unlimited
+	DisallowUnknownFields bool `json:"disallow_unknown_fields"` // Reject fields not present in the target struct
+	RejectDuplicateKeys   bool `json:"reject_duplicate_keys"`   // Reject objects with repeated keys
+}
+
 // BindJSON parses JSON request body into the given struct
 func BindJSON(ctx context.Context, v interface{}) error {
 	reqCtx := GetRequestCtx(ctx)
@@ -25,13 +34,81 @@ func BindJSON(ctx context.Context, v interface{}) error {
 		return BadRequest("Request body is empty")
 	}
 
-	if err := json.Unmarshal(body, v); err != nil {
+	opts := GetJSONOptions(ctx)
+	if opts.MaxDepth > 0 || opts.RejectDuplicateKeys {
+		if err := checkJSONStructure(body, opts); err != nil {
+			return BadRequestf("Invalid JSON: %v", err)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if opts.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	if err := decoder.Decode(v); err != nil {
 		return BadRequestf("Invalid JSON: %v", err)
 	}
 
+	// Reject trailing data after the first JSON value
+	if _, err := decoder.Token(); err != io.EOF {
+		return BadRequest("Invalid JSON: unexpected data after top-level value")
+	}
+
 	return nil
 }
 
+// checkJSONStructure walks the JSON tokens and enforces depth and duplicate key limits
+func checkJSONStructure(body []byte, opts JSONOptions) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+
+	// One entry per open container; objects track their seen keys, arrays hold nil
+	var stack []map[string]struct{}
+	expectKey := false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if delim, ok := token.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				if opts.MaxDepth > 0 && len(stack) >= opts.MaxDepth {
+					return fmt.Errorf("maximum nesting depth of %d exceeded", opts.MaxDepth)
+				}
+				if delim == '{' {
+					stack = append(stack, make(map[string]struct{}))
+				} else {
+					stack = append(stack, nil)
+				}
+			case '}', ']':
+				stack = stack[:len(stack)-1]
+			}
+			expectKey = len(stack) > 0 && stack[len(stack)-1] != nil
+			continue
+		}
+
+		if expectKey {
+			key := token.(string)
+			seen := stack[len(stack)-1]
+			if _, dup := seen[key]; dup && opts.RejectDuplicateKeys {
+				return fmt.Errorf("duplicate key %q", key)
+			}
+			seen[key] = struct{}{}
+			expectKey = false
+			continue
+		}
+
+		// A scalar value; inside an object the next token is a key again
+		expectKey = len(stack) > 0 && stack[len(stack)-1] != nil
+	}
+}
+
 // BindAndValidate parses and validates JSON request body
 func BindAndValidate(ctx context.Context, v interface{}, validator *Validator) error {
 	// Bind JSON
@@ -39,9 +116,9 @@ func BindAndValidate(ctx context.Context, v interface{}, validator *Validator) e
 		return err
 	}
 
-	// Validate if validator is provided
+	// Validate if validator is provided, in the request's locale
 	if validator != nil {
-		if err := validator.Validate(v); err != nil {
+		if err := validator.ValidateCtx(ctx, v); err != nil {
 			return err
 		}
 	}
@@ -338,3 +338,82 @@ func TestBindJSONNilContext(t *testing.T) {
 		t.Error("Expected error when request context is nil")
 	}
 }
+
+func TestBindJSONHardening(t *testing.T) {
+	type Item struct {
+		SKU string `json:"sku"`
+	}
+	type TestRequest struct {
+		Name  string `json:"name"`
+		Items []Item `json:"items"`
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		opts    JSONOptions
+		wantErr bool
+	}{
+		{
+			name: "no options",
+			body: `{"name":"John","name":"Jane","extra":{"a":{"b":1}}}`,
+		},
+		{
+			name: "within max depth",
+			body: `{"name":"John","items":[{"sku":"a"}]}`,
+			opts: JSONOptions{MaxDepth: 3},
+		},
+		{
+			name:    "max depth exceeded",
+			body:    `{"name":"John","items":[{"sku":"a"}]}`,
+			opts:    JSONOptions{MaxDepth: 2},
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			body:    `{"name":"John","extra":true}`,
+			opts:    JSONOptions{DisallowUnknownFields: true},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate key",
+			body:    `{"name":"John","name":"Jane"}`,
+			opts:    JSONOptions{RejectDuplicateKeys: true},
+			wantErr: true,
+		},
+		{
+			name:    "nested duplicate key",
+			body:    `{"name":"John","items":[{"sku":"a","sku":"b"}]}`,
+			opts:    JSONOptions{RejectDuplicateKeys: true},
+			wantErr: true,
+		},
+		{
+			name: "same key in sibling objects",
+			body: `{"name":"John","items":[{"sku":"a"},{"sku":"b"}]}`,
+			opts: JSONOptions{RejectDuplicateKeys: true},
+		},
+		{
+			name:    "trailing data",
+			body:    `{"name":"John"} {"name":"Jane"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqCtx := &fasthttp.RequestCtx{}
+			reqCtx.Request.Header.SetContentType("application/json")
+			reqCtx.Request.SetBodyString(tt.body)
+
+			ctx := SetRequestCtx(context.Background(), reqCtx)
+			ctx = SetJSONOptions(ctx, tt.opts)
+
+			var req TestRequest
+			err := BindJSON(ctx, &req)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BindJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package httpservice
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// SecurityConfig holds the security header settings used by the Secure middleware
+type SecurityConfig struct {
+	// Strict-Transport-Security
+	HSTSMaxAge            int  `json:"hsts_max_age"` // in seconds, 0 disables the header
+	HSTSIncludeSubdomains bool `json:"hsts_include_subdomains"`
+	HSTSPreload           bool `json:"hsts_preload"`
+
+	ContentSecurityPolicy string `json:"content_security_policy"` // Content-Security-Policy
+	FrameOptions          string `json:"frame_options"`           // X-Frame-Options (DENY, SAMEORIGIN)
+	ContentTypeNosniff    bool   `json:"content_type_nosniff"`    // X-Content-Type-Options: nosniff
+	ReferrerPolicy        string `json:"referrer_policy"`         // Referrer-Policy
+	PermissionsPolicy     string `json:"permissions_policy"`      // Permissions-Policy
+}
+
+// DefaultSecurityConfig returns the default security header configuration
+func DefaultSecurityConfig() SecurityConfig {
+	return SecurityConfig{
+		HSTSMaxAge:            31536000, // 1 year
+		HSTSIncludeSubdomains: true,
+		HSTSPreload:           false,
+		ContentSecurityPolicy: "default-src 'self'",
+		FrameOptions:          "DENY",
+		ContentTypeNosniff:    true,
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+		PermissionsPolicy:     "",
+	}
+}
+
+// Secure middleware adds security headers using the default configuration
+func Secure() Middleware {
+	return SecureWithConfig(DefaultSecurityConfig())
+}
+
+// SecureWithConfig middleware adds security headers using the given configuration.
+// Empty values disable the corresponding header.
+func SecureWithConfig(config SecurityConfig) Middleware {
+	hsts := ""
+	if config.HSTSMaxAge > 0 {
+		hsts = fmt.Sprintf("max-age=%d", config.HSTSMaxAge)
+		if config.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if config.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context) error {
+			reqCtx := GetRequestCtx(ctx)
+			header := &reqCtx.Response.Header
+
+			// HSTS is only meaningful over TLS (or behind a TLS-terminating proxy)
+			if hsts != "" && isSecureRequest(reqCtx) {
+				header.Set("Strict-Transport-Security", hsts)
+			}
+
+			if config.ContentSecurityPolicy != "" {
+				header.Set("Content-Security-Policy", config.ContentSecurityPolicy)
+			}
+
+			if config.FrameOptions != "" {
+				header.Set("X-Frame-Options", config.FrameOptions)
+			}
+
+			if config.ContentTypeNosniff {
+				header.Set("X-Content-Type-Options", "nosniff")
+			}
+
+			if config.ReferrerPolicy != "" {
+				header.Set("Referrer-Policy", config.ReferrerPolicy)
+			}
+
+			if config.PermissionsPolicy != "" {
+				header.Set("Permissions-Policy", config.PermissionsPolicy)
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// isSecureRequest checks if the request arrived over TLS
+func isSecureRequest(reqCtx *fasthttp.RequestCtx) bool {
+	if reqCtx.IsTLS() {
+		return true
+	}
+	return strings.EqualFold(string(reqCtx.Request.Header.Peek("X-Forwarded-Proto")), "https")
+}
+
+// CSRFConfig holds the settings for the CSRF middleware
+type CSRFConfig struct {
+	TokenLength    int                            // Number of random bytes in a token
+	CookieName     string                         // Cookie holding the token
+	CookiePath     string                         // Cookie path
+	CookieDomain   string                         // Cookie domain
+	CookieMaxAge   time.Duration                  // Cookie lifetime
+	CookieSecure   bool                           // Send cookie over HTTPS only
+	CookieSameSite fasthttp.CookieSameSite        // SameSite attribute
+	HeaderName     string                         // Request header carrying the submitted token
+	FormField      string                         // Form field carrying the submitted token
+	Skipper        func(ctx context.Context) bool // Skip CSRF checks for a request
+}
+
+// DefaultCSRFConfig returns the default CSRF configuration
+func DefaultCSRFConfig() CSRFConfig {
+	return CSRFConfig{
+		TokenLength:    32,
+		CookieName:     "_csrf",
+		CookiePath:     "/",
+		CookieMaxAge:   24 * time.Hour,
+		CookieSecure:   true,
+		CookieSameSite: fasthttp.CookieSameSiteStrictMode,
+		HeaderName:     "X-CSRF-Token",
+		FormField:      "_csrf",
+	}
+}
+
+// CSRF middleware implements double-submit-cookie CSRF protection using the default configuration
+func CSRF() Middleware {
+	return CSRFWithConfig(DefaultCSRFConfig())
+}
+
+// CSRFWithConfig middleware implements double-submit-cookie CSRF protection.
+// Safe methods receive a token cookie; unsafe methods must echo the cookie
+// value in the configured header or form field.
+func CSRFWithConfig(config CSRFConfig) Middleware {
+	defaults := DefaultCSRFConfig()
+	if config.TokenLength <= 0 {
+		config.TokenLength = defaults.TokenLength
+	}
+	if config.CookieName == "" {
+		config.CookieName = defaults.CookieName
+	}
+	if config.CookiePath == "" {
+		config.CookiePath = defaults.CookiePath
+	}
+	if config.HeaderName == "" {
+		config.HeaderName = defaults.HeaderName
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context) error {
+			if config.Skipper != nil && config.Skipper(ctx) {
+				return next(ctx)
+			}
+
+			reqCtx := GetRequestCtx(ctx)
+
+			token := string(reqCtx.Request.Header.Cookie(config.CookieName))
+			if token == "" {
+				newToken, err := generateCSRFToken(config.TokenLength)
+				if err != nil {
+					return InternalServerError("Failed to generate CSRF token")
+				}
+				token = newToken
+			}
+
+			if !isSafeMethod(string(reqCtx.Method())) {
+				submitted := string(reqCtx.Request.Header.Peek(config.HeaderName))
+				if submitted == "" && config.FormField != "" {
+					submitted = string(reqCtx.FormValue(config.FormField))
+				}
+
+				if submitted == "" {
+					return Forbidden("Missing CSRF token")
+				}
+
+				if subtle.ConstantTimeCompare([]byte(submitted), []byte(token)) != 1 {
+					return Forbidden("Invalid CSRF token")
+				}
+			}
+
+			// Refresh the cookie so its expiry slides with activity
+			cookie := fasthttp.AcquireCookie()
+			defer fasthttp.ReleaseCookie(cookie)
+			cookie.SetKey(config.CookieName)
+			cookie.SetValue(token)
+			cookie.SetPath(config.CookiePath)
+			cookie.SetDomain(config.CookieDomain)
+			cookie.SetSecure(config.CookieSecure)
+			cookie.SetSameSite(config.CookieSameSite)
+			if config.CookieMaxAge > 0 {
+				cookie.SetMaxAge(int(config.CookieMaxAge.Seconds()))
+			}
+			reqCtx.Response.Header.SetCookie(cookie)
+
+			ctx = SetCSRFToken(ctx, token)
+			return next(ctx)
+		}
+	}
+}
+
+// generateCSRFToken generates a random URL-safe token
+func generateCSRFToken(length int) (string, error) {
+	b := make([]byte, length)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// isSafeMethod checks if the HTTP method is safe as defined by RFC 9110
+func isSafeMethod(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "TRACE":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package httpservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestSecureMiddleware(t *testing.T) {
+	middleware := Secure()
+
+	handler := func(ctx context.Context) error {
+		return nil
+	}
+
+	wrappedHandler := middleware(handler)
+
+	reqCtx := &fasthttp.RequestCtx{}
+	ctx := SetRequestCtx(context.Background(), reqCtx)
+
+	if err := wrappedHandler(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"Content-Security-Policy": "default-src 'self'",
+		"X-Frame-Options":         "DENY",
+		"X-Content-Type-Options":  "nosniff",
+		"Referrer-Policy":         "strict-origin-when-cross-origin",
+	}
+	for header, value := range expected {
+		if got := string(reqCtx.Response.Header.Peek(header)); got != value {
+			t.Errorf("Expected %s '%s', got '%s'", header, value, got)
+		}
+	}
+
+	// HSTS must not be sent over plain HTTP
+	if hsts := reqCtx.Response.Header.Peek("Strict-Transport-Security"); len(hsts) != 0 {
+		t.Errorf("Expected no HSTS header over plain HTTP, got '%s'", hsts)
+	}
+}
+
+func TestSecureMiddlewareHSTS(t *testing.T) {
+	config := DefaultSecurityConfig()
+	config.HSTSMaxAge = 600
+	config.HSTSPreload = true
+	config.PermissionsPolicy = "geolocation=()"
+
+	middleware := SecureWithConfig(config)
+
+	handler := func(ctx context.Context) error {
+		return nil
+	}
+
+	reqCtx := &fasthttp.RequestCtx{}
+	reqCtx.Request.Header.Set("X-Forwarded-Proto", "https")
+	ctx := SetRequestCtx(context.Background(), reqCtx)
+
+	if err := middleware(handler)(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	hsts := string(reqCtx.Response.Header.Peek("Strict-Transport-Security"))
+	if hsts != "max-age=600; includeSubDomains; preload" {
+		t.Errorf("Unexpected HSTS header: %s", hsts)
+	}
+
+	if pp := string(reqCtx.Response.Header.Peek("Permissions-Policy")); pp != "geolocation=()" {
+		t.Errorf("Expected Permissions-Policy 'geolocation=()', got '%s'", pp)
+	}
+}
+
+func TestSecureMiddlewareDisabledHeaders(t *testing.T) {
+	middleware := SecureWithConfig(SecurityConfig{})
+
+	handler := func(ctx context.Context) error {
+		return nil
+	}
+
+	reqCtx := &fasthttp.RequestCtx{}
+	ctx := SetRequestCtx(context.Background(), reqCtx)
+
+	if err := middleware(handler)(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, header := range []string{"Content-Security-Policy", "X-Frame-Options", "X-Content-Type-Options", "Referrer-Policy"} {
+		if value := reqCtx.Response.Header.Peek(header); len(value) != 0 {
+			t.Errorf("Expected no %s header, got '%s'", header, value)
+		}
+	}
+}
+
+func TestCSRFMiddlewareSafeMethodIssuesToken(t *testing.T) {
+	middleware := CSRF()
+
+	var token string
+	handler := func(ctx context.Context) error {
+		token = GetCSRFToken(ctx)
+		return nil
+	}
+
+	reqCtx := &fasthttp.RequestCtx{}
+	reqCtx.Request.Header.SetMethod("GET")
+	ctx := SetRequestCtx(context.Background(), reqCtx)
+
+	if err := middleware(handler)(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if token == "" {
+		t.Fatal("Expected CSRF token in context")
+	}
+
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+	cookie.SetKey("_csrf")
+	if !reqCtx.Response.Header.Cookie(cookie) {
+		t.Fatal("Expected CSRF cookie in response")
+	}
+
+	if string(cookie.Value()) != token {
+		t.Errorf("Expected cookie value '%s', got '%s'", token, cookie.Value())
+	}
+}
+
+func TestCSRFMiddlewareUnsafeMethod(t *testing.T) {
+	middleware := CSRF()
+
+	callCount := 0
+	handler := func(ctx context.Context) error {
+		callCount++
+		return nil
+	}
+
+	tests := []struct {
+		name     string
+		cookie   string
+		header   string
+		form     string
+		wantCode int
+	}{
+		{name: "matching header", cookie: "abc123", header: "abc123"},
+		{name: "matching form field", cookie: "abc123", form: "abc123"},
+		{name: "missing token", cookie: "abc123", wantCode: 403},
+		{name: "mismatched token", cookie: "abc123", header: "other", wantCode: 403},
+		{name: "missing cookie", header: "abc123", wantCode: 403},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callCount = 0
+
+			reqCtx := &fasthttp.RequestCtx{}
+			reqCtx.Request.Header.SetMethod("POST")
+			if tt.cookie != "" {
+				reqCtx.Request.Header.SetCookie("_csrf", tt.cookie)
+			}
+			if tt.header != "" {
+				reqCtx.Request.Header.Set("X-CSRF-Token", tt.header)
+			}
+			if tt.form != "" {
+				reqCtx.Request.Header.SetContentType("application/x-www-form-urlencoded")
+				reqCtx.Request.SetBodyString("_csrf=" + tt.form)
+			}
+			ctx := SetRequestCtx(context.Background(), reqCtx)
+
+			err := middleware(handler)(ctx)
+
+			if tt.wantCode == 0 {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				if callCount != 1 {
+					t.Errorf("Expected handler to be called once, got %d", callCount)
+				}
+				return
+			}
+
+			httpErr := GetHTTPError(err)
+			if httpErr == nil || httpErr.Code != tt.wantCode {
+				t.Errorf("Expected %d error, got %v", tt.wantCode, err)
+			}
+			if callCount != 0 {
+				t.Error("Expected handler not to be called")
+			}
+		})
+	}
+}
+
+func TestCSRFMiddlewareSkipper(t *testing.T) {
+	config := DefaultCSRFConfig()
+	config.Skipper = func(ctx context.Context) bool {
+		return Path(ctx) == "/webhook"
+	}
+
+	middleware := CSRFWithConfig(config)
+
+	handler := func(ctx context.Context) error {
+		return nil
+	}
+
+	reqCtx := &fasthttp.RequestCtx{}
+	reqCtx.Request.Header.SetMethod("POST")
+	reqCtx.Request.SetRequestURI("/webhook")
+	ctx := SetRequestCtx(context.Background(), reqCtx)
+
+	if err := middleware(handler)(ctx); err != nil {
+		t.Errorf("Expected skipped request to pass, got %v", err)
+	}
+}
@@ -3,10 +3,11 @@ package httpservice
 import (
 	"context"
 	"fmt"
-	"log"
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/valyala/fasthttp"
 )
@@ -15,15 +16,18 @@ import (
 type Service struct {
 	config    *Config
 	routes    []*Route
+	versions  map[int]*APIVersion
 	validator *Validator
 	server    *fasthttp.Server
 
 	// Middleware
 	globalMiddleware []Middleware
 
-	mu     sync.RWMutex
-	closed bool
-	wg     sync.WaitGroup
+	mu        sync.RWMutex
+	closed    bool
+	wg        sync.WaitGroup
+	startedAt time.Time
+	logLevel  atomic.Int32
 }
 
 // New creates a new HTTP service
@@ -41,11 +45,23 @@ func New(opts ...Option) (*Service, error) {
 	service := &Service{
 		config:           config,
 		routes:           make([]*Route, 0),
+		versions:         make(map[int]*APIVersion),
 		validator:        NewValidator(),
 		globalMiddleware: make([]Middleware, 0),
 		closed:           false,
+		startedAt:        time.Now(),
 	}
 
+	level := LogLevelInfo
+	if config.LogLevel != "" {
+		parsed, err := ParseLogLevel(config.LogLevel)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+		level = parsed
+	}
+	service.SetLogLevel(level)
+
 	// Setup fasthttp server
 	service.server = &fasthttp.Server{
 		Handler:            service.handler,
@@ -82,6 +98,10 @@ func (s *Service) registerBuiltInMiddleware() {
 		s.Use(CORS(s.config))
 	}
 
+	if s.config.EnableSecurity {
+		s.Use(SecureWithConfig(s.config.Security))
+	}
+
 	if s.config.EnableRateLimiting {
 		s.Use(RateLimit(s.config.RateLimitRequests, s.config.RateLimitWindow))
 	}
@@ -100,6 +120,10 @@ func (s *Service) registerBuiltInRoutes() {
 	if s.config.EnableDocs {
 		s.GET("/docs", s.docsHandler())
 	}
+
+	if s.config.EnableDebug {
+		s.registerDebugRoutes()
+	}
 }
 
 // handler is the main fasthttp handler
@@ -127,19 +151,27 @@ func (s *Service) handler(ctx *fasthttp.RequestCtx) {
 		}
 	}
 
-	route := s.findRoute(method, path)
+	route, matchPath, version := s.resolveRoute(ctx, method, path)
 	if route == nil {
 		ctx.SetStatusCode(fasthttp.StatusNotFound)
 		WriteError(ctx, NotFound("Route not found"))
 		return
 	}
 
+	if route.apiVersion != nil {
+		writeVersionHeaders(ctx, route.apiVersion)
+	}
+
 	// Create context
 	reqCtx := context.Background()
 	reqCtx = SetRequestCtx(reqCtx, ctx)
+	reqCtx = SetJSONOptions(reqCtx, s.config.JSON)
+	reqCtx = SetAPIVersion(reqCtx, version)
+	reqCtx = setLogLevelSource(reqCtx, &s.logLevel)
+	reqCtx = setRoutePath(reqCtx, matchPath)
 
 	// Parse path parameters
-	params, _ := ParsePathParams(route.Path, path)
+	params, _ := ParsePathParams(route.Path, matchPath)
 	reqCtx = SetPathParams(reqCtx, params)
 
 	// Apply route-specific middleware
@@ -153,36 +185,23 @@ func (s *Service) handler(ctx *fasthttp.RequestCtx) {
 	if err := handler(reqCtx); err != nil {
 		WriteError(ctx, err)
 	}
-}
-
-// findRoute finds a matching route
-func (s *Service) findRoute(method, path string) *Route {
-	for _, route := range s.routes {
-		if route.Method == method {
-			// Simple exact match for now
-			if route.Path == path {
-				return route
-			}
 
-			// Check if path matches pattern
-			if _, err := ParsePathParams(route.Path, path); err == nil {
-				return route
-			}
-		}
+	if s.config.EnableContractValidation {
+		s.checkResponseContract(route, ctx)
 	}
-	return nil
 }
 
 // hasRouteForPath checks if there's any route (regardless of method) for the given path
 func (s *Service) hasRouteForPath(path string) bool {
+	_, versionedPath, hasPrefix := splitVersionPrefix(path)
+
 	for _, route := range s.routes {
-		// Simple exact match for now
-		if route.Path == path {
+		if routeMatchesPath(route, path) {
 			return true
 		}
 
-		// Check if path matches pattern
-		if _, err := ParsePathParams(route.Path, path); err == nil {
+		// Versioned routes are also reachable under their /v{n} prefix
+		if hasPrefix && routeMatchesPath(route, versionedPath) {
 			return true
 		}
 	}
@@ -254,6 +273,8 @@ func (s *Service) addRoute(method, path string, handler interface{}, opts ...Rou
 		Method:      method,
 		Path:        path,
 		Middlewares: make([]Middleware, 0),
+		handlerName: funcName(handler),
+		handlerType: typeName(handler),
 	}
 
 	// Convert handler to HandlerFunc
@@ -276,11 +297,11 @@ func (s *Service) addRoute(method, path string, handler interface{}, opts ...Rou
 			if isRequestHandler(handlerType) {
 				route.Handler = wrapGenericRequestHandler(handler, s.validator)
 			} else {
-				log.Printf("Warning: unsupported handler type for %s %s", method, path)
+				s.logf(LogLevelWarn, "Warning: unsupported handler type for %s %s", method, path)
 				return
 			}
 		} else {
-			log.Printf("Warning: unsupported handler type for %s %s", method, path)
+			s.logf(LogLevelWarn, "Warning: unsupported handler type for %s %s", method, path)
 			return
 		}
 	}
@@ -290,6 +311,29 @@ func (s *Service) addRoute(method, path string, handler interface{}, opts ...Rou
 		opt(route)
 	}
 
+	if route.Version > 0 {
+		route.apiVersion = s.apiVersion(route.Version)
+	}
+
+	s.routes = append(s.routes, route)
+}
+
+// addPrefixRoute adds a route matching the prefix and every path below it
+func (s *Service) addPrefixRoute(method, prefix string, handler HandlerFunc, opts ...RouteOption) {
+	route := &Route{
+		Method:      method,
+		Path:        prefix,
+		Handler:     handler,
+		Middlewares: make([]Middleware, 0),
+		matchPrefix: true,
+		handlerName: funcName(handler),
+		handlerType: typeName(handler),
+	}
+
+	for _, opt := range opts {
+		opt(route)
+	}
+
 	s.routes = append(s.routes, route)
 }
 
@@ -303,9 +347,9 @@ func (s *Service) Start() error {
 	s.mu.Unlock()
 
 	addr := s.config.Addr()
-	log.Printf("Starting HTTP service on %s", addr)
-	log.Printf("OpenAPI docs: http://%s/docs", addr)
-	log.Printf("Health check: http://%s/health", addr)
+	s.logf(LogLevelInfo, "Starting HTTP service on %s", addr)
+	s.logf(LogLevelInfo, "OpenAPI docs: http://%s/docs", addr)
+	s.logf(LogLevelInfo, "Health check: http://%s/health", addr)
 
 	return s.server.ListenAndServe(addr)
 }
@@ -316,7 +360,7 @@ func (s *Service) StartAsync() error {
 	go func() {
 		defer s.wg.Done()
 		if err := s.Start(); err != nil {
-			log.Printf("Server error: %v", err)
+			s.logf(LogLevelError, "Server error: %v", err)
 		}
 	}()
 	return nil
@@ -332,14 +376,14 @@ func (s *Service) Shutdown() error {
 	s.closed = true
 	s.mu.Unlock()
 
-	log.Println("Shutting down HTTP service...")
+	s.logf(LogLevelInfo, "Shutting down HTTP service...")
 
 	if err := s.server.Shutdown(); err != nil {
 		return fmt.Errorf("failed to shutdown server: %w", err)
 	}
 
 	s.wg.Wait()
-	log.Println("HTTP service shut down successfully")
+	s.logf(LogLevelInfo, "HTTP service shut down successfully")
 
 	return nil
 }
@@ -365,7 +409,12 @@ func (s *Service) healthCheckHandler() HandlerFunc {
 // openAPIHandler returns the OpenAPI spec handler
 func (s *Service) openAPIHandler() HandlerFunc {
 	return func(ctx context.Context) error {
-		spec := GenerateOpenAPISpec(s.config, s.routes)
+		var spec *OpenAPISpec
+		if version := GetAPIVersion(ctx); version > 0 {
+			spec = GenerateOpenAPISpecForVersion(s.config, s.routes, version)
+		} else {
+			spec = GenerateOpenAPISpec(s.config, s.routes)
+		}
 		reqCtx := GetRequestCtx(ctx)
 		return WriteResponse(reqCtx, OK(spec))
 	}
@@ -83,6 +83,15 @@ func TestConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "negative json max depth",
+			config: func() *Config {
+				c := DefaultConfig()
+				c.JSON.MaxDepth = -1
+				return c
+			}(),
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1,16 +1,26 @@
 package httpservice
 
 import (
-	"fmt"
+	"context"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/go-playground/validator/v10"
 )
 
+// DefaultLocale is the locale used when a request does not ask for a supported one
+const DefaultLocale = "en"
+
 // Validator wraps go-playground/validator
 type Validator struct {
 	validate *validator.Validate
+
+	mu            sync.RWMutex
+	messages      map[string]map[string]string // locale -> tag -> message template
+	defaultLocale string
 }
 
 // NewValidator creates a new validator instance
@@ -26,14 +36,37 @@ func NewValidator() *Validator {
 		return name
 	})
 
-	return &Validator{validate: v}
+	messages := make(map[string]map[string]string, len(defaultMessages))
+	for locale, catalog := range defaultMessages {
+		messages[locale] = make(map[string]string, len(catalog))
+		for tag, message := range catalog {
+			messages[locale][tag] = message
+		}
+	}
+
+	return &Validator{
+		validate:      v,
+		messages:      messages,
+		defaultLocale: DefaultLocale,
+	}
 }
 
-// Validate validates a struct
+// Validate validates a struct, reporting errors in the default locale
 func (v *Validator) Validate(i interface{}) error {
+	return v.ValidateWithLocale(i, "")
+}
+
+// ValidateCtx validates a struct, reporting errors in the locale set with
+// SetLocale or else the best match for the request's Accept-Language header
+func (v *Validator) ValidateCtx(ctx context.Context, i interface{}) error {
+	return v.ValidateWithLocale(i, v.requestLocale(ctx))
+}
+
+// ValidateWithLocale validates a struct, reporting errors in the given locale
+func (v *Validator) ValidateWithLocale(i interface{}, locale string) error {
 	if err := v.validate.Struct(i); err != nil {
 		if validationErrors, ok := err.(validator.ValidationErrors); ok {
-			return v.formatValidationErrors(validationErrors)
+			return v.formatValidationErrors(validationErrors, reflect.TypeOf(i), normalizeLocale(locale))
 		}
 		return err
 	}
@@ -41,13 +74,14 @@ func (v *Validator) Validate(i interface{}) error {
 }
 
 // formatValidationErrors converts validator errors to HTTPError
-func (v *Validator) formatValidationErrors(errs validator.ValidationErrors) *HTTPError {
+func (v *Validator) formatValidationErrors(errs validator.ValidationErrors, root reflect.Type, locale string) *HTTPError {
 	var validationErrs []ValidationError
 
 	for _, err := range errs {
+		field := fieldPath(root, err.StructNamespace())
 		validationErrs = append(validationErrs, ValidationError{
-			Field:   err.Field(),
-			Message: v.getErrorMessage(err),
+			Field:   field,
+			Message: v.getErrorMessage(err, field, locale),
 			Tag:     err.Tag(),
 		})
 	}
@@ -62,101 +96,33 @@ func (v *Validator) formatValidationErrors(errs validator.ValidationErrors) *HTT
 	}
 }
 
-// getErrorMessage returns a human-readable error message
-func (v *Validator) getErrorMessage(err validator.FieldError) string {
-	field := err.Field()
-	switch err.Tag() {
-	case "required":
-		return fmt.Sprintf("%s is required", field)
-	case "email":
-		return fmt.Sprintf("%s must be a valid email", field)
-	case "min":
-		return fmt.Sprintf("%s must be at least %s", field, err.Param())
-	case "max":
-		return fmt.Sprintf("%s must be at most %s", field, err.Param())
-	case "len":
-		return fmt.Sprintf("%s must be %s characters long", field, err.Param())
-	case "gte":
-		return fmt.Sprintf("%s must be greater than or equal to %s", field, err.Param())
-	case "lte":
-		return fmt.Sprintf("%s must be less than or equal to %s", field, err.Param())
-	case "gt":
-		return fmt.Sprintf("%s must be greater than %s", field, err.Param())
-	case "lt":
-		return fmt.Sprintf("%s must be less than %s", field, err.Param())
-	case "eqfield":
-		return fmt.Sprintf("%s must be equal to %s", field, err.Param())
-	case "nefield":
-		return fmt.Sprintf("%s must not be equal to %s", field, err.Param())
-	case "url":
-		return fmt.Sprintf("%s must be a valid URL", field)
-	case "uri":
-		return fmt.Sprintf("%s must be a valid URI", field)
-	case "alpha":
-		return fmt.Sprintf("%s must contain only letters", field)
-	case "alphanum":
-		return fmt.Sprintf("%s must contain only letters and numbers", field)
-	case "numeric":
-		return fmt.Sprintf("%s must be numeric", field)
-	case "number":
-		return fmt.Sprintf("%s must be a valid number", field)
-	case "hexadecimal":
-		return fmt.Sprintf("%s must be a valid hexadecimal", field)
-	case "hexcolor":
-		return fmt.Sprintf("%s must be a valid hex color", field)
-	case "rgb":
-		return fmt.Sprintf("%s must be a valid RGB color", field)
-	case "rgba":
-		return fmt.Sprintf("%s must be a valid RGBA color", field)
-	case "hsl":
-		return fmt.Sprintf("%s must be a valid HSL color", field)
-	case "hsla":
-		return fmt.Sprintf("%s must be a valid HSLA color", field)
-	case "e164":
-		return fmt.Sprintf("%s must be a valid E.164 phone number", field)
-	case "uuid":
-		return fmt.Sprintf("%s must be a valid UUID", field)
-	case "uuid3":
-		return fmt.Sprintf("%s must be a valid UUID v3", field)
-	case "uuid4":
-		return fmt.Sprintf("%s must be a valid UUID v4", field)
-	case "uuid5":
-		return fmt.Sprintf("%s must be a valid UUID v5", field)
-	case "isbn":
-		return fmt.Sprintf("%s must be a valid ISBN", field)
-	case "isbn10":
-		return fmt.Sprintf("%s must be a valid ISBN-10", field)
-	case "isbn13":
-		return fmt.Sprintf("%s must be a valid ISBN-13", field)
-	case "json":
-		return fmt.Sprintf("%s must be valid JSON", field)
-	case "jwt":
-		return fmt.Sprintf("%s must be a valid JWT", field)
-	case "latitude":
-		return fmt.Sprintf("%s must be a valid latitude", field)
-	case "longitude":
-		return fmt.Sprintf("%s must be a valid longitude", field)
-	case "ssn":
-		return fmt.Sprintf("%s must be a valid SSN", field)
-	case "ip":
-		return fmt.Sprintf("%s must be a valid IP address", field)
-	case "ipv4":
-		return fmt.Sprintf("%s must be a valid IPv4 address", field)
-	case "ipv6":
-		return fmt.Sprintf("%s must be a valid IPv6 address", field)
-	case "cidr":
-		return fmt.Sprintf("%s must be a valid CIDR", field)
-	case "mac":
-		return fmt.Sprintf("%s must be a valid MAC address", field)
-	case "hostname":
-		return fmt.Sprintf("%s must be a valid hostname", field)
-	case "fqdn":
-		return fmt.Sprintf("%s must be a valid FQDN", field)
-	case "datetime":
-		return fmt.Sprintf("%s must be a valid datetime with format %s", field, err.Param())
-	default:
-		return fmt.Sprintf("%s failed validation for %s", field, err.Tag())
+// getErrorMessage returns a human-readable error message in the given locale.
+// A tag without a message in that locale gets the locale's generic message;
+// the default locale is only used for locales without one.
+func (v *Validator) getErrorMessage(err validator.FieldError, field, locale string) string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if locale == "" {
+		locale = v.defaultLocale
+	}
+
+	message, ok := v.messages[locale][err.Tag()]
+	if !ok {
+		message, ok = v.messages[locale][""]
+	}
+	if !ok {
+		message, ok = v.messages[v.defaultLocale][err.Tag()]
+	}
+	if !ok {
+		message = v.messages[v.defaultLocale][""]
 	}
+
+	return strings.NewReplacer(
+		"{field}", field,
+		"{param}", err.Param(),
+		"{tag}", err.Tag(),
+	).Replace(message)
 }
 
 // RegisterValidation registers a custom validation function
@@ -164,7 +130,202 @@ func (v *Validator) RegisterValidation(tag string, fn validator.Func) error {
 	return v.validate.RegisterValidation(tag, fn)
 }
 
+// RegisterValidationWithMessage registers a custom validation function together
+// with its error message in the default locale
+func (v *Validator) RegisterValidationWithMessage(tag string, fn validator.Func, message string) error {
+	if err := v.validate.RegisterValidation(tag, fn); err != nil {
+		return err
+	}
+	v.RegisterMessage(v.DefaultLocale(), tag, message)
+	return nil
+}
+
+// RegisterMessage sets the error message for a validation tag in a locale. The
+// message may contain {field}, {param} and {tag} placeholders. An empty tag sets
+// the fallback message for tags without a message.
+func (v *Validator) RegisterMessage(locale, tag, message string) {
+	locale = normalizeLocale(locale)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.messages[locale] == nil {
+		v.messages[locale] = make(map[string]string)
+	}
+	v.messages[locale][tag] = message
+}
+
+// RegisterMessages sets several error messages for a locale
+func (v *Validator) RegisterMessages(locale string, messages map[string]string) {
+	for tag, message := range messages {
+		v.RegisterMessage(locale, tag, message)
+	}
+}
+
+// SetDefaultLocale sets the locale used when no supported locale is requested
+func (v *Validator) SetDefaultLocale(locale string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.defaultLocale = normalizeLocale(locale)
+}
+
+// DefaultLocale returns the locale used when no supported locale is requested
+func (v *Validator) DefaultLocale() string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.defaultLocale
+}
+
+// Locales returns the locales that have messages, sorted
+func (v *Validator) Locales() []string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	locales := make([]string, 0, len(v.messages))
+	for locale := range v.messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
 // RegisterStructValidation registers a custom struct-level validation
 func (v *Validator) RegisterStructValidation(fn validator.StructLevelFunc, types ...interface{}) {
 	v.validate.RegisterStructValidation(fn, types...)
 }
+
+// requestLocale returns the locale for a request
+func (v *Validator) requestLocale(ctx context.Context) string {
+	if locale := GetLocale(ctx); locale != "" {
+		return normalizeLocale(locale)
+	}
+
+	reqCtx := GetRequestCtx(ctx)
+	if reqCtx == nil {
+		return ""
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return negotiateLocale(string(reqCtx.Request.Header.Peek("Accept-Language")), v.messages)
+}
+
+// negotiateLocale picks the supported locale with the highest quality from an
+// Accept-Language header. "pt-BR" matches "pt-br" first and then "pt".
+func negotiateLocale(header string, supported map[string]map[string]string) string {
+	best, bestQuality := "", 0.0
+
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = normalizeLocale(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= bestQuality {
+			continue
+		}
+
+		if _, ok := supported[tag]; ok {
+			best, bestQuality = tag, quality
+			continue
+		}
+		if base, _, found := strings.Cut(tag, "-"); found {
+			if _, ok := supported[base]; ok {
+				best, bestQuality = base, quality
+			}
+		}
+	}
+
+	return best
+}
+
+// normalizeLocale lower-cases a language tag and uses "-" as separator
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// fieldPath converts a validator struct namespace such as
+// "Order.Items[2].SKU" into a JSON path such as "items[2].sku", using json
+// tag names and flattening embedded structs like encoding/json
+func fieldPath(root reflect.Type, namespace string) string {
+	segments := strings.Split(namespace, ".")
+	if len(segments) < 2 {
+		return namespace
+	}
+
+	t := root
+	var path strings.Builder
+
+	// The first segment is the name of the root struct
+	for _, segment := range segments[1:] {
+		name, index := segment, ""
+		if i := strings.IndexByte(segment, '['); i >= 0 {
+			name, index = segment[:i], segment[i:]
+		}
+
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		jsonName, embedded := name, false
+		if t != nil && t.Kind() == reflect.Struct {
+			if field, ok := t.FieldByName(name); ok {
+				jsonName, embedded = jsonFieldName(field)
+				t = field.Type
+			} else {
+				t = nil
+			}
+		} else {
+			t = nil
+		}
+
+		if !embedded {
+			if path.Len() > 0 {
+				path.WriteByte('.')
+			}
+			path.WriteString(jsonName)
+		}
+		path.WriteString(index)
+
+		// Each [index] or [key] steps into the element type
+		for n := strings.Count(index, "["); n > 0 && t != nil; n-- {
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			switch t.Kind() {
+			case reflect.Slice, reflect.Array, reflect.Map:
+				t = t.Elem()
+			default:
+				t = nil
+			}
+		}
+	}
+
+	return path.String()
+}
+
+// jsonFieldName returns the JSON name of a struct field and whether encoding/json
+// flattens it into its parent
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "" || name == "-" {
+		t := field.Type
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if field.Anonymous && name == "" && t.Kind() == reflect.Struct {
+			return field.Name, true
+		}
+		return field.Name, false
+	}
+	return name, false
+}
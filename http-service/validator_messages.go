@@ -0,0 +1,120 @@
+package httpservice
+
+// defaultMessages holds the built-in validation messages per locale. Messages
+// may use the {field}, {param} and {tag} placeholders; the "" entry is the
+// fallback for tags without a message. Tags missing from a locale fall back to
+// the default locale.
+var defaultMessages = map[string]map[string]string{
+	"en": {
+		"":            "{field} failed validation for {tag}",
+		"required":    "{field} is required",
+		"email":       "{field} must be a valid email",
+		"min":         "{field} must be at least {param}",
+		"max":         "{field} must be at most {param}",
+		"len":         "{field} must be {param} characters long",
+		"gte":         "{field} must be greater than or equal to {param}",
+		"lte":         "{field} must be less than or equal to {param}",
+		"gt":          "{field} must be greater than {param}",
+		"lt":          "{field} must be less than {param}",
+		"eqfield":     "{field} must be equal to {param}",
+		"nefield":     "{field} must not be equal to {param}",
+		"oneof":       "{field} must be one of: {param}",
+		"url":         "{field} must be a valid URL",
+		"uri":         "{field} must be a valid URI",
+		"alpha":       "{field} must contain only letters",
+		"alphanum":    "{field} must contain only letters and numbers",
+		"numeric":     "{field} must be numeric",
+		"number":      "{field} must be a valid number",
+		"hexadecimal": "{field} must be a valid hexadecimal",
+		"hexcolor":    "{field} must be a valid hex color",
+		"rgb":         "{field} must be a valid RGB color",
+		"rgba":        "{field} must be a valid RGBA color",
+		"hsl":         "{field} must be a valid HSL color",
+		"hsla":        "{field} must be a valid HSLA color",
+		"e164":        "{field} must be a valid E.164 phone number",
+		"uuid":        "{field} must be a valid UUID",
+		"uuid3":       "{field} must be a valid UUID v3",
+		"uuid4":       "{field} must be a valid UUID v4",
+		"uuid5":       "{field} must be a valid UUID v5",
+		"isbn":        "{field} must be a valid ISBN",
+		"isbn10":      "{field} must be a valid ISBN-10",
+		"isbn13":      "{field} must be a valid ISBN-13",
+		"json":        "{field} must be valid JSON",
+		"jwt":         "{field} must be a valid JWT",
+		"latitude":    "{field} must be a valid latitude",
+		"longitude":   "{field} must be a valid longitude",
+		"ssn":         "{field} must be a valid SSN",
+		"ip":          "{field} must be a valid IP address",
+		"ipv4":        "{field} must be a valid IPv4 address",
+		"ipv6":        "{field} must be a valid IPv6 address",
+		"cidr":        "{field} must be a valid CIDR",
+		"mac":         "{field} must be a valid MAC address",
+		"hostname":    "{field} must be a valid hostname",
+		"fqdn":        "{field} must be a valid FQDN",
+		"datetime":    "{field} must be a valid datetime with format {param}",
+	},
+	"tr": {
+		"":         "{field} alanı {tag} doğrulamasını geçemedi",
+		"required": "{field} alanı zorunludur",
+		"email":    "{field} geçerli bir e-posta adresi olmalıdır",
+		"min":      "{field} en az {param} olmalıdır",
+		"max":      "{field} en fazla {param} olmalıdır",
+		"len":      "{field} {param} karakter uzunluğunda olmalıdır",
+		"gte":      "{field} {param} veya daha büyük olmalıdır",
+		"lte":      "{field} {param} veya daha küçük olmalıdır",
+		"gt":       "{field} {param} değerinden büyük olmalıdır",
+		"lt":       "{field} {param} değerinden küçük olmalıdır",
+		"oneof":    "{field} şunlardan biri olmalıdır: {param}",
+		"url":      "{field} geçerli bir URL olmalıdır",
+		"uuid":     "{field} geçerli bir UUID olmalıdır",
+		"numeric":  "{field} sayısal olmalıdır",
+	},
+	"de": {
+		"":         "{field} hat die Prüfung {tag} nicht bestanden",
+		"required": "{field} ist erforderlich",
+		"email":    "{field} muss eine gültige E-Mail-Adresse sein",
+		"min":      "{field} muss mindestens {param} sein",
+		"max":      "{field} darf höchstens {param} sein",
+		"len":      "{field} muss {param} Zeichen lang sein",
+		"gte":      "{field} muss größer oder gleich {param} sein",
+		"lte":      "{field} muss kleiner oder gleich {param} sein",
+		"gt":       "{field} muss größer als {param} sein",
+		"lt":       "{field} muss kleiner als {param} sein",
+		"oneof":    "{field} muss einer der folgenden Werte sein: {param}",
+		"url":      "{field} muss eine gültige URL sein",
+		"uuid":     "{field} muss eine gültige UUID sein",
+		"numeric":  "{field} muss numerisch sein",
+	},
+	"es": {
+		"":         "{field} no superó la validación {tag}",
+		"required": "{field} es obligatorio",
+		"email":    "{field} debe ser un correo electrónico válido",
+		"min":      "{field} debe ser al menos {param}",
+		"max":      "{field} debe ser como máximo {param}",
+		"len":      "{field} debe tener {param} caracteres",
+		"gte":      "{field} debe ser mayor o igual que {param}",
+		"lte":      "{field} debe ser menor o igual que {param}",
+		"gt":       "{field} debe ser mayor que {param}",
+		"lt":       "{field} debe ser menor que {param}",
+		"oneof":    "{field} debe ser uno de: {param}",
+		"url":      "{field} debe ser una URL válida",
+		"uuid":     "{field} debe ser un UUID válido",
+		"numeric":  "{field} debe ser numérico",
+	},
+	"fr": {
+		"":         "{field} n'a pas passé la validation {tag}",
+		"required": "{field} est obligatoire",
+		"email":    "{field} doit être une adresse e-mail valide",
+		"min":      "{field} doit être au moins {param}",
+		"max":      "{field} doit être au plus {param}",
+		"len":      "{field} doit contenir {param} caractères",
+		"gte":      "{field} doit être supérieur ou égal à {param}",
+		"lte":      "{field} doit être inférieur ou égal à {param}",
+		"gt":       "{field} doit être supérieur à {param}",
+		"lt":       "{field} doit être inférieur à {param}",
+		"oneof":    "{field} doit être l'une des valeurs : {param}",
+		"url":      "{field} doit être une URL valide",
+		"uuid":     "{field} doit être un UUID valide",
+		"numeric":  "{field} doit être numérique",
+	},
+}
@@ -0,0 +1,195 @@
+package httpservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/valyala/fasthttp"
+)
+
+type validatorAudit struct {
+	CreatedBy string `json:"created_by" validate:"required"`
+}
+
+type validatorItem struct {
+	SKU      string `json:"sku" validate:"required"`
+	Quantity int    `json:"quantity" validate:"min=1"`
+}
+
+type validatorOrder struct {
+	validatorAudit
+	Customer string            `json:"customer" validate:"required"`
+	Note     string            `validate:"max=5"`
+	Items    []validatorItem   `json:"items" validate:"required,dive"`
+	Labels   map[string]string `json:"labels" validate:"dive,required"`
+	Status   string            `json:"status" validate:"oneof=new paid"`
+}
+
+func validationErrors(t *testing.T, err error) []ValidationError {
+	t.Helper()
+
+	httpErr, ok := err.(*HTTPError)
+	if !ok {
+		t.Fatalf("Expected *HTTPError, got %T: %v", err, err)
+	}
+	errs, ok := httpErr.Details["errors"].([]ValidationError)
+	if !ok {
+		t.Fatalf("Expected validation errors in details, got %v", httpErr.Details)
+	}
+	return errs
+}
+
+func TestValidatorFieldPaths(t *testing.T) {
+	v := NewValidator()
+
+	err := v.Validate(&validatorOrder{
+		Note:   "too long",
+		Items:  []validatorItem{{SKU: "A", Quantity: 1}, {SKU: "B", Quantity: 1}, {Quantity: 0}},
+		Labels: map[string]string{"env": ""},
+		Status: "lost",
+	})
+
+	want := []ValidationError{
+		{Field: "created_by", Message: "created_by is required", Tag: "required"},
+		{Field: "customer", Message: "customer is required", Tag: "required"},
+		{Field: "Note", Message: "Note must be at most 5", Tag: "max"},
+		{Field: "items[2].sku", Message: "items[2].sku is required", Tag: "required"},
+		{Field: "items[2].quantity", Message: "items[2].quantity must be at least 1", Tag: "min"},
+		{Field: "labels[env]", Message: "labels[env] is required", Tag: "required"},
+		{Field: "status", Message: "status must be one of: new paid", Tag: "oneof"},
+	}
+
+	got := validationErrors(t, err)
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d errors, got %d: %+v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Error %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestValidatorLocales(t *testing.T) {
+	type request struct {
+		Email string `json:"email" validate:"required,email"`
+		Code  string `json:"code" validate:"startswith=#"`
+	}
+
+	v := NewValidator()
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		locale         string
+		want           []string
+	}{
+		{
+			name: "default locale",
+			want: []string{"email is required", "code failed validation for startswith"},
+		},
+		{
+			name:           "exact match",
+			acceptLanguage: "de",
+			want:           []string{"email ist erforderlich", "code hat die Prüfung startswith nicht bestanden"},
+		},
+		{
+			name:           "base language with quality",
+			acceptLanguage: "ja;q=0.9, tr-TR;q=0.8, en;q=0.5",
+			want:           []string{"email alanı zorunludur", "code alanı startswith doğrulamasını geçemedi"},
+		},
+		{
+			name:           "unsupported language",
+			acceptLanguage: "ja",
+			want:           []string{"email is required", "code failed validation for startswith"},
+		},
+		{
+			name:           "context locale wins",
+			acceptLanguage: "de",
+			locale:         "es",
+			want:           []string{"email es obligatorio", "code no superó la validación startswith"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqCtx := &fasthttp.RequestCtx{}
+			if tt.acceptLanguage != "" {
+				reqCtx.Request.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			ctx := SetRequestCtx(context.Background(), reqCtx)
+			if tt.locale != "" {
+				ctx = SetLocale(ctx, tt.locale)
+			}
+
+			errs := validationErrors(t, v.ValidateCtx(ctx, &request{Code: "blue"}))
+			if len(errs) != len(tt.want) {
+				t.Fatalf("Expected %d errors, got %+v", len(tt.want), errs)
+			}
+			for i, want := range tt.want {
+				if errs[i].Message != want {
+					t.Errorf("Message %d = %q, want %q", i, errs[i].Message, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidatorCustomMessages(t *testing.T) {
+	type request struct {
+		Username string `json:"username" validate:"required,nospace"`
+		Age      int    `json:"age" validate:"gte=18"`
+	}
+
+	v := NewValidator()
+
+	err := v.RegisterValidationWithMessage("nospace", func(fl validator.FieldLevel) bool {
+		for _, r := range fl.Field().String() {
+			if r == ' ' {
+				return false
+			}
+		}
+		return true
+	}, "{field} must not contain spaces")
+	if err != nil {
+		t.Fatalf("Failed to register validation: %v", err)
+	}
+
+	v.RegisterMessage("tr", "nospace", "{field} boşluk içeremez")
+	v.RegisterMessages("pt_BR", map[string]string{
+		"gte": "{field} deve ser maior ou igual a {param}",
+	})
+
+	input := &request{Username: "john doe", Age: 16}
+
+	tests := []struct {
+		locale string
+		want   []string
+	}{
+		{locale: "", want: []string{"username must not contain spaces", "age must be greater than or equal to 18"}},
+		{locale: "tr", want: []string{"username boşluk içeremez", "age 18 veya daha büyük olmalıdır"}},
+		{locale: "de", want: []string{"username hat die Prüfung nospace nicht bestanden", "age muss größer oder gleich 18 sein"}},
+		{locale: "pt-br", want: []string{"username must not contain spaces", "age deve ser maior ou igual a 18"}},
+	}
+
+	for _, tt := range tests {
+		errs := validationErrors(t, v.ValidateWithLocale(input, tt.locale))
+		for i, want := range tt.want {
+			if errs[i].Message != want {
+				t.Errorf("[%s] message %d = %q, want %q", tt.locale, i, errs[i].Message, want)
+			}
+		}
+	}
+
+	v.SetDefaultLocale("tr")
+	errs := validationErrors(t, v.Validate(input))
+	if errs[0].Message != "username boşluk içeremez" {
+		t.Errorf("Expected default locale tr, got %q", errs[0].Message)
+	}
+
+	locales := v.Locales()
+	if len(locales) != 6 || locales[4] != "pt-br" {
+		t.Errorf("Unexpected locales %v", locales)
+	}
+}
@@ -0,0 +1,278 @@
+package httpservice
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// APIVersionHeader is the request/response header carrying the API version
+const APIVersionHeader = "API-Version"
+
+// acceptVersionPattern matches vendor media types such as application/vnd.acme.v2+json
+var acceptVersionPattern = regexp.MustCompile(`^application/vnd\.[a-zA-Z0-9._-]*?\.?v(\d+)(\+json)?$`)
+
+// APIVersion describes a registered API version
+type APIVersion struct {
+	Version    int
+	Deprecated bool
+	Sunset     time.Time // Zero means no sunset date
+	Link       string    // Documentation link for the deprecation, sent as Link rel="deprecation"
+}
+
+// VersionOption is a function option for configuring API versions
+type VersionOption func(*APIVersion)
+
+// WithVersionDeprecated marks an API version as deprecated
+func WithVersionDeprecated() VersionOption {
+	return func(v *APIVersion) {
+		v.Deprecated = true
+	}
+}
+
+// WithVersionSunset sets the date after which an API version will be removed.
+// Setting a sunset date also marks the version as deprecated.
+func WithVersionSunset(sunset time.Time) VersionOption {
+	return func(v *APIVersion) {
+		v.Deprecated = true
+		v.Sunset = sunset
+	}
+}
+
+// WithVersionLink sets the documentation link for a deprecated API version
+func WithVersionLink(link string) VersionOption {
+	return func(v *APIVersion) {
+		v.Link = link
+	}
+}
+
+// WithAPIVersion assigns a route to an API version
+func WithAPIVersion(version int) RouteOption {
+	return func(r *Route) {
+		r.Version = version
+	}
+}
+
+// VersionGroup registers routes under a single API version
+type VersionGroup struct {
+	service *Service
+	version int
+}
+
+// Version returns a route group for the given API version, applying any options
+// to the version. Calling Version again for the same version updates its options.
+func (s *Service) Version(version int, opts ...VersionOption) *VersionGroup {
+	v := s.apiVersion(version)
+	for _, opt := range opts {
+		opt(v)
+	}
+	return &VersionGroup{service: s, version: version}
+}
+
+// APIVersions returns the registered API versions in ascending order
+func (s *Service) APIVersions() []*APIVersion {
+	versions := make([]*APIVersion, 0, len(s.versions))
+	for _, v := range s.versions {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version < versions[j].Version
+	})
+	return versions
+}
+
+// apiVersion gets or creates the APIVersion entry for a version number
+func (s *Service) apiVersion(version int) *APIVersion {
+	if v, ok := s.versions[version]; ok {
+		return v
+	}
+	v := &APIVersion{Version: version}
+	s.versions[version] = v
+	return v
+}
+
+// GET registers a GET route for this version
+func (g *VersionGroup) GET(path string, handler interface{}, opts ...RouteOption) {
+	g.service.addRoute("GET", path, handler, g.routeOptions(opts)...)
+}
+
+// POST registers a POST route for this version
+func (g *VersionGroup) POST(path string, handler interface{}, opts ...RouteOption) {
+	g.service.addRoute("POST", path, handler, g.routeOptions(opts)...)
+}
+
+// PUT registers a PUT route for this version
+func (g *VersionGroup) PUT(path string, handler interface{}, opts ...RouteOption) {
+	g.service.addRoute("PUT", path, handler, g.routeOptions(opts)...)
+}
+
+// DELETE registers a DELETE route for this version
+func (g *VersionGroup) DELETE(path string, handler interface{}, opts ...RouteOption) {
+	g.service.addRoute("DELETE", path, handler, g.routeOptions(opts)...)
+}
+
+// PATCH registers a PATCH route for this version
+func (g *VersionGroup) PATCH(path string, handler interface{}, opts ...RouteOption) {
+	g.service.addRoute("PATCH", path, handler, g.routeOptions(opts)...)
+}
+
+// routeOptions appends the group's version to the given route options
+func (g *VersionGroup) routeOptions(opts []RouteOption) []RouteOption {
+	all := make([]RouteOption, 0, len(opts)+1)
+	all = append(all, opts...)
+	return append(all, WithAPIVersion(g.version))
+}
+
+// resolveRoute finds the route for a request, honouring the API version requested
+// by path prefix, API-Version header or Accept media type. It returns the route,
+// the path used for parameter matching and the resolved version.
+func (s *Service) resolveRoute(ctx *fasthttp.RequestCtx, method, path string) (*Route, string, int) {
+	if version, rest, ok := splitVersionPrefix(path); ok {
+		if route := s.findVersionedRoute(method, rest, version); route != nil {
+			return route, rest, version
+		}
+		// Fall through: the path may be registered literally, e.g. "/v1/legacy"
+	}
+
+	version := requestedAPIVersion(ctx)
+	if version == 0 {
+		version = s.config.DefaultAPIVersion
+	}
+
+	route := s.findVersionedRoute(method, path, version)
+	if route != nil && route.Version != 0 {
+		version = route.Version
+	}
+	return route, path, version
+}
+
+// findVersionedRoute finds a route for the given version. An exact version match
+// wins, then an unversioned route. When no version is requested the highest
+// registered version is used.
+func (s *Service) findVersionedRoute(method, path string, version int) *Route {
+	var unversioned, latest, prefixed *Route
+
+	for _, route := range s.routes {
+		if route.Method != method || !routeMatchesPath(route, path) {
+			continue
+		}
+
+		switch {
+		case route.matchPrefix:
+			// Prefix routes only apply when nothing more specific matches
+			if prefixed == nil || len(route.Path) > len(prefixed.Path) {
+				prefixed = route
+			}
+		case route.Version == 0:
+			if unversioned == nil {
+				unversioned = route
+			}
+		case version != 0 && route.Version == version:
+			return route
+		case version == 0 && (latest == nil || route.Version > latest.Version):
+			latest = route
+		}
+	}
+
+	if unversioned != nil {
+		return unversioned
+	}
+	if latest != nil {
+		return latest
+	}
+	return prefixed
+}
+
+// routeMatchesPath checks if a route's path pattern matches the path
+func routeMatchesPath(route *Route, path string) bool {
+	if route.Path == path {
+		return true
+	}
+	if route.matchPrefix {
+		return strings.HasPrefix(path, strings.TrimSuffix(route.Path, "/")+"/")
+	}
+	_, err := ParsePathParams(route.Path, path)
+	return err == nil
+}
+
+// splitVersionPrefix splits a "/v{n}" prefix off a path
+func splitVersionPrefix(path string) (int, string, bool) {
+	trimmed := strings.TrimPrefix(path, "/")
+	segment, rest, _ := strings.Cut(trimmed, "/")
+
+	version, ok := parseVersion(segment)
+	if !ok || segment[0] != 'v' {
+		return 0, "", false
+	}
+
+	return version, "/" + rest, true
+}
+
+// requestedAPIVersion reads the API version from the API-Version or Accept headers
+func requestedAPIVersion(ctx *fasthttp.RequestCtx) int {
+	if header := string(ctx.Request.Header.Peek(APIVersionHeader)); header != "" {
+		if version, ok := parseVersion(strings.TrimSpace(header)); ok {
+			return version
+		}
+	}
+
+	accept := string(ctx.Request.Header.Peek("Accept"))
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, _, _ := strings.Cut(mediaRange, ";")
+		matches := acceptVersionPattern.FindStringSubmatch(strings.TrimSpace(mediaType))
+		if matches == nil {
+			continue
+		}
+		if version, err := strconv.Atoi(matches[1]); err == nil && version > 0 {
+			return version
+		}
+	}
+
+	return 0
+}
+
+// parseVersion parses "2" or "v2" into a positive version number
+func parseVersion(s string) (int, bool) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+	if s == "" {
+		return 0, false
+	}
+	version, err := strconv.Atoi(s)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// writeVersionHeaders sets the API-Version header and, for deprecated versions,
+// the Deprecation, Sunset and Link headers
+func writeVersionHeaders(ctx *fasthttp.RequestCtx, v *APIVersion) {
+	ctx.Response.Header.Set(APIVersionHeader, strconv.Itoa(v.Version))
+
+	if !v.Deprecated {
+		return
+	}
+
+	ctx.Response.Header.Set("Deprecation", "true")
+
+	if !v.Sunset.IsZero() {
+		ctx.Response.Header.Set("Sunset", v.Sunset.UTC().Format(http.TimeFormat))
+	}
+
+	if v.Link != "" {
+		ctx.Response.Header.Add("Link", "<"+v.Link+`>; rel="deprecation"`)
+	}
+}
+
+// versionedPath returns the public path of a route including its version prefix
+func versionedPath(route *Route) string {
+	if route.Version == 0 {
+		return route.Path
+	}
+	return "/v" + strconv.Itoa(route.Version) + route.Path
+}
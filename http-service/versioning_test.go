@@ -0,0 +1,201 @@
+package httpservice
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func newVersionedTestService(t *testing.T) *Service {
+	service, err := New(WithLogger(false))
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	service.Version(1,
+		WithVersionSunset(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)),
+		WithVersionLink("https://example.com/migrate"),
+	).GET("/users/{id}", func(ctx context.Context) (interface{}, error) {
+		return map[string]string{"version": "1", "id": PathParam(ctx, "id")}, nil
+	})
+
+	service.Version(2).GET("/users/{id}", func(ctx context.Context) (interface{}, error) {
+		return map[string]string{"version": "2", "id": PathParam(ctx, "id")}, nil
+	})
+
+	return service
+}
+
+func serveTestRequest(service *Service, method, uri string, headers map[string]string) *fasthttp.RequestCtx {
+	reqCtx := &fasthttp.RequestCtx{}
+	reqCtx.Request.Header.SetMethod(method)
+	reqCtx.Request.SetRequestURI(uri)
+	for key, value := range headers {
+		reqCtx.Request.Header.Set(key, value)
+	}
+	service.handler(reqCtx)
+	return reqCtx
+}
+
+func TestVersionResolution(t *testing.T) {
+	service := newVersionedTestService(t)
+
+	tests := []struct {
+		name        string
+		uri         string
+		headers     map[string]string
+		wantVersion string
+	}{
+		{name: "path prefix v1", uri: "/v1/users/42", wantVersion: "1"},
+		{name: "path prefix v2", uri: "/v2/users/42", wantVersion: "2"},
+		{name: "header", uri: "/users/42", headers: map[string]string{"API-Version": "1"}, wantVersion: "1"},
+		{name: "header with prefix", uri: "/users/42", headers: map[string]string{"API-Version": "v2"}, wantVersion: "2"},
+		{name: "accept media type", uri: "/users/42", headers: map[string]string{"Accept": "application/vnd.x.v1+json"}, wantVersion: "1"},
+		{name: "no version selects latest", uri: "/users/42", wantVersion: "2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqCtx := serveTestRequest(service, "GET", tt.uri, tt.headers)
+
+			if reqCtx.Response.StatusCode() != 200 {
+				t.Fatalf("Expected status 200, got %d", reqCtx.Response.StatusCode())
+			}
+
+			var result map[string]string
+			if err := json.Unmarshal(reqCtx.Response.Body(), &result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if result["version"] != tt.wantVersion {
+				t.Errorf("Expected version %s, got %s", tt.wantVersion, result["version"])
+			}
+
+			if result["id"] != "42" {
+				t.Errorf("Expected id 42, got %s", result["id"])
+			}
+
+			if got := string(reqCtx.Response.Header.Peek("API-Version")); got != tt.wantVersion {
+				t.Errorf("Expected API-Version header %s, got %s", tt.wantVersion, got)
+			}
+		})
+	}
+}
+
+func TestVersionUnknown(t *testing.T) {
+	service := newVersionedTestService(t)
+
+	reqCtx := serveTestRequest(service, "GET", "/v3/users/42", nil)
+	if reqCtx.Response.StatusCode() != 404 {
+		t.Errorf("Expected status 404, got %d", reqCtx.Response.StatusCode())
+	}
+}
+
+func TestVersionDefault(t *testing.T) {
+	service, err := New(WithLogger(false), WithDefaultAPIVersion(1))
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	service.Version(1).GET("/items", func(ctx context.Context) (interface{}, error) {
+		return GetAPIVersion(ctx), nil
+	})
+	service.Version(2).GET("/items", func(ctx context.Context) (interface{}, error) {
+		return GetAPIVersion(ctx), nil
+	})
+
+	reqCtx := serveTestRequest(service, "GET", "/items", nil)
+	if body := string(reqCtx.Response.Body()); body != "1" {
+		t.Errorf("Expected default version 1, got %s", body)
+	}
+}
+
+func TestVersionDeprecationHeaders(t *testing.T) {
+	service := newVersionedTestService(t)
+
+	reqCtx := serveTestRequest(service, "GET", "/v1/users/1", nil)
+
+	if got := string(reqCtx.Response.Header.Peek("Deprecation")); got != "true" {
+		t.Errorf("Expected Deprecation header 'true', got '%s'", got)
+	}
+
+	if got := string(reqCtx.Response.Header.Peek("Sunset")); got != "Tue, 01 Jan 2030 00:00:00 GMT" {
+		t.Errorf("Unexpected Sunset header: '%s'", got)
+	}
+
+	if got := string(reqCtx.Response.Header.Peek("Link")); got != `<https://example.com/migrate>; rel="deprecation"` {
+		t.Errorf("Unexpected Link header: '%s'", got)
+	}
+
+	reqCtx = serveTestRequest(service, "GET", "/v2/users/1", nil)
+	if got := reqCtx.Response.Header.Peek("Deprecation"); len(got) != 0 {
+		t.Errorf("Expected no Deprecation header on v2, got '%s'", got)
+	}
+}
+
+func TestVersionUnversionedRoutesUnderPrefix(t *testing.T) {
+	service := newVersionedTestService(t)
+
+	reqCtx := serveTestRequest(service, "GET", "/v2/health", nil)
+	if reqCtx.Response.StatusCode() != 200 {
+		t.Errorf("Expected status 200 for unversioned route under prefix, got %d", reqCtx.Response.StatusCode())
+	}
+}
+
+func TestAPIVersions(t *testing.T) {
+	service := newVersionedTestService(t)
+
+	versions := service.APIVersions()
+	if len(versions) != 2 {
+		t.Fatalf("Expected 2 versions, got %d", len(versions))
+	}
+
+	if versions[0].Version != 1 || !versions[0].Deprecated {
+		t.Errorf("Expected deprecated v1 first, got %+v", versions[0])
+	}
+
+	if versions[1].Version != 2 || versions[1].Deprecated {
+		t.Errorf("Expected active v2 second, got %+v", versions[1])
+	}
+}
+
+func TestOpenAPISpecPerVersion(t *testing.T) {
+	service := newVersionedTestService(t)
+
+	full := GenerateOpenAPISpec(service.config, service.routes)
+	for _, path := range []string{"/v1/users/{id}", "/v2/users/{id}", "/health"} {
+		if _, ok := full.Paths[path]; !ok {
+			t.Errorf("Expected path %s in full spec", path)
+		}
+	}
+
+	v1 := GenerateOpenAPISpecForVersion(service.config, service.routes, 1)
+	if v1.Info.Version != "v1" {
+		t.Errorf("Expected info version v1, got %s", v1.Info.Version)
+	}
+
+	item, ok := v1.Paths["/users/{id}"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected /users/{id} in v1 spec")
+	}
+
+	operation := item["get"].(map[string]interface{})
+	if operation["deprecated"] != true {
+		t.Error("Expected v1 operation to be deprecated")
+	}
+
+	// The spec is served per version through the version prefix
+	reqCtx := serveTestRequest(service, "GET", "/v2/openapi.json", nil)
+
+	var spec OpenAPISpec
+	if err := json.Unmarshal(reqCtx.Response.Body(), &spec); err != nil {
+		t.Fatalf("Failed to decode spec: %v", err)
+	}
+
+	if spec.Info.Version != "v2" {
+		t.Errorf("Expected v2 spec, got %s", spec.Info.Version)
+	}
+}
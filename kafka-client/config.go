@@ -66,6 +66,42 @@ type ConsumerConfig struct {
 
 	// MaxProcessingTime is the maximum time to process a message
 	MaxProcessingTime time.Duration
+
+	// ErrorPolicy determines what happens to messages whose handler fails
+	ErrorPolicy ErrorPolicy
+}
+
+// ErrorPolicy determines what happens to a message whose handler returns an
+// error. A failed message is first retried in place, then published to the
+// next retry topic, then to the dead-letter topic. Once none is left the
+// message is dropped. Every message is committed after its outcome is known.
+type ErrorPolicy struct {
+	// MaxRetries is the number of in-place retries before the message is
+	// forwarded; 0 disables in-place retries
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first in-place retry, doubled for
+	// each further retry
+	RetryBackoff time.Duration
+
+	// MaxRetryBackoff caps the in-place retry delay and the delay between
+	// attempts to publish to a retry or dead-letter topic
+	MaxRetryBackoff time.Duration
+
+	// RetryTopicDelays enables non-blocking retry topics, one per delay.
+	// A message that fails on topic "orders" is published to "orders.retry.1"
+	// and consumed again once the first delay has passed, then to
+	// "orders.retry.2" and so on. Retry topics are subscribed automatically
+	// and must exist unless the brokers create topics on demand.
+	RetryTopicDelays []time.Duration
+
+	// DeadLetterTopic receives messages that failed every retry, with the
+	// original topic, partition, offset and error in headers. "{topic}" is
+	// replaced by the original topic, e.g. "{topic}.dlq". Empty disables it.
+	DeadLetterTopic string
+
+	// OnOutcome is called once for every message with its outcome (optional)
+	OnOutcome OutcomeHandler
 }
 
 // ProducerConfig holds producer-specific configuration
@@ -132,6 +168,10 @@ func DefaultConfig() *Config {
 			AutoCommit:         true,
 			AutoCommitInterval: 1 * time.Second,
 			MaxProcessingTime:  5 * time.Minute,
+			ErrorPolicy: ErrorPolicy{
+				RetryBackoff:    100 * time.Millisecond,
+				MaxRetryBackoff: 10 * time.Second,
+			},
 		},
 		Producer: ProducerConfig{
 			RequiredAcks:     -1, // WaitForAll
@@ -173,6 +213,9 @@ func (c *Config) Validate() error {
 		if c.Consumer.SessionTimeout <= 0 {
 			return fmt.Errorf("consumer session timeout must be positive")
 		}
+		if err := c.Consumer.ErrorPolicy.validate(); err != nil {
+			return err
+		}
 	}
 
 	// Validate producer config
@@ -187,6 +230,25 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// validate validates the error policy
+func (p *ErrorPolicy) validate() error {
+	if p.MaxRetries < 0 {
+		return fmt.Errorf("max retries cannot be negative")
+	}
+	if p.RetryBackoff < 0 {
+		return fmt.Errorf("retry backoff cannot be negative")
+	}
+	if p.MaxRetryBackoff < 0 {
+		return fmt.Errorf("max retry backoff cannot be negative")
+	}
+	for i, delay := range p.RetryTopicDelays {
+		if delay < 0 {
+			return fmt.Errorf("retry topic %d delay cannot be negative", i+1)
+		}
+	}
+	return nil
+}
+
 // ToSaramaConfig converts our config to Sarama config
 func (c *Config) ToSaramaConfig() (*sarama.Config, error) {
 	config := sarama.NewConfig()
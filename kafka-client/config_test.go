@@ -123,6 +123,44 @@ func TestConfig_FullValidation(t *testing.T) {
 			wantError: true,
 			errorMsg:  "retry max cannot be negative",
 		},
+		{
+			name: "consumer with negative max retries",
+			config: &Config{
+				Brokers:  []string{"localhost:9092"},
+				ClientID: "test",
+				Timeout:  30 * time.Second,
+				Consumer: ConsumerConfig{
+					GroupID:        "group",
+					Topics:         []string{"orders"},
+					SessionTimeout: 10 * time.Second,
+					ErrorPolicy:    ErrorPolicy{MaxRetries: -1},
+				},
+				Producer: ProducerConfig{
+					MaxMessageBytes: 1000000,
+				},
+			},
+			wantError: true,
+			errorMsg:  "max retries cannot be negative",
+		},
+		{
+			name: "consumer with negative retry topic delay",
+			config: &Config{
+				Brokers:  []string{"localhost:9092"},
+				ClientID: "test",
+				Timeout:  30 * time.Second,
+				Consumer: ConsumerConfig{
+					GroupID:        "group",
+					Topics:         []string{"orders"},
+					SessionTimeout: 10 * time.Second,
+					ErrorPolicy:    ErrorPolicy{RetryTopicDelays: []time.Duration{time.Second, -time.Second}},
+				},
+				Producer: ProducerConfig{
+					MaxMessageBytes: 1000000,
+				},
+			},
+			wantError: true,
+			errorMsg:  "retry topic 2 delay cannot be negative",
+		},
 	}
 
 	for _, tt := range tests {
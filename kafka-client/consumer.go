@@ -3,6 +3,7 @@ package kafkaclient
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
 
 	"github.com/IBM/sarama"
@@ -13,6 +14,7 @@ type Consumer struct {
 	consumerGroup sarama.ConsumerGroup
 	config        *Config
 	handler       *consumerGroupHandler
+	producer      *Producer // publishes to retry and dead-letter topics, nil if unused
 	mu            sync.RWMutex
 	closed        bool
 	ctx           context.Context
@@ -29,6 +31,7 @@ type ConsumedMessage struct {
 	Value     []byte
 	Headers   map[string]string
 	Timestamp int64
+	Attempt   int // handler attempt, starting at 1 and counting in-place retries and retry topics
 }
 
 // MessageHandler is a function type for handling consumed messages
@@ -36,8 +39,10 @@ type MessageHandler func(ctx context.Context, msg *ConsumedMessage) error
 
 // consumerGroupHandler implements sarama.ConsumerGroupHandler
 type consumerGroupHandler struct {
-	handler MessageHandler
-	ready   chan bool
+	handler  MessageHandler
+	config   *Config
+	producer *Producer
+	ready    chan bool
 }
 
 func (h *consumerGroupHandler) Setup(sarama.ConsumerGroupSession) error {
@@ -57,38 +62,39 @@ func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession,
 				return nil
 			}
 
-			// Convert Sarama message to our format
-			headers := make(map[string]string)
-			for _, header := range message.Headers {
-				headers[string(header.Key)] = string(header.Value)
-			}
-
-			msg := &ConsumedMessage{
-				Topic:     message.Topic,
-				Partition: message.Partition,
-				Offset:    message.Offset,
-				Key:       message.Key,
-				Value:     message.Value,
-				Headers:   headers,
-				Timestamp: message.Timestamp.Unix(),
-			}
-
-			// Call user's handler
-			if err := h.handler(session.Context(), msg); err != nil {
-				// Log error but continue processing
-				// In production, you might want to implement error handling strategy
-				continue
+			// Handle the message according to the error policy; stop if the
+			// session ended before it was settled, so no later offset is marked
+			if !h.process(session, message) {
+				return nil
 			}
 
-			// Mark message as processed
-			session.MarkMessage(message, "")
-
 		case <-session.Context().Done():
 			return nil
 		}
 	}
 }
 
+// newConsumedMessage converts a Sarama message to our format
+func newConsumedMessage(message *sarama.ConsumerMessage) *ConsumedMessage {
+	headers := make(map[string]string)
+	for _, header := range message.Headers {
+		headers[string(header.Key)] = string(header.Value)
+	}
+
+	attempts, _ := strconv.Atoi(headers[HeaderAttempts])
+
+	return &ConsumedMessage{
+		Topic:     message.Topic,
+		Partition: message.Partition,
+		Offset:    message.Offset,
+		Key:       message.Key,
+		Value:     message.Value,
+		Headers:   headers,
+		Timestamp: message.Timestamp.Unix(),
+		Attempt:   attempts + 1,
+	}
+}
+
 // NewConsumer creates a new Kafka consumer
 func NewConsumer(config *Config, handler MessageHandler) (*Consumer, error) {
 	if err := config.Validate(); err != nil {
@@ -108,8 +114,20 @@ func NewConsumer(config *Config, handler MessageHandler) (*Consumer, error) {
 		return nil, fmt.Errorf("failed to create sarama config: %w", err)
 	}
 
+	// Failed messages are published to retry and dead-letter topics
+	var producer *Producer
+	if config.Consumer.ErrorPolicy.forwards() {
+		producer, err = NewProducer(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create retry producer: %w", err)
+		}
+	}
+
 	consumerGroup, err := sarama.NewConsumerGroup(config.Brokers, config.Consumer.GroupID, saramaConfig)
 	if err != nil {
+		if producer != nil {
+			producer.Close()
+		}
 		return nil, fmt.Errorf("%w: %v", ErrConnectionFailed, err)
 	}
 
@@ -118,9 +136,12 @@ func NewConsumer(config *Config, handler MessageHandler) (*Consumer, error) {
 	consumer := &Consumer{
 		consumerGroup: consumerGroup,
 		config:        config,
+		producer:      producer,
 		handler: &consumerGroupHandler{
-			handler: handler,
-			ready:   make(chan bool),
+			handler:  handler,
+			config:   config,
+			producer: producer,
+			ready:    make(chan bool),
 		},
 		closed: false,
 		ctx:    ctx,
@@ -141,8 +162,8 @@ func NewConsumer(config *Config, handler MessageHandler) (*Consumer, error) {
 func (c *Consumer) consume() {
 	defer c.wg.Done()
 
-	// Apply workspace prefix to topics if configured
-	topics := c.config.ApplyWorkspacePrefixToTopics(c.config.Consumer.Topics)
+	// Apply workspace prefix to topics and their retry topics if configured
+	topics := c.config.ApplyWorkspacePrefixToTopics(c.config.Consumer.subscriptions())
 
 	for {
 		// Check if context is cancelled
@@ -187,7 +208,13 @@ func (c *Consumer) Close() error {
 	c.cancel()
 	c.wg.Wait()
 
-	return c.consumerGroup.Close()
+	err := c.consumerGroup.Close()
+	if c.producer != nil {
+		if perr := c.producer.Close(); perr != nil && err == nil {
+			err = perr
+		}
+	}
+	return err
 }
 
 // Errors returns a channel of consumer errors
@@ -64,6 +64,39 @@ func WithAutoCommit(enable bool) Option {
 	}
 }
 
+// WithConsumerRetry retries a failed message in place up to maxRetries
+// times, starting with backoff and doubling it up to maxBackoff
+func WithConsumerRetry(maxRetries int, backoff, maxBackoff time.Duration) Option {
+	return func(c *Config) {
+		c.Consumer.ErrorPolicy.MaxRetries = maxRetries
+		c.Consumer.ErrorPolicy.RetryBackoff = backoff
+		c.Consumer.ErrorPolicy.MaxRetryBackoff = maxBackoff
+	}
+}
+
+// WithRetryTopics enables retry topics "{topic}.retry.1", "{topic}.retry.2", ...
+// where each failed message is consumed again after the matching delay
+func WithRetryTopics(delays ...time.Duration) Option {
+	return func(c *Config) {
+		c.Consumer.ErrorPolicy.RetryTopicDelays = delays
+	}
+}
+
+// WithDeadLetterTopic sets the topic for messages that failed every retry
+// "{topic}" is replaced by the original topic, e.g. "{topic}.dlq"
+func WithDeadLetterTopic(topic string) Option {
+	return func(c *Config) {
+		c.Consumer.ErrorPolicy.DeadLetterTopic = topic
+	}
+}
+
+// WithOutcomeHandler sets the callback called with the outcome of every message
+func WithOutcomeHandler(fn OutcomeHandler) Option {
+	return func(c *Config) {
+		c.Consumer.ErrorPolicy.OnOutcome = fn
+	}
+}
+
 // WithCompression sets the compression codec for producer
 // Options: "none", "gzip", "snappy", "lz4", "zstd"
 func WithCompression(compression string) Option {
@@ -146,6 +146,47 @@ func TestOptions_ConsumerOptions(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name:   "WithConsumerRetry",
+			option: WithConsumerRetry(3, 50*time.Millisecond, 2*time.Second),
+			validate: func(c *Config) error {
+				p := c.Consumer.ErrorPolicy
+				if p.MaxRetries != 3 || p.RetryBackoff != 50*time.Millisecond || p.MaxRetryBackoff != 2*time.Second {
+					return ErrInvalidConfig
+				}
+				return nil
+			},
+		},
+		{
+			name:   "WithRetryTopics",
+			option: WithRetryTopics(time.Minute, 10*time.Minute),
+			validate: func(c *Config) error {
+				if len(c.Consumer.ErrorPolicy.RetryTopicDelays) != 2 || c.Consumer.ErrorPolicy.RetryTopicDelays[1] != 10*time.Minute {
+					return ErrInvalidConfig
+				}
+				return nil
+			},
+		},
+		{
+			name:   "WithDeadLetterTopic",
+			option: WithDeadLetterTopic("{topic}.dlq"),
+			validate: func(c *Config) error {
+				if c.Consumer.ErrorPolicy.DeadLetterTopic != "{topic}.dlq" {
+					return ErrInvalidConfig
+				}
+				return nil
+			},
+		},
+		{
+			name:   "WithOutcomeHandler",
+			option: WithOutcomeHandler(func(*ConsumedMessage, Outcome, error) {}),
+			validate: func(c *Config) error {
+				if c.Consumer.ErrorPolicy.OnOutcome == nil {
+					return ErrInvalidConfig
+				}
+				return nil
+			},
+		},
 	}
 
 	for _, tt := range tests {
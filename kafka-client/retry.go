@@ -0,0 +1,264 @@
+package kafkaclient
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// Headers added to messages published to retry and dead-letter topics
+const (
+	// HeaderOriginalTopic is the topic the message was first consumed from
+	HeaderOriginalTopic = "x-original-topic"
+
+	// HeaderOriginalPartition is the partition the message was first consumed from
+	HeaderOriginalPartition = "x-original-partition"
+
+	// HeaderOriginalOffset is the offset the message was first consumed at
+	HeaderOriginalOffset = "x-original-offset"
+
+	// HeaderError is the error returned by the last failed attempt
+	HeaderError = "x-error"
+
+	// HeaderAttempts is the number of failed handler attempts so far
+	HeaderAttempts = "x-attempts"
+
+	// HeaderRetryLevel is the number of the retry topic the message was sent to
+	HeaderRetryLevel = "x-retry-level"
+
+	// HeaderRetryAt is the time in Unix milliseconds before which a retry
+	// message is not handled
+	HeaderRetryAt = "x-retry-at"
+)
+
+// Outcome is what happened to a consumed message
+type Outcome int
+
+const (
+	// OutcomeSucceeded means the handler succeeded, possibly after in-place retries
+	OutcomeSucceeded Outcome = iota
+
+	// OutcomeRetried means the message was published to a retry topic
+	OutcomeRetried
+
+	// OutcomeDeadLettered means the message was published to the dead-letter topic
+	OutcomeDeadLettered
+
+	// OutcomeDropped means the message failed and no retry or dead-letter
+	// topic was left, so it was skipped
+	OutcomeDropped
+
+	// OutcomeInterrupted means the consumer session ended before the message
+	// was settled; it is not committed and will be consumed again
+	OutcomeInterrupted
+)
+
+// String returns the outcome name
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeSucceeded:
+		return "succeeded"
+	case OutcomeRetried:
+		return "retried"
+	case OutcomeDeadLettered:
+		return "dead-lettered"
+	case OutcomeDropped:
+		return "dropped"
+	case OutcomeInterrupted:
+		return "interrupted"
+	default:
+		return fmt.Sprintf("outcome(%d)", int(o))
+	}
+}
+
+// OutcomeHandler is called with the outcome of a message and the handler
+// error, or the publish error for OutcomeInterrupted. err is nil for
+// OutcomeSucceeded.
+type OutcomeHandler func(msg *ConsumedMessage, outcome Outcome, err error)
+
+// forwards reports whether failed messages are published to other topics
+func (p *ErrorPolicy) forwards() bool {
+	return len(p.RetryTopicDelays) > 0 || p.DeadLetterTopic != ""
+}
+
+// minPublishBackoff is the shortest wait between attempts to publish to a
+// retry or dead-letter topic, so a zero RetryBackoff does not spin
+const minPublishBackoff = 100 * time.Millisecond
+
+// backoff returns the delay before the given retry, counting from 0
+func (p *ErrorPolicy) backoff(retry int) time.Duration {
+	d := p.RetryBackoff << min(retry, 20)
+	if p.MaxRetryBackoff > 0 && (d > p.MaxRetryBackoff || d < p.RetryBackoff) {
+		d = p.MaxRetryBackoff
+	}
+	return d
+}
+
+// publishBackoff returns the delay before the given publish retry, counting
+// from 0. It is the retry backoff but at least minPublishBackoff.
+func (p *ErrorPolicy) publishBackoff(retry int) time.Duration {
+	return max(p.backoff(retry), minPublishBackoff)
+}
+
+// RetryTopic returns the name of the retry topic at level for a topic
+func RetryTopic(topic string, level int) string {
+	return fmt.Sprintf("%s.retry.%d", topic, level)
+}
+
+// subscriptions returns the consumed topics followed by their retry topics
+func (c *ConsumerConfig) subscriptions() []string {
+	topics := make([]string, 0, len(c.Topics)*(1+len(c.ErrorPolicy.RetryTopicDelays)))
+	topics = append(topics, c.Topics...)
+	for _, topic := range c.Topics {
+		for level := 1; level <= len(c.ErrorPolicy.RetryTopicDelays); level++ {
+			topics = append(topics, RetryTopic(topic, level))
+		}
+	}
+	return topics
+}
+
+// process handles one message according to the error policy and marks it
+// once it is settled. It returns false if the session ended first.
+func (h *consumerGroupHandler) process(session sarama.ConsumerGroupSession, message *sarama.ConsumerMessage) bool {
+	ctx := session.Context()
+	msg := newConsumedMessage(message)
+	policy := &h.config.Consumer.ErrorPolicy
+
+	// Retry topic messages wait until their delay has passed
+	if at, err := strconv.ParseInt(msg.Headers[HeaderRetryAt], 10, 64); err == nil {
+		if err := sleepContext(ctx, time.Until(time.UnixMilli(at))); err != nil {
+			h.report(msg, OutcomeInterrupted, err)
+			return false
+		}
+	}
+
+	err := h.handle(ctx, msg)
+	if err == nil {
+		session.MarkMessage(message, "")
+		h.report(msg, OutcomeSucceeded, nil)
+		return true
+	}
+	if ctx.Err() != nil {
+		h.report(msg, OutcomeInterrupted, err)
+		return false
+	}
+
+	original := msg.Headers[HeaderOriginalTopic]
+	if original == "" {
+		original = h.config.stripWorkspacePrefix(msg.Topic)
+	}
+	level, _ := strconv.Atoi(msg.Headers[HeaderRetryLevel])
+
+	outcome := OutcomeDropped
+	switch {
+	case level < len(policy.RetryTopicDelays):
+		outcome = OutcomeRetried
+		retry := h.failedMessage(msg, original, err)
+		retry.Topic = RetryTopic(original, level+1)
+		retry.Headers[HeaderRetryLevel] = strconv.Itoa(level + 1)
+		retry.Headers[HeaderRetryAt] = strconv.FormatInt(time.Now().Add(policy.RetryTopicDelays[level]).UnixMilli(), 10)
+		if perr := h.publish(ctx, retry); perr != nil {
+			h.report(msg, OutcomeInterrupted, perr)
+			return false
+		}
+	case policy.DeadLetterTopic != "":
+		outcome = OutcomeDeadLettered
+		dead := h.failedMessage(msg, original, err)
+		dead.Topic = strings.ReplaceAll(policy.DeadLetterTopic, "{topic}", original)
+		delete(dead.Headers, HeaderRetryAt)
+		if perr := h.publish(ctx, dead); perr != nil {
+			h.report(msg, OutcomeInterrupted, perr)
+			return false
+		}
+	}
+
+	session.MarkMessage(message, "")
+	h.report(msg, outcome, err)
+	return true
+}
+
+// handle calls the message handler, retrying in place with backoff
+func (h *consumerGroupHandler) handle(ctx context.Context, msg *ConsumedMessage) error {
+	policy := &h.config.Consumer.ErrorPolicy
+	for retry := 0; ; retry++ {
+		err := h.handler(ctx, msg)
+		if err == nil || retry >= policy.MaxRetries || ctx.Err() != nil {
+			return err
+		}
+		if sleepContext(ctx, policy.backoff(retry)) != nil {
+			return err
+		}
+		msg.Attempt++
+	}
+}
+
+// failedMessage copies a failed message with headers describing the failure.
+// The original topic, partition and offset are kept from the first failure.
+func (h *consumerGroupHandler) failedMessage(msg *ConsumedMessage, original string, err error) *Message {
+	headers := make(map[string]string, len(msg.Headers)+7)
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	headers[HeaderOriginalTopic] = original
+	if _, ok := headers[HeaderOriginalPartition]; !ok {
+		headers[HeaderOriginalPartition] = strconv.FormatInt(int64(msg.Partition), 10)
+		headers[HeaderOriginalOffset] = strconv.FormatInt(msg.Offset, 10)
+	}
+	headers[HeaderError] = err.Error()
+	headers[HeaderAttempts] = strconv.Itoa(msg.Attempt)
+
+	return &Message{
+		Key:       msg.Key,
+		Value:     msg.Value,
+		Headers:   headers,
+		Partition: -1,
+	}
+}
+
+// publish sends a message to a retry or dead-letter topic, retrying with
+// backoff until it succeeds or the session ends
+func (h *consumerGroupHandler) publish(ctx context.Context, msg *Message) error {
+	for retry := 0; ; retry++ {
+		_, _, err := h.producer.SendMessage(ctx, msg)
+		if err == nil {
+			return nil
+		}
+		if sleepContext(ctx, h.config.Consumer.ErrorPolicy.publishBackoff(retry)) != nil {
+			return fmt.Errorf("failed to publish to %s: %w", msg.Topic, err)
+		}
+	}
+}
+
+// report passes an outcome to the outcome handler
+func (h *consumerGroupHandler) report(msg *ConsumedMessage, outcome Outcome, err error) {
+	if fn := h.config.Consumer.ErrorPolicy.OnOutcome; fn != nil {
+		fn(msg, outcome, err)
+	}
+}
+
+// stripWorkspacePrefix removes the workspace prefix from a topic name
+func (c *Config) stripWorkspacePrefix(topic string) string {
+	if c.Workspace == "" {
+		return topic
+	}
+	return strings.TrimPrefix(topic, c.Workspace+".")
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
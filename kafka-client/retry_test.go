@@ -0,0 +1,371 @@
+package kafkaclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+)
+
+// fakeSession is a consumer group session that records marked offsets
+type fakeSession struct {
+	ctx    context.Context
+	mu     sync.Mutex
+	marked []int64
+}
+
+func (s *fakeSession) Claims() map[string][]int32                        { return nil }
+func (s *fakeSession) MemberID() string                                  { return "member" }
+func (s *fakeSession) GenerationID() int32                               { return 1 }
+func (s *fakeSession) MarkOffset(string, int32, int64, string)           {}
+func (s *fakeSession) Commit()                                           {}
+func (s *fakeSession) ResetOffset(string, int32, int64, string)          {}
+func (s *fakeSession) Context() context.Context                          { return s.ctx }
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) { s.mark(msg.Offset) }
+
+func (s *fakeSession) mark(offset int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.marked = append(s.marked, offset)
+}
+
+func (s *fakeSession) markedOffsets() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int64(nil), s.marked...)
+}
+
+// fakeClaim is a claim that serves messages from a channel
+type fakeClaim struct {
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Topic() string                            { return "orders" }
+func (c *fakeClaim) Partition() int32                         { return 0 }
+func (c *fakeClaim) InitialOffset() int64                     { return 0 }
+func (c *fakeClaim) HighWaterMarkOffset() int64               { return 0 }
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+// outcomeRecorder collects outcomes passed to the outcome handler
+type outcomeRecorder struct {
+	mu       sync.Mutex
+	outcomes []Outcome
+	errs     []error
+}
+
+func (r *outcomeRecorder) handle(_ *ConsumedMessage, outcome Outcome, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.outcomes = append(r.outcomes, outcome)
+	r.errs = append(r.errs, err)
+}
+
+// newTestHandler creates a handler whose producer is a Sarama mock
+func newTestHandler(t *testing.T, config *Config, handler MessageHandler) (*consumerGroupHandler, *mocks.SyncProducer) {
+	mock := mocks.NewSyncProducer(t, nil)
+	t.Cleanup(func() { mock.Close() })
+	return &consumerGroupHandler{
+		handler:  handler,
+		config:   config,
+		producer: &Producer{producer: mock, config: config},
+		ready:    make(chan bool),
+	}, mock
+}
+
+// headersOf converts producer headers to a map
+func headersOf(msg *sarama.ProducerMessage) map[string]string {
+	headers := make(map[string]string)
+	for _, h := range msg.Headers {
+		headers[string(h.Key)] = string(h.Value)
+	}
+	return headers
+}
+
+// consumerMessage converts a produced message to the message a consumer would see
+func consumerMessage(t *testing.T, msg *sarama.ProducerMessage, offset int64) *sarama.ConsumerMessage {
+	t.Helper()
+	value, err := msg.Value.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	headers := make([]*sarama.RecordHeader, 0, len(msg.Headers))
+	for i := range msg.Headers {
+		headers = append(headers, &msg.Headers[i])
+	}
+	return &sarama.ConsumerMessage{Topic: msg.Topic, Offset: offset, Value: value, Headers: headers}
+}
+
+func TestConsumer_InPlaceRetry(t *testing.T) {
+	config := DefaultConfig()
+	config.Consumer.ErrorPolicy.MaxRetries = 3
+	config.Consumer.ErrorPolicy.RetryBackoff = time.Millisecond
+	recorder := &outcomeRecorder{}
+	config.Consumer.ErrorPolicy.OnOutcome = recorder.handle
+
+	var attempts []int
+	handler, _ := newTestHandler(t, config, func(ctx context.Context, msg *ConsumedMessage) error {
+		attempts = append(attempts, msg.Attempt)
+		if len(attempts) < 3 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	})
+
+	session := &fakeSession{ctx: context.Background()}
+	if !handler.process(session, &sarama.ConsumerMessage{Topic: "orders", Offset: 7}) {
+		t.Fatal("process() = false, want true")
+	}
+
+	if fmt.Sprint(attempts) != "[1 2 3]" {
+		t.Errorf("attempts = %v, want [1 2 3]", attempts)
+	}
+	if got := session.markedOffsets(); len(got) != 1 || got[0] != 7 {
+		t.Errorf("marked offsets = %v, want [7]", got)
+	}
+	if len(recorder.outcomes) != 1 || recorder.outcomes[0] != OutcomeSucceeded || recorder.errs[0] != nil {
+		t.Errorf("outcomes = %v %v, want [succeeded] [<nil>]", recorder.outcomes, recorder.errs)
+	}
+}
+
+func TestConsumer_RetryTopicsAndDeadLetter(t *testing.T) {
+	config := DefaultConfig()
+	config.Workspace = "prod"
+	config.Consumer.ErrorPolicy.RetryTopicDelays = []time.Duration{time.Millisecond, time.Millisecond}
+	config.Consumer.ErrorPolicy.DeadLetterTopic = "{topic}.dlq"
+	recorder := &outcomeRecorder{}
+	config.Consumer.ErrorPolicy.OnOutcome = recorder.handle
+
+	handlerErr := errors.New("payment declined")
+	handler, mock := newTestHandler(t, config, func(ctx context.Context, msg *ConsumedMessage) error {
+		return handlerErr
+	})
+
+	var published []*sarama.ProducerMessage
+	capture := func(msg *sarama.ProducerMessage) error {
+		published = append(published, msg)
+		return nil
+	}
+	mock.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(capture)
+	mock.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(capture)
+	mock.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(capture)
+
+	session := &fakeSession{ctx: context.Background()}
+	message := &sarama.ConsumerMessage{
+		Topic:     "prod.orders",
+		Partition: 2,
+		Offset:    41,
+		Value:     []byte("order-1"),
+		Headers:   []*sarama.RecordHeader{{Key: []byte("trace-id"), Value: []byte("abc")}},
+	}
+
+	for i, wantTopic := range []string{"prod.orders.retry.1", "prod.orders.retry.2", "prod.orders.dlq"} {
+		if !handler.process(session, message) {
+			t.Fatalf("step %d: process() = false, want true", i)
+		}
+		if len(published) != i+1 {
+			t.Fatalf("step %d: published %d messages, want %d", i, len(published), i+1)
+		}
+		msg := published[i]
+		if msg.Topic != wantTopic {
+			t.Errorf("step %d: topic = %s, want %s", i, msg.Topic, wantTopic)
+		}
+
+		headers := headersOf(msg)
+		want := map[string]string{
+			"trace-id":              "abc",
+			HeaderOriginalTopic:     "orders",
+			HeaderOriginalPartition: "2",
+			HeaderOriginalOffset:    "41",
+			HeaderError:             "payment declined",
+			HeaderAttempts:          strconv.Itoa(i + 1),
+		}
+		for k, v := range want {
+			if headers[k] != v {
+				t.Errorf("step %d: header %s = %q, want %q", i, k, headers[k], v)
+			}
+		}
+		if _, ok := headers[HeaderRetryAt]; ok != (i < 2) {
+			t.Errorf("step %d: retry-at header present = %v, want %v", i, ok, i < 2)
+		}
+
+		// Consume the forwarded message next
+		message = consumerMessage(t, msg, int64(100+i))
+	}
+
+	wantOutcomes := []Outcome{OutcomeRetried, OutcomeRetried, OutcomeDeadLettered}
+	if fmt.Sprint(recorder.outcomes) != fmt.Sprint(wantOutcomes) {
+		t.Errorf("outcomes = %v, want %v", recorder.outcomes, wantOutcomes)
+	}
+	for _, err := range recorder.errs {
+		if !errors.Is(err, handlerErr) {
+			t.Errorf("outcome error = %v, want %v", err, handlerErr)
+		}
+	}
+	if got := session.markedOffsets(); fmt.Sprint(got) != "[41 100 101]" {
+		t.Errorf("marked offsets = %v, want [41 100 101]", got)
+	}
+}
+
+func TestConsumer_RetryDelay(t *testing.T) {
+	config := DefaultConfig()
+
+	var handledAt time.Time
+	handler, _ := newTestHandler(t, config, func(ctx context.Context, msg *ConsumedMessage) error {
+		handledAt = time.Now()
+		return nil
+	})
+
+	retryAt := time.Now().Add(50 * time.Millisecond)
+	message := &sarama.ConsumerMessage{
+		Topic: "orders.retry.1",
+		Headers: []*sarama.RecordHeader{
+			{Key: []byte(HeaderRetryAt), Value: []byte(strconv.FormatInt(retryAt.UnixMilli(), 10))},
+		},
+	}
+	if !handler.process(&fakeSession{ctx: context.Background()}, message) {
+		t.Fatal("process() = false, want true")
+	}
+	if handledAt.Before(retryAt.Truncate(time.Millisecond)) {
+		t.Errorf("message handled %v before its retry time", retryAt.Sub(handledAt))
+	}
+}
+
+func TestConsumer_DropWithoutPolicy(t *testing.T) {
+	config := DefaultConfig()
+	recorder := &outcomeRecorder{}
+	config.Consumer.ErrorPolicy.OnOutcome = recorder.handle
+
+	handler, _ := newTestHandler(t, config, func(ctx context.Context, msg *ConsumedMessage) error {
+		return errors.New("bad message")
+	})
+
+	session := &fakeSession{ctx: context.Background()}
+	if !handler.process(session, &sarama.ConsumerMessage{Topic: "orders", Offset: 3}) {
+		t.Fatal("process() = false, want true")
+	}
+	if got := session.markedOffsets(); len(got) != 1 || got[0] != 3 {
+		t.Errorf("marked offsets = %v, want [3]", got)
+	}
+	if len(recorder.outcomes) != 1 || recorder.outcomes[0] != OutcomeDropped || recorder.errs[0] == nil {
+		t.Errorf("outcomes = %v %v, want [dropped] with error", recorder.outcomes, recorder.errs)
+	}
+}
+
+func TestConsumer_InterruptedPublish(t *testing.T) {
+	config := DefaultConfig()
+	config.Consumer.ErrorPolicy.DeadLetterTopic = "dead-letters"
+	config.Consumer.ErrorPolicy.RetryBackoff = time.Hour
+	recorder := &outcomeRecorder{}
+	config.Consumer.ErrorPolicy.OnOutcome = recorder.handle
+
+	handler, mock := newTestHandler(t, config, func(ctx context.Context, msg *ConsumedMessage) error {
+		return errors.New("bad message")
+	})
+	mock.ExpectSendMessageAndFail(sarama.ErrNotLeaderForPartition)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, 2)}
+	claim.messages <- &sarama.ConsumerMessage{Topic: "orders", Offset: 1}
+	claim.messages <- &sarama.ConsumerMessage{Topic: "orders", Offset: 2}
+
+	session := &fakeSession{ctx: ctx}
+	if err := handler.ConsumeClaim(session, claim); err != nil {
+		t.Fatalf("ConsumeClaim() error = %v", err)
+	}
+
+	// Nothing may be marked, or the failed message would be skipped
+	if got := session.markedOffsets(); len(got) != 0 {
+		t.Errorf("marked offsets = %v, want none", got)
+	}
+	if len(claim.messages) != 1 {
+		t.Errorf("ConsumeClaim() should stop after the interrupted message")
+	}
+	if len(recorder.outcomes) != 1 || recorder.outcomes[0] != OutcomeInterrupted {
+		t.Errorf("outcomes = %v, want [interrupted]", recorder.outcomes)
+	}
+	if !errors.Is(recorder.errs[0], sarama.ErrNotLeaderForPartition) {
+		t.Errorf("outcome error = %v, want the publish error", recorder.errs[0])
+	}
+}
+
+func TestConsumer_PublishRetryWithZeroBackoff(t *testing.T) {
+	config := DefaultConfig()
+	WithConsumerRetry(0, 0, 0)(config)
+	WithDeadLetterTopic("dead-letters")(config)
+
+	handler, mock := newTestHandler(t, config, func(ctx context.Context, msg *ConsumedMessage) error {
+		return errors.New("bad message")
+	})
+	mock.ExpectSendMessageAndFail(sarama.ErrNotLeaderForPartition)
+	mock.ExpectSendMessageAndFail(sarama.ErrNotLeaderForPartition)
+	mock.ExpectSendMessageAndSucceed()
+
+	start := time.Now()
+	if !handler.process(&fakeSession{ctx: context.Background()}, &sarama.ConsumerMessage{Topic: "orders"}) {
+		t.Fatal("process() = false, want true")
+	}
+	if elapsed := time.Since(start); elapsed < 2*minPublishBackoff {
+		t.Errorf("two failed publishes took %v, want at least %v", elapsed, 2*minPublishBackoff)
+	}
+}
+
+func TestErrorPolicy_Subscriptions(t *testing.T) {
+	config := DefaultConfig()
+	WithTopics([]string{"orders", "users"})(config)
+	WithRetryTopics(time.Second, time.Minute)(config)
+	WithWorkspace("prod")(config)
+
+	got := config.ApplyWorkspacePrefixToTopics(config.Consumer.subscriptions())
+	want := []string{
+		"prod.orders", "prod.users",
+		"prod.orders.retry.1", "prod.orders.retry.2",
+		"prod.users.retry.1", "prod.users.retry.2",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("subscriptions = %v, want %v", got, want)
+	}
+}
+
+func TestErrorPolicy_Backoff(t *testing.T) {
+	policy := ErrorPolicy{RetryBackoff: 100 * time.Millisecond, MaxRetryBackoff: time.Second}
+
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{1, 200 * time.Millisecond},
+		{3, 800 * time.Millisecond},
+		{4, time.Second},
+		{1000, time.Second},
+	}
+	for _, tt := range tests {
+		if got := policy.backoff(tt.retry); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.retry, got, tt.want)
+		}
+	}
+}
+
+func TestOutcome_String(t *testing.T) {
+	tests := map[Outcome]string{
+		OutcomeSucceeded:    "succeeded",
+		OutcomeRetried:      "retried",
+		OutcomeDeadLettered: "dead-lettered",
+		OutcomeDropped:      "dropped",
+		OutcomeInterrupted:  "interrupted",
+		Outcome(42):         "outcome(42)",
+	}
+	for outcome, want := range tests {
+		if got := outcome.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
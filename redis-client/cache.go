@@ -0,0 +1,469 @@
+package redisclient
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// ====================
+// Typed Cache
+// ====================
+
+// CacheOptions configures a Cache
+type CacheOptions struct {
+	Codec        Codec         // Value encoding, default JSONCodec
+	Prefix       string        // Prepended to every key, e.g. "user:"
+	DefaultTTL   time.Duration // TTL used when 0 is passed, 0 means no expiry
+	NegativeTTL  time.Duration // TTL for remembering ErrNotFound from loaders, 0 disables
+	TTLJitter    float64       // Randomizes TTLs by +/- this fraction to spread expiries, e.g. 0.1
+	EarlyRefresh float64       // XFetch beta for probabilistic early refresh, 0 disables, 1 is typical
+	ValueCodec   *ValueCodec   // Compresses and encrypts entries, optional
+	LoadTimeout  time.Duration // Bounds a shared loader call, 0 means no limit
+}
+
+// DefaultCacheOptions returns the default cache options
+func DefaultCacheOptions() CacheOptions {
+	return CacheOptions{
+		Codec:       JSONCodec{},
+		DefaultTTL:  time.Hour,
+		LoadTimeout: 30 * time.Second,
+	}
+}
+
+// CacheOption is a functional option for configuring a Cache
+type CacheOption func(*CacheOptions)
+
+// WithCodec sets the value codec
+func WithCodec(codec Codec) CacheOption {
+	return func(o *CacheOptions) {
+		o.Codec = codec
+	}
+}
+
+// WithCachePrefix sets the key prefix
+func WithCachePrefix(prefix string) CacheOption {
+	return func(o *CacheOptions) {
+		o.Prefix = prefix
+	}
+}
+
+// WithDefaultTTL sets the TTL used when 0 is passed
+func WithDefaultTTL(ttl time.Duration) CacheOption {
+	return func(o *CacheOptions) {
+		o.DefaultTTL = ttl
+	}
+}
+
+// WithNegativeTTL caches ErrNotFound returned by loaders for the given TTL
+func WithNegativeTTL(ttl time.Duration) CacheOption {
+	return func(o *CacheOptions) {
+		o.NegativeTTL = ttl
+	}
+}
+
+// WithTTLJitter randomizes TTLs by +/- the given fraction
+func WithTTLJitter(fraction float64) CacheOption {
+	return func(o *CacheOptions) {
+		o.TTLJitter = fraction
+	}
+}
+
+// WithEarlyRefresh enables probabilistic early refresh in GetOrLoad. Entries are
+// reloaded in the background shortly before they expire, with a probability
+// that grows as expiry approaches and with the time the last load took.
+func WithEarlyRefresh(beta float64) CacheOption {
+	return func(o *CacheOptions) {
+		o.EarlyRefresh = beta
+	}
+}
+
+// WithLoadTimeout bounds a loader call. The call is shared by concurrent
+// misses, so it does not stop when the caller that started it gives up.
+func WithLoadTimeout(timeout time.Duration) CacheOption {
+	return func(o *CacheOptions) {
+		o.LoadTimeout = timeout
+	}
+}
+
+// WithCacheValueCodec compresses and encrypts entries of this cache. It is not
+// needed when the client already has a value codec.
+func WithCacheValueCodec(codec *ValueCodec) CacheOption {
+	return func(o *CacheOptions) {
+		o.ValueCodec = codec
+	}
+}
+
+// Loader loads a value on a cache miss. Return ErrNotFound when the value does
+// not exist so it can be negatively cached.
+type Loader[T any] func(ctx context.Context) (T, error)
+
+// Cache is a typed cache-aside cache backed by a Client
+type Cache[T any] struct {
+	client *Client
+	opts   CacheOptions
+	group  singleflight.Group
+}
+
+// NewCache creates a typed cache on top of a client
+func NewCache[T any](client *Client, opts ...CacheOption) *Cache[T] {
+	options := DefaultCacheOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.Codec == nil {
+		options.Codec = JSONCodec{}
+	}
+
+	return &Cache[T]{
+		client: client,
+		opts:   options,
+	}
+}
+
+// Get returns a cached value. It returns ErrNil on a miss and ErrNotFound for
+// a negatively cached key.
+func (c *Cache[T]) Get(ctx context.Context, key string) (T, error) {
+	var zero T
+
+	entry, err := c.getEntry(ctx, key)
+	if err != nil {
+		return zero, err
+	}
+	if entry.negative {
+		return zero, ErrNotFound
+	}
+
+	return entry.value, nil
+}
+
+// Set stores a value. A TTL of 0 uses the default TTL.
+func (c *Cache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	data, err := c.encode(value, false, c.ttl(ttl), 0)
+	if err != nil {
+		return err
+	}
+
+	return c.client.Set(ctx, c.key(key), data, c.jitter(c.ttl(ttl)))
+}
+
+// Delete removes values from the cache
+func (c *Cache[T]) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	fullKeys := make([]string, len(keys))
+	for i, key := range keys {
+		fullKeys[i] = c.key(key)
+	}
+
+	_, err := c.client.Del(ctx, fullKeys...)
+	return err
+}
+
+// GetOrLoad returns the cached value or calls loader on a miss and caches the
+// result. Concurrent misses for the same key share a single loader call, which
+// runs until LoadTimeout even if ctx is done; each caller returns when its own
+// ctx is.
+// Redis errors are treated as misses so the loader still serves requests when
+// Redis is unavailable.
+func (c *Cache[T]) GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader Loader[T]) (T, error) {
+	entry, _, err := c.getOrLoad(ctx, key, ttl, loader)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	if entry.negative {
+		var zero T
+		return zero, ErrNotFound
+	}
+	return entry.value, nil
+}
+
+// getOrLoad implements GetOrLoad and reports whether the entry came from Redis
+func (c *Cache[T]) getOrLoad(ctx context.Context, key string, ttl time.Duration, loader Loader[T]) (cacheEntry[T], bool, error) {
+	entry, err := c.getEntry(ctx, key)
+	if err == nil {
+		if c.shouldRefreshEarly(entry) {
+			c.refreshAsync(ctx, key, ttl, loader)
+		}
+		return entry, true, nil
+	}
+	if err == ErrInvalidKey {
+		return entry, false, err
+	}
+	if ctx.Err() != nil {
+		return entry, false, ctx.Err()
+	}
+
+	var result singleflight.Result
+	select {
+	case result = <-c.group.DoChan(c.key(key), func() (interface{}, error) {
+		loadCtx, cancel := c.loadContext(ctx)
+		defer cancel()
+		return c.load(loadCtx, key, ttl, loader)
+	}):
+	case <-ctx.Done():
+		return entry, false, ctx.Err()
+	}
+	if result.Err != nil {
+		if errors.Is(result.Err, ErrNotFound) {
+			return cacheEntry[T]{negative: true}, false, nil
+		}
+		return entry, false, result.Err
+	}
+
+	return cacheEntry[T]{value: result.Val.(T)}, false, nil
+}
+
+// GetMany returns the cached values for the keys that are present, using a
+// single pipeline. Missing and negatively cached keys are omitted.
+func (c *Cache[T]) GetMany(ctx context.Context, keys ...string) (map[string]T, error) {
+	result := make(map[string]T, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	c.client.mu.RLock()
+	defer c.client.mu.RUnlock()
+
+	if c.client.closed {
+		return nil, ErrClientClosed
+	}
+
+	pipe := c.client.client.Pipeline()
+	for _, key := range keys {
+		pipe.Get(ctx, c.key(key))
+	}
+
+	cmds, err := pipe.Exec(ctx)
+	if err != nil && !IsNil(err) {
+		return nil, err
+	}
+
+	for i, cmd := range cmds {
+		data, err := cmd.(*redis.StringCmd).Bytes()
+		if err != nil {
+			continue
+		}
+		entry, err := c.decode(data)
+		if err != nil || entry.negative {
+			continue
+		}
+		result[keys[i]] = entry.value
+	}
+
+	return result, nil
+}
+
+// SetMany stores several values using a single pipeline. A TTL of 0 uses the
+// default TTL; each key gets its own jitter.
+func (c *Cache[T]) SetMany(ctx context.Context, values map[string]T, ttl time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	c.client.mu.RLock()
+	defer c.client.mu.RUnlock()
+
+	if c.client.closed {
+		return ErrClientClosed
+	}
+
+	ttl = c.ttl(ttl)
+	pipe := c.client.client.Pipeline()
+	for key, value := range values {
+		if key == "" {
+			return ErrInvalidKey
+		}
+		data, err := c.encode(value, false, ttl, 0)
+		if err != nil {
+			return err
+		}
+		pipe.Set(ctx, c.key(key), data, c.jitter(ttl))
+	}
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// load calls the loader and stores its result
+func (c *Cache[T]) load(ctx context.Context, key string, ttl time.Duration, loader Loader[T]) (T, error) {
+	start := time.Now()
+	value, err := loader(ctx)
+	delta := time.Since(start)
+
+	if err != nil {
+		if errors.Is(err, ErrNotFound) && c.opts.NegativeTTL > 0 {
+			var zero T
+			if data, encErr := c.encode(zero, true, c.opts.NegativeTTL, delta); encErr == nil {
+				_ = c.client.Set(ctx, c.key(key), data, c.opts.NegativeTTL)
+			}
+		}
+		return value, err
+	}
+
+	ttl = c.ttl(ttl)
+	if data, err := c.encode(value, false, ttl, delta); err == nil {
+		// Best effort: a failed write only costs another load
+		_ = c.client.Set(ctx, c.key(key), data, c.jitter(ttl))
+	}
+
+	return value, nil
+}
+
+// refreshAsync reloads a key in the background, deduplicated with foreground loads
+func (c *Cache[T]) refreshAsync(ctx context.Context, key string, ttl time.Duration, loader Loader[T]) {
+	c.group.DoChan(c.key(key), func() (interface{}, error) {
+		loadCtx, cancel := c.loadContext(ctx)
+		defer cancel()
+		return c.load(loadCtx, key, ttl, loader)
+	})
+}
+
+// loadContext detaches a shared load from the cancellation of the caller that
+// started it and bounds it by LoadTimeout
+func (c *Cache[T]) loadContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx = context.WithoutCancel(ctx)
+	if c.opts.LoadTimeout > 0 {
+		return context.WithTimeout(ctx, c.opts.LoadTimeout)
+	}
+	return ctx, func() {}
+}
+
+// shouldRefreshEarly implements XFetch: refresh when
+// now - delta * beta * ln(rand) >= expiry
+func (c *Cache[T]) shouldRefreshEarly(entry cacheEntry[T]) bool {
+	if c.opts.EarlyRefresh <= 0 || entry.expiresAt.IsZero() || entry.delta <= 0 {
+		return false
+	}
+
+	gap := time.Duration(float64(entry.delta) * c.opts.EarlyRefresh * -math.Log(1-rand.Float64()))
+	return !time.Now().Add(gap).Before(entry.expiresAt)
+}
+
+// getEntry reads and decodes an entry
+func (c *Cache[T]) getEntry(ctx context.Context, key string) (cacheEntry[T], error) {
+	if key == "" {
+		return cacheEntry[T]{}, ErrInvalidKey
+	}
+
+	data, err := c.client.Get(ctx, c.key(key))
+	if err != nil {
+		return cacheEntry[T]{}, err
+	}
+
+	return c.decode([]byte(data))
+}
+
+// key returns the prefixed Redis key
+func (c *Cache[T]) key(key string) string {
+	return c.opts.Prefix + key
+}
+
+// ttl returns the TTL to use, applying the default
+func (c *Cache[T]) ttl(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return c.opts.DefaultTTL
+	}
+	return ttl
+}
+
+// jitter randomizes a TTL by +/- TTLJitter
+func (c *Cache[T]) jitter(ttl time.Duration) time.Duration {
+	if ttl <= 0 || c.opts.TTLJitter <= 0 {
+		return ttl
+	}
+
+	spread := float64(ttl) * c.opts.TTLJitter
+	jittered := time.Duration(float64(ttl) + spread*(2*rand.Float64()-1))
+	if jittered < time.Millisecond {
+		return time.Millisecond
+	}
+	return jittered
+}
+
+// cacheEntry is a decoded cache value with its metadata
+type cacheEntry[T any] struct {
+	value     T
+	negative  bool
+	expiresAt time.Time     // Logical expiry used for early refresh, zero if none
+	delta     time.Duration // How long the value took to load
+}
+
+// Entry header: version, flags, expiry (unix ms), load time (ms)
+const (
+	cacheEntryVersion    = 1
+	cacheEntryNegative   = 1 << 0
+	cacheEntryHeaderSize = 1 + 1 + 8 + 4
+)
+
+// encode serializes a value with its metadata header
+func (c *Cache[T]) encode(value T, negative bool, ttl, delta time.Duration) ([]byte, error) {
+	var payload []byte
+	if !negative {
+		var err error
+		payload, err = c.opts.Codec.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode cache value: %w", err)
+		}
+	}
+
+	data := make([]byte, cacheEntryHeaderSize, cacheEntryHeaderSize+len(payload))
+	data[0] = cacheEntryVersion
+	if negative {
+		data[1] |= cacheEntryNegative
+	}
+	if ttl > 0 {
+		binary.BigEndian.PutUint64(data[2:10], uint64(time.Now().Add(ttl).UnixMilli()))
+	}
+	ms := delta.Milliseconds()
+	if ms > math.MaxUint32 {
+		ms = math.MaxUint32
+	}
+	binary.BigEndian.PutUint32(data[10:14], uint32(ms))
+	data = append(data, payload...)
+
+	if c.opts.ValueCodec != nil {
+		return c.opts.ValueCodec.Encode(data)
+	}
+	return data, nil
+}
+
+// decode parses an entry written by encode
+func (c *Cache[T]) decode(data []byte) (cacheEntry[T], error) {
+	var entry cacheEntry[T]
+
+	if c.opts.ValueCodec != nil {
+		var err error
+		if data, err = c.opts.ValueCodec.Decode(data); err != nil {
+			return entry, fmt.Errorf("%w: %v", ErrCacheCorrupt, err)
+		}
+	}
+
+	if len(data) < cacheEntryHeaderSize || data[0] != cacheEntryVersion {
+		return entry, ErrCacheCorrupt
+	}
+
+	entry.negative = data[1]&cacheEntryNegative != 0
+	if ms := binary.BigEndian.Uint64(data[2:10]); ms > 0 {
+		entry.expiresAt = time.UnixMilli(int64(ms))
+	}
+	entry.delta = time.Duration(binary.BigEndian.Uint32(data[10:14])) * time.Millisecond
+
+	if !entry.negative {
+		if err := c.opts.Codec.Unmarshal(data[cacheEntryHeaderSize:], &entry.value); err != nil {
+			return entry, fmt.Errorf("%w: %v", ErrCacheCorrupt, err)
+		}
+	}
+
+	return entry, nil
+}
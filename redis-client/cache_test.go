@@ -0,0 +1,211 @@
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type cachedUser struct {
+	ID    int      `json:"id" msgpack:"id"`
+	Name  string   `json:"name" msgpack:"name"`
+	Roles []string `json:"roles" msgpack:"roles"`
+}
+
+// TestCodecs tests that every codec round-trips a value
+func TestCodecs(t *testing.T) {
+	codecs := map[string]Codec{
+		"json":    JSONCodec{},
+		"msgpack": MsgpackCodec{},
+		"gob":     GobCodec{},
+	}
+
+	in := cachedUser{ID: 7, Name: "Ada", Roles: []string{"admin"}}
+
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			data, err := codec.Marshal(in)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+
+			var out cachedUser
+			if err := codec.Unmarshal(data, &out); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			if out.ID != in.ID || out.Name != in.Name || len(out.Roles) != 1 || out.Roles[0] != "admin" {
+				t.Errorf("round trip = %+v, want %+v", out, in)
+			}
+		})
+	}
+}
+
+// TestCache_EntryEncoding tests the entry header and negative entries
+func TestCache_EntryEncoding(t *testing.T) {
+	cache := NewCache[cachedUser](&Client{config: DefaultConfig()}, WithCodec(MsgpackCodec{}))
+
+	data, err := cache.encode(cachedUser{ID: 1, Name: "Ada"}, false, time.Minute, 150*time.Millisecond)
+	if err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+
+	entry, err := cache.decode(data)
+	if err != nil {
+		t.Fatalf("decode() error = %v", err)
+	}
+	if entry.negative || entry.value.Name != "Ada" {
+		t.Errorf("decode() = %+v", entry)
+	}
+	if entry.delta != 150*time.Millisecond {
+		t.Errorf("delta = %v, want 150ms", entry.delta)
+	}
+	if until := time.Until(entry.expiresAt); until < 55*time.Second || until > time.Minute {
+		t.Errorf("expiresAt is %v from now, want about 1m", until)
+	}
+
+	data, err = cache.encode(cachedUser{}, true, time.Minute, 0)
+	if err != nil {
+		t.Fatalf("encode() negative error = %v", err)
+	}
+	if entry, err := cache.decode(data); err != nil || !entry.negative {
+		t.Errorf("decode() negative = %+v, %v", entry, err)
+	}
+
+	if _, err := cache.decode([]byte("plain string")); !errors.Is(err, ErrCacheCorrupt) {
+		t.Errorf("decode() of foreign value error = %v, want ErrCacheCorrupt", err)
+	}
+}
+
+// TestCache_TTL tests default TTLs and jitter bounds
+func TestCache_TTL(t *testing.T) {
+	cache := NewCache[string](&Client{config: DefaultConfig()},
+		WithDefaultTTL(10*time.Minute),
+		WithTTLJitter(0.1),
+	)
+
+	if got := cache.ttl(0); got != 10*time.Minute {
+		t.Errorf("ttl(0) = %v, want default 10m", got)
+	}
+
+	for i := 0; i < 100; i++ {
+		got := cache.jitter(time.Minute)
+		if got < 54*time.Second || got > 66*time.Second {
+			t.Fatalf("jitter(1m) = %v, want within 10%%", got)
+		}
+	}
+
+	noJitter := NewCache[string](&Client{config: DefaultConfig()})
+	if got := noJitter.jitter(time.Minute); got != time.Minute {
+		t.Errorf("jitter without TTLJitter = %v, want 1m", got)
+	}
+}
+
+// TestCache_EarlyRefresh tests the XFetch refresh decision
+func TestCache_EarlyRefresh(t *testing.T) {
+	cache := NewCache[string](&Client{config: DefaultConfig()}, WithEarlyRefresh(1))
+
+	fresh := cacheEntry[string]{expiresAt: time.Now().Add(time.Hour), delta: time.Millisecond}
+	if cache.shouldRefreshEarly(fresh) {
+		t.Error("entry far from expiry should not be refreshed")
+	}
+
+	expiring := cacheEntry[string]{expiresAt: time.Now().Add(-time.Millisecond), delta: time.Second}
+	if !cache.shouldRefreshEarly(expiring) {
+		t.Error("expired entry should be refreshed")
+	}
+
+	disabled := NewCache[string](&Client{config: DefaultConfig()})
+	if disabled.shouldRefreshEarly(expiring) {
+		t.Error("early refresh should be disabled by default")
+	}
+}
+
+// TestCache_ClosedClient tests cache operations on a closed client
+func TestCache_ClosedClient(t *testing.T) {
+	cache := NewCache[string](&Client{config: DefaultConfig(), closed: true})
+	ctx := context.Background()
+
+	if _, err := cache.Get(ctx, "a"); err != ErrClientClosed {
+		t.Errorf("Get() error = %v, want ErrClientClosed", err)
+	}
+	if _, err := cache.GetMany(ctx, "a", "b"); err != ErrClientClosed {
+		t.Errorf("GetMany() error = %v, want ErrClientClosed", err)
+	}
+	if err := cache.SetMany(ctx, map[string]string{"a": "1"}, 0); err != ErrClientClosed {
+		t.Errorf("SetMany() error = %v, want ErrClientClosed", err)
+	}
+
+	// The loader still serves requests when Redis is unavailable
+	v, err := cache.GetOrLoad(ctx, "a", 0, func(ctx context.Context) (string, error) {
+		return "loaded", nil
+	})
+	if err != nil || v != "loaded" {
+		t.Errorf("GetOrLoad() = %q, %v, want loaded", v, err)
+	}
+
+	if _, err := cache.GetOrLoad(ctx, "", 0, nil); err != ErrInvalidKey {
+		t.Errorf("GetOrLoad(\"\") error = %v, want ErrInvalidKey", err)
+	}
+}
+
+// TestCache_LoadOutlivesCaller tests that a caller giving up neither cancels
+// the shared load nor fails the callers waiting on it
+func TestCache_LoadOutlivesCaller(t *testing.T) {
+	cache := NewCache[string](&Client{config: DefaultConfig(), closed: true})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls atomic.Int32
+	loader := func(ctx context.Context) (string, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		select {
+		case <-release:
+			return "loaded", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := cache.GetOrLoad(ctx, "a", 0, loader)
+		first <- err
+	}()
+	<-started
+
+	type result struct {
+		value string
+		err   error
+	}
+	second := make(chan result, 1)
+	go func() {
+		v, err := cache.GetOrLoad(context.Background(), "a", 0, loader)
+		second <- result{v, err}
+	}()
+	time.Sleep(20 * time.Millisecond) // let the second caller join the load
+
+	cancel()
+	select {
+	case err := <-first:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("GetOrLoad() after cancel error = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("GetOrLoad() did not return when its context was canceled")
+	}
+
+	close(release)
+	got := <-second
+	if got.err != nil || got.value != "loaded" {
+		t.Errorf("waiting GetOrLoad() = %q, %v, want loaded", got.value, got.err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("loader called %d times, want 1", n)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/x509"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,7 +16,10 @@ import (
 // Client represents the Redis client wrapper
 type Client struct {
 	config *Config
-	client *redis.Client
+	client redis.UniversalClient
+
+	// Scripts preloaded on every new connection
+	scripts scriptRegistry
 
 	mu     sync.RWMutex
 	closed bool
@@ -52,8 +56,145 @@ func NewWithOptions(opts ...Option) (*Client, error) {
 	return New(config)
 }
 
-// connect establishes the Redis connection
+// connect establishes the Redis connection for the configured mode
 func (c *Client) connect() error {
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return err
+	}
+
+	switch c.config.Mode() {
+	case ModeCluster:
+		c.client = redis.NewClusterClient(c.clusterOptions(tlsConfig))
+	case ModeSentinel:
+		opts := c.failoverOptions(tlsConfig)
+		if c.config.ReadFromReplica || c.config.RouteByLatency {
+			// Replica routing needs the cluster-style failover client
+			opts.RouteByLatency = c.config.RouteByLatency
+			opts.RouteRandomly = !c.config.RouteByLatency
+			c.client = redis.NewFailoverClusterClient(opts)
+		} else {
+			c.client = redis.NewFailoverClient(opts)
+		}
+	default:
+		opts, err := c.redisOptions()
+		if err != nil {
+			return err
+		}
+		c.client = redis.NewClient(opts)
+	}
+
+	if c.config.KeyPrefix != "" {
+		c.client.AddHook(prefixHook{prefix: c.config.KeyPrefix})
+	}
+	if c.config.ValueCodec != nil {
+		c.client.AddHook(codecHook{codec: c.config.ValueCodec})
+	}
+
+	// Instrumentation runs after the prefix hook, so it sees the real keys
+	if hook := newObserveHook(c.config); hook != nil {
+		c.client.AddHook(hook)
+	}
+	if c.config.Metrics != nil {
+		c.config.Metrics.addPool(c)
+	}
+
+	return nil
+}
+
+// nodeClient creates a dedicated RESP2 client with a small pool to the
+// standalone server or sentinel master, for features that need their own
+// connections such as client-side tracking
+func (c *Client) nodeClient(name string, poolSize int) (*redis.Client, error) {
+	switch c.config.Mode() {
+	case ModeCluster:
+		return nil, fmt.Errorf("not supported in cluster mode")
+	case ModeSentinel:
+		tlsConfig, err := c.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		opts := c.failoverOptions(tlsConfig)
+		opts.ClientName = name
+		opts.Protocol = 2
+		opts.PoolSize = poolSize
+		opts.MinIdleConns = 0
+		opts.OnConnect = nil
+		return redis.NewFailoverClient(opts), nil
+	default:
+		opts, err := c.redisOptions()
+		if err != nil {
+			return nil, err
+		}
+		opts.ClientName = name
+		opts.Protocol = 2
+		opts.PoolSize = poolSize
+		opts.MinIdleConns = 0
+		opts.OnConnect = nil
+		return redis.NewClient(opts), nil
+	}
+}
+
+// clusterOptions builds the go-redis cluster options from the configuration
+func (c *Client) clusterOptions(tlsConfig *tls.Config) *redis.ClusterOptions {
+	return &redis.ClusterOptions{
+		Addrs:           c.config.ClusterAddrs,
+		Password:        c.config.Password,
+		ReadOnly:        c.config.ReadFromReplica,
+		RouteByLatency:  c.config.RouteByLatency,
+		MaxRetries:      c.config.MaxRetries,
+		MinIdleConns:    c.config.MinIdleConns,
+		MaxIdleConns:    c.config.MaxIdleConns,
+		PoolSize:        c.config.PoolSize,
+		PoolTimeout:     c.config.PoolTimeout,
+		ConnMaxIdleTime: c.config.ConnMaxIdleTime,
+		ConnMaxLifetime: c.config.ConnMaxLifetime,
+		DialTimeout:     c.config.DialTimeout,
+		ReadTimeout:     c.config.ReadTimeout,
+		WriteTimeout:    c.config.WriteTimeout,
+		TLSConfig:       tlsConfig,
+		OnConnect:       c.preloadScripts,
+	}
+}
+
+// failoverOptions builds the go-redis sentinel options from the configuration
+func (c *Client) failoverOptions(tlsConfig *tls.Config) *redis.FailoverOptions {
+	return &redis.FailoverOptions{
+		MasterName:       c.config.MasterName,
+		SentinelAddrs:    c.config.SentinelAddrs,
+		SentinelPassword: c.config.SentinelPassword,
+		Password:         c.config.Password,
+		DB:               c.config.DB,
+		MaxRetries:       c.config.MaxRetries,
+		MinIdleConns:     c.config.MinIdleConns,
+		MaxIdleConns:     c.config.MaxIdleConns,
+		PoolSize:         c.config.PoolSize,
+		PoolTimeout:      c.config.PoolTimeout,
+		ConnMaxIdleTime:  c.config.ConnMaxIdleTime,
+		ConnMaxLifetime:  c.config.ConnMaxLifetime,
+		DialTimeout:      c.config.DialTimeout,
+		ReadTimeout:      c.config.ReadTimeout,
+		WriteTimeout:     c.config.WriteTimeout,
+		TLSConfig:        tlsConfig,
+		OnConnect:        c.preloadScripts,
+	}
+}
+
+// tlsConfig returns the TLS configuration, or nil if TLS is disabled
+func (c *Client) tlsConfig() (*tls.Config, error) {
+	if !c.config.TLSEnabled {
+		return nil, nil
+	}
+
+	tlsConfig, err := c.createTLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TLS config: %w", err)
+	}
+	return tlsConfig, nil
+}
+
+// redisOptions builds the go-redis options from the configuration
+func (c *Client) redisOptions() (*redis.Options, error) {
 	opts := &redis.Options{
 		Addr:            c.config.Addr,
 		Password:        c.config.Password,
@@ -68,19 +209,17 @@ func (c *Client) connect() error {
 		DialTimeout:     c.config.DialTimeout,
 		ReadTimeout:     c.config.ReadTimeout,
 		WriteTimeout:    c.config.WriteTimeout,
+		OnConnect:       c.preloadScripts,
 	}
 
 	// Configure TLS if enabled
-	if c.config.TLSEnabled {
-		tlsConfig, err := c.createTLSConfig()
-		if err != nil {
-			return fmt.Errorf("failed to create TLS config: %w", err)
-		}
-		opts.TLSConfig = tlsConfig
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
 	}
+	opts.TLSConfig = tlsConfig
 
-	c.client = redis.NewClient(opts)
-	return nil
+	return opts, nil
 }
 
 // createTLSConfig creates TLS configuration
@@ -123,16 +262,46 @@ func (c *Client) Close() error {
 		return ErrAlreadyClosed
 	}
 
+	var final PoolStats
 	if c.client != nil {
+		final = poolStatsOf(c.client)
 		if err := c.client.Close(); err != nil {
 			return fmt.Errorf("failed to close redis client: %w", err)
 		}
 	}
 
+	if c.config.Metrics != nil {
+		c.config.Metrics.removePool(c, final)
+	}
+
 	c.closed = true
 	return nil
 }
 
+// blocking runs a command that can wait on the server for a long time. The
+// lock is not held while it waits, so Close and other commands are not held
+// up. A command interrupted by Close returns ErrClientClosed.
+func (c *Client) blocking(run func(client redis.UniversalClient) error) error {
+	c.mu.RLock()
+	client, closed := c.client, c.closed
+	c.mu.RUnlock()
+
+	if closed {
+		return ErrClientClosed
+	}
+
+	err := run(client)
+	if err != nil && !IsNil(err) && err != ErrInvalidKey {
+		c.mu.RLock()
+		closed = c.closed
+		c.mu.RUnlock()
+		if closed {
+			return ErrClientClosed
+		}
+	}
+	return err
+}
+
 // Ping checks if the connection is alive
 func (c *Client) Ping(ctx context.Context) error {
 	c.mu.RLock()
@@ -145,16 +314,29 @@ func (c *Client) Ping(ctx context.Context) error {
 	return c.client.Ping(ctx).Err()
 }
 
-// Client returns the underlying go-redis client for advanced operations
+// Client returns the underlying go-redis client for advanced operations. It
+// is nil in cluster mode and with replica routing in sentinel mode; use
+// UniversalClient there.
 func (c *Client) Client() *redis.Client {
+	client, _ := c.client.(*redis.Client)
+	return client
+}
+
+// UniversalClient returns the underlying go-redis client for any mode
+func (c *Client) UniversalClient() redis.UniversalClient {
 	return c.client
 }
 
+// Mode returns the deployment mode the client is connected to
+func (c *Client) Mode() Mode {
+	return c.config.Mode()
+}
+
 // ====================
 // String Operations
 // ====================
 
-// Get retrieves the value of a key
+// Get retrieves the value of a key. It returns ErrNil if the key does not exist.
 func (c *Client) Get(ctx context.Context, key string) (string, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -171,7 +353,7 @@ func (c *Client) Get(ctx context.Context, key string) (string, error) {
 }
 
 // Set sets the value of a key with optional TTL
-func (c *Client) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+func (c *Client) Set(ctx context.Context, key string, value interface{}, ttl ...time.Duration) error {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -183,7 +365,12 @@ func (c *Client) Set(ctx context.Context, key string, value interface{}, ttl tim
 		return ErrInvalidKey
 	}
 
-	return c.client.Set(ctx, key, value, ttl).Err()
+	var duration time.Duration
+	if len(ttl) > 0 {
+		duration = ttl[0]
+	}
+
+	return c.client.Set(ctx, key, value, duration).Err()
 }
 
 // SetNX sets the value of a key only if it does not exist
@@ -222,7 +409,8 @@ func (c *Client) SetEX(ctx context.Context, key string, value interface{}, ttl t
 	return c.client.SetEx(ctx, key, value, ttl).Err()
 }
 
-// GetSet sets a new value and returns the old value
+// GetSet sets a new value and returns the old value. It returns ErrNil if the
+// key did not exist.
 func (c *Client) GetSet(ctx context.Context, key string, value interface{}) (string, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -238,7 +426,7 @@ func (c *Client) GetSet(ctx context.Context, key string, value interface{}) (str
 	return c.client.GetSet(ctx, key, value).Result()
 }
 
-// MGet retrieves values of multiple keys
+// MGet retrieves values of multiple keys. Missing keys are nil.
 func (c *Client) MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -326,52 +514,46 @@ func (c *Client) DecrBy(ctx context.Context, key string, value int64) (int64, er
 	return c.client.DecrBy(ctx, key, value).Result()
 }
 
-// ====================
-// Key Operations
-// ====================
-
-// Del deletes one or more keys
-func (c *Client) Del(ctx context.Context, keys ...string) (int64, error) {
+// GetDel gets the value of a key and deletes it. It returns ErrNil if the key
+// does not exist.
+func (c *Client) GetDel(ctx context.Context, key string) (string, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	if c.closed {
-		return 0, ErrClientClosed
+		return "", ErrClientClosed
 	}
 
-	return c.client.Del(ctx, keys...).Result()
-}
-
-// Exists checks if one or more keys exist
-func (c *Client) Exists(ctx context.Context, keys ...string) (int64, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	if c.closed {
-		return 0, ErrClientClosed
+	if key == "" {
+		return "", ErrInvalidKey
 	}
 
-	return c.client.Exists(ctx, keys...).Result()
+	return c.client.GetDel(ctx, key).Result()
 }
 
-// Expire sets a key's time to live in seconds
-func (c *Client) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+// GetEx gets the value of a key and sets its TTL. A ttl of 0 leaves the
+// expiration unchanged. It returns ErrNil if the key does not exist.
+func (c *Client) GetEx(ctx context.Context, key string, ttl time.Duration) (string, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	if c.closed {
-		return false, ErrClientClosed
+		return "", ErrClientClosed
 	}
 
 	if key == "" {
-		return false, ErrInvalidKey
+		return "", ErrInvalidKey
 	}
 
-	return c.client.Expire(ctx, key, ttl).Result()
+	if ttl < 0 {
+		return "", ErrInvalidTTL
+	}
+
+	return c.client.GetEx(ctx, key, ttl).Result()
 }
 
-// TTL returns the time to live for a key
-func (c *Client) TTL(ctx context.Context, key string) (time.Duration, error) {
+// IncrByFloat increments the float value of a key by the given amount
+func (c *Client) IncrByFloat(ctx context.Context, key string, value float64) (float64, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -383,43 +565,47 @@ func (c *Client) TTL(ctx context.Context, key string) (time.Duration, error) {
 		return 0, ErrInvalidKey
 	}
 
-	return c.client.TTL(ctx, key).Result()
+	return c.client.IncrByFloat(ctx, key, value).Result()
 }
 
-// Persist removes the expiration from a key
-func (c *Client) Persist(ctx context.Context, key string) (bool, error) {
+// Append appends a value to a string and returns its new length
+func (c *Client) Append(ctx context.Context, key, value string) (int64, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	if c.closed {
-		return false, ErrClientClosed
+		return 0, ErrClientClosed
 	}
 
 	if key == "" {
-		return false, ErrInvalidKey
+		return 0, ErrInvalidKey
 	}
 
-	return c.client.Persist(ctx, key).Result()
+	return c.client.Append(ctx, key, value).Result()
 }
 
-// Keys returns all keys matching a pattern
-func (c *Client) Keys(ctx context.Context, pattern string) ([]string, error) {
+// StrLen returns the length of a string value
+func (c *Client) StrLen(ctx context.Context, key string) (int64, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	if c.closed {
-		return nil, ErrClientClosed
+		return 0, ErrClientClosed
 	}
 
-	return c.client.Keys(ctx, pattern).Result()
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.StrLen(ctx, key).Result()
 }
 
 // ====================
-// Hash Operations
+// Bitmap Operations
 // ====================
 
-// HSet sets field in the hash stored at key to value
-func (c *Client) HSet(ctx context.Context, key string, values ...interface{}) (int64, error) {
+// SetBit sets the bit at offset to value (0 or 1) and returns the previous bit
+func (c *Client) SetBit(ctx context.Context, key string, offset int64, value int) (int64, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -431,43 +617,50 @@ func (c *Client) HSet(ctx context.Context, key string, values ...interface{}) (i
 		return 0, ErrInvalidKey
 	}
 
-	return c.client.HSet(ctx, key, values...).Result()
+	if value != 0 && value != 1 {
+		return 0, fmt.Errorf("bit value must be 0 or 1")
+	}
+
+	return c.client.SetBit(ctx, key, offset, value).Result()
 }
 
-// HGet returns the value associated with field in the hash
-func (c *Client) HGet(ctx context.Context, key, field string) (string, error) {
+// GetBit returns the bit at offset
+func (c *Client) GetBit(ctx context.Context, key string, offset int64) (int64, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	if c.closed {
-		return "", ErrClientClosed
+		return 0, ErrClientClosed
 	}
 
 	if key == "" {
-		return "", ErrInvalidKey
+		return 0, ErrInvalidKey
 	}
 
-	return c.client.HGet(ctx, key, field).Result()
+	return c.client.GetBit(ctx, key, offset).Result()
 }
 
-// HGetAll returns all fields and values in a hash
-func (c *Client) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+// BitCount counts the set bits in a string. A nil bitCount counts the whole
+// string; otherwise Start and End select a byte range, or a bit range if Unit
+// is "BIT".
+func (c *Client) BitCount(ctx context.Context, key string, bitCount *redis.BitCount) (int64, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	if c.closed {
-		return nil, ErrClientClosed
+		return 0, ErrClientClosed
 	}
 
 	if key == "" {
-		return nil, ErrInvalidKey
+		return 0, ErrInvalidKey
 	}
 
-	return c.client.HGetAll(ctx, key).Result()
+	return c.client.BitCount(ctx, key, bitCount).Result()
 }
 
-// HDel deletes one or more hash fields
-func (c *Client) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+// BitPos returns the position of the first bit set to bit (0 or 1), optionally
+// within a start and end byte
+func (c *Client) BitPos(ctx context.Context, key string, bit int64, pos ...int64) (int64, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -479,27 +672,46 @@ func (c *Client) HDel(ctx context.Context, key string, fields ...string) (int64,
 		return 0, ErrInvalidKey
 	}
 
-	return c.client.HDel(ctx, key, fields...).Result()
+	return c.client.BitPos(ctx, key, bit, pos...).Result()
 }
 
-// HExists determines if a hash field exists
-func (c *Client) HExists(ctx context.Context, key, field string) (bool, error) {
+// BitOp stores the result of a bitwise AND, OR, XOR or NOT of the given keys
+// in destKey and returns its length. NOT takes exactly one key.
+func (c *Client) BitOp(ctx context.Context, op, destKey string, keys ...string) (int64, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	if c.closed {
-		return false, ErrClientClosed
+		return 0, ErrClientClosed
 	}
 
-	if key == "" {
-		return false, ErrInvalidKey
+	if destKey == "" || len(keys) == 0 {
+		return 0, ErrInvalidKey
 	}
 
-	return c.client.HExists(ctx, key, field).Result()
+	switch strings.ToUpper(op) {
+	case "AND":
+		return c.client.BitOpAnd(ctx, destKey, keys...).Result()
+	case "OR":
+		return c.client.BitOpOr(ctx, destKey, keys...).Result()
+	case "XOR":
+		return c.client.BitOpXor(ctx, destKey, keys...).Result()
+	case "NOT":
+		if len(keys) != 1 {
+			return 0, fmt.Errorf("BITOP NOT takes exactly one key")
+		}
+		return c.client.BitOpNot(ctx, destKey, keys[0]).Result()
+	default:
+		return 0, fmt.Errorf("unknown BITOP operation %q", op)
+	}
 }
 
-// HIncrBy increments the integer value of a hash field
-func (c *Client) HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error) {
+// ====================
+// Key Operations
+// ====================
+
+// Del deletes one or more keys
+func (c *Client) Del(ctx context.Context, keys ...string) (int64, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -507,31 +719,39 @@ func (c *Client) HIncrBy(ctx context.Context, key, field string, incr int64) (in
 		return 0, ErrClientClosed
 	}
 
-	if key == "" {
-		return 0, ErrInvalidKey
+	return c.client.Del(ctx, keys...).Result()
+}
+
+// Exists checks if one or more keys exist
+func (c *Client) Exists(ctx context.Context, keys ...string) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
 	}
 
-	return c.client.HIncrBy(ctx, key, field, incr).Result()
+	return c.client.Exists(ctx, keys...).Result()
 }
 
-// HKeys returns all field names in a hash
-func (c *Client) HKeys(ctx context.Context, key string) ([]string, error) {
+// Expire sets a key's time to live in seconds
+func (c *Client) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	if c.closed {
-		return nil, ErrClientClosed
+		return false, ErrClientClosed
 	}
 
 	if key == "" {
-		return nil, ErrInvalidKey
+		return false, ErrInvalidKey
 	}
 
-	return c.client.HKeys(ctx, key).Result()
+	return c.client.Expire(ctx, key, ttl).Result()
 }
 
-// HLen returns the number of fields in a hash
-func (c *Client) HLen(ctx context.Context, key string) (int64, error) {
+// TTL returns the time to live for a key
+func (c *Client) TTL(ctx context.Context, key string) (time.Duration, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -543,47 +763,53 @@ func (c *Client) HLen(ctx context.Context, key string) (int64, error) {
 		return 0, ErrInvalidKey
 	}
 
-	return c.client.HLen(ctx, key).Result()
+	return c.client.TTL(ctx, key).Result()
 }
 
-// ====================
-// List Operations
-// ====================
-
-// LPush inserts all the specified values at the head of the list
-func (c *Client) LPush(ctx context.Context, key string, values ...interface{}) (int64, error) {
+// Persist removes the expiration from a key
+func (c *Client) Persist(ctx context.Context, key string) (bool, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	if c.closed {
-		return 0, ErrClientClosed
+		return false, ErrClientClosed
 	}
 
 	if key == "" {
-		return 0, ErrInvalidKey
+		return false, ErrInvalidKey
 	}
 
-	return c.client.LPush(ctx, key, values...).Result()
+	return c.client.Persist(ctx, key).Result()
 }
 
-// RPush inserts all the specified values at the tail of the list
-func (c *Client) RPush(ctx context.Context, key string, values ...interface{}) (int64, error) {
+// Keys returns all keys matching a pattern. KEYS blocks Redis while it walks the
+// whole keyspace; use ScanIter in production.
+func (c *Client) Keys(ctx context.Context, pattern string) ([]string, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	if c.closed {
-		return 0, ErrClientClosed
+		return nil, ErrClientClosed
 	}
 
-	if key == "" {
-		return 0, ErrInvalidKey
+	return c.client.Keys(ctx, pattern).Result()
+}
+
+// Unlink deletes keys like Del, but reclaims memory in the background
+func (c *Client) Unlink(ctx context.Context, keys ...string) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
 	}
 
-	return c.client.RPush(ctx, key, values...).Result()
+	return c.client.Unlink(ctx, keys...).Result()
 }
 
-// LPop removes and returns the first element of the list
-func (c *Client) LPop(ctx context.Context, key string) (string, error) {
+// Type returns the type of the value stored at key, or "none" if the key does
+// not exist
+func (c *Client) Type(ctx context.Context, key string) (string, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -595,43 +821,47 @@ func (c *Client) LPop(ctx context.Context, key string) (string, error) {
 		return "", ErrInvalidKey
 	}
 
-	return c.client.LPop(ctx, key).Result()
+	return c.client.Type(ctx, key).Result()
 }
 
-// RPop removes and returns the last element of the list
-func (c *Client) RPop(ctx context.Context, key string) (string, error) {
+// Rename renames a key, overwriting newKey if it exists
+func (c *Client) Rename(ctx context.Context, key, newKey string) error {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	if c.closed {
-		return "", ErrClientClosed
+		return ErrClientClosed
 	}
 
 	if key == "" {
-		return "", ErrInvalidKey
+		return ErrInvalidKey
 	}
 
-	return c.client.RPop(ctx, key).Result()
+	if newKey == "" {
+		return ErrInvalidKey
+	}
+
+	return c.client.Rename(ctx, key, newKey).Err()
 }
 
-// LRange returns the specified elements of the list
-func (c *Client) LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+// ExpireAt sets a key to expire at the given time
+func (c *Client) ExpireAt(ctx context.Context, key string, tm time.Time) (bool, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	if c.closed {
-		return nil, ErrClientClosed
+		return false, ErrClientClosed
 	}
 
 	if key == "" {
-		return nil, ErrInvalidKey
+		return false, ErrInvalidKey
 	}
 
-	return c.client.LRange(ctx, key, start, stop).Result()
+	return c.client.ExpireAt(ctx, key, tm).Result()
 }
 
-// LLen returns the length of the list
-func (c *Client) LLen(ctx context.Context, key string) (int64, error) {
+// PTTL returns the time to live for a key with millisecond precision
+func (c *Client) PTTL(ctx context.Context, key string) (time.Duration, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -643,15 +873,15 @@ func (c *Client) LLen(ctx context.Context, key string) (int64, error) {
 		return 0, ErrInvalidKey
 	}
 
-	return c.client.LLen(ctx, key).Result()
+	return c.client.PTTL(ctx, key).Result()
 }
 
 // ====================
-// Set Operations
+// Hash Operations
 // ====================
 
-// SAdd adds one or more members to a set
-func (c *Client) SAdd(ctx context.Context, key string, members ...interface{}) (int64, error) {
+// HSet sets field in the hash stored at key to value
+func (c *Client) HSet(ctx context.Context, key string, values ...interface{}) (int64, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -663,43 +893,637 @@ func (c *Client) SAdd(ctx context.Context, key string, members ...interface{}) (
 		return 0, ErrInvalidKey
 	}
 
-	return c.client.SAdd(ctx, key, members...).Result()
+	return c.client.HSet(ctx, key, values...).Result()
 }
 
-// SMembers returns all members of a set
-func (c *Client) SMembers(ctx context.Context, key string) ([]string, error) {
+// HGet returns the value associated with field in the hash. It returns ErrNil
+// if the key or field does not exist.
+func (c *Client) HGet(ctx context.Context, key, field string) (string, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	if c.closed {
-		return nil, ErrClientClosed
+		return "", ErrClientClosed
 	}
 
 	if key == "" {
-		return nil, ErrInvalidKey
+		return "", ErrInvalidKey
 	}
 
-	return c.client.SMembers(ctx, key).Result()
+	return c.client.HGet(ctx, key, field).Result()
 }
 
-// SIsMember checks if a member exists in a set
-func (c *Client) SIsMember(ctx context.Context, key string, member interface{}) (bool, error) {
+// HGetAll returns all fields and values in a hash
+func (c *Client) HGetAll(ctx context.Context, key string) (map[string]string, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	if c.closed {
-		return false, ErrClientClosed
+		return nil, ErrClientClosed
+	}
+
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	return c.client.HGetAll(ctx, key).Result()
+}
+
+// HDel deletes one or more hash fields
+func (c *Client) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.HDel(ctx, key, fields...).Result()
+}
+
+// HExists determines if a hash field exists
+func (c *Client) HExists(ctx context.Context, key, field string) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return false, ErrClientClosed
+	}
+
+	if key == "" {
+		return false, ErrInvalidKey
+	}
+
+	return c.client.HExists(ctx, key, field).Result()
+}
+
+// HIncrBy increments the integer value of a hash field
+func (c *Client) HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.HIncrBy(ctx, key, field, incr).Result()
+}
+
+// HKeys returns all field names in a hash
+func (c *Client) HKeys(ctx context.Context, key string) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	return c.client.HKeys(ctx, key).Result()
+}
+
+// HLen returns the number of fields in a hash
+func (c *Client) HLen(ctx context.Context, key string) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.HLen(ctx, key).Result()
+}
+
+// HMGet returns the values of hash fields. Missing fields are nil.
+func (c *Client) HMGet(ctx context.Context, key string, fields ...string) ([]interface{}, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	return c.client.HMGet(ctx, key, fields...).Result()
+}
+
+// HSetNX sets a hash field only if it does not exist
+func (c *Client) HSetNX(ctx context.Context, key, field string, value interface{}) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return false, ErrClientClosed
+	}
+
+	if key == "" {
+		return false, ErrInvalidKey
+	}
+
+	return c.client.HSetNX(ctx, key, field, value).Result()
+}
+
+// HVals returns all values in a hash
+func (c *Client) HVals(ctx context.Context, key string) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	return c.client.HVals(ctx, key).Result()
+}
+
+// HIncrByFloat increments the float value of a hash field by the given amount
+func (c *Client) HIncrByFloat(ctx context.Context, key, field string, incr float64) (float64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.HIncrByFloat(ctx, key, field, incr).Result()
+}
+
+// HScan returns one page of field-value pairs and the next cursor. Iteration is
+// complete when the cursor is 0; HScanIter does the paging for you.
+func (c *Client) HScan(ctx context.Context, key string, cursor uint64, match string, count int64) ([]string, uint64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return nil, 0, ErrInvalidKey
+	}
+
+	return c.client.HScan(ctx, key, cursor, match, count).Result()
+}
+
+// ====================
+// List Operations
+// ====================
+
+// LPush inserts all the specified values at the head of the list
+func (c *Client) LPush(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.LPush(ctx, key, values...).Result()
+}
+
+// RPush inserts all the specified values at the tail of the list
+func (c *Client) RPush(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.RPush(ctx, key, values...).Result()
+}
+
+// LPop removes and returns the first element of the list. It returns ErrNil if
+// the list is empty.
+func (c *Client) LPop(ctx context.Context, key string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return "", ErrClientClosed
+	}
+
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+
+	return c.client.LPop(ctx, key).Result()
+}
+
+// RPop removes and returns the last element of the list. It returns ErrNil if
+// the list is empty.
+func (c *Client) RPop(ctx context.Context, key string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return "", ErrClientClosed
+	}
+
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+
+	return c.client.RPop(ctx, key).Result()
+}
+
+// LRange returns the specified elements of the list
+func (c *Client) LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	return c.client.LRange(ctx, key, start, stop).Result()
+}
+
+// LLen returns the length of the list
+func (c *Client) LLen(ctx context.Context, key string) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.LLen(ctx, key).Result()
+}
+
+// BLPop removes and returns the first element of the first non-empty list, as
+// a key and value pair. It blocks for up to timeout, or until ctx is done if
+// timeout is 0, and returns ErrNil if no element arrived. Close interrupts it
+// with ErrClientClosed.
+func (c *Client) BLPop(ctx context.Context, timeout time.Duration, keys ...string) ([]string, error) {
+	var result []string
+	err := c.blocking(func(client redis.UniversalClient) (err error) {
+		if len(keys) == 0 {
+			return ErrInvalidKey
+		}
+		result, err = client.BLPop(ctx, timeout, keys...).Result()
+		return err
+	})
+	return result, err
+}
+
+// BRPop removes and returns the last element of the first non-empty list, as
+// a key and value pair. It blocks like BLPop.
+func (c *Client) BRPop(ctx context.Context, timeout time.Duration, keys ...string) ([]string, error) {
+	var result []string
+	err := c.blocking(func(client redis.UniversalClient) (err error) {
+		if len(keys) == 0 {
+			return ErrInvalidKey
+		}
+		result, err = client.BRPop(ctx, timeout, keys...).Result()
+		return err
+	})
+	return result, err
+}
+
+// LTrim trims a list to the elements from start to stop, inclusive
+func (c *Client) LTrim(ctx context.Context, key string, start, stop int64) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return ErrClientClosed
+	}
+
+	if key == "" {
+		return ErrInvalidKey
+	}
+
+	return c.client.LTrim(ctx, key, start, stop).Err()
+}
+
+// LRem removes count occurrences of value: from the head if count is positive,
+// from the tail if negative, and all of them if 0
+func (c *Client) LRem(ctx context.Context, key string, count int64, value interface{}) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.LRem(ctx, key, count, value).Result()
+}
+
+// LIndex returns the element at index. Negative indexes count from the end. It
+// returns ErrNil if the index is out of range.
+func (c *Client) LIndex(ctx context.Context, key string, index int64) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return "", ErrClientClosed
+	}
+
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+
+	return c.client.LIndex(ctx, key, index).Result()
+}
+
+// LSet sets the element at index
+func (c *Client) LSet(ctx context.Context, key string, index int64, value interface{}) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return ErrClientClosed
+	}
+
+	if key == "" {
+		return ErrInvalidKey
+	}
+
+	return c.client.LSet(ctx, key, index, value).Err()
+}
+
+// ====================
+// Set Operations
+// ====================
+
+// SAdd adds one or more members to a set
+func (c *Client) SAdd(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.SAdd(ctx, key, members...).Result()
+}
+
+// SMembers returns all members of a set
+func (c *Client) SMembers(ctx context.Context, key string) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	return c.client.SMembers(ctx, key).Result()
+}
+
+// SIsMember checks if a member exists in a set
+func (c *Client) SIsMember(ctx context.Context, key string, member interface{}) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return false, ErrClientClosed
+	}
+
+	if key == "" {
+		return false, ErrInvalidKey
+	}
+
+	return c.client.SIsMember(ctx, key, member).Result()
+}
+
+// SRem removes one or more members from a set
+func (c *Client) SRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.SRem(ctx, key, members...).Result()
+}
+
+// SCard returns the number of members in a set
+func (c *Client) SCard(ctx context.Context, key string) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.SCard(ctx, key).Result()
+}
+
+// SInter returns the members present in all of the given sets
+func (c *Client) SInter(ctx context.Context, keys ...string) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrInvalidKey
+	}
+
+	return c.client.SInter(ctx, keys...).Result()
+}
+
+// SUnion returns the members present in any of the given sets
+func (c *Client) SUnion(ctx context.Context, keys ...string) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrInvalidKey
+	}
+
+	return c.client.SUnion(ctx, keys...).Result()
+}
+
+// SDiff returns the members of the first set that are in none of the others
+func (c *Client) SDiff(ctx context.Context, keys ...string) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrInvalidKey
+	}
+
+	return c.client.SDiff(ctx, keys...).Result()
+}
+
+// SPop removes and returns a random member. It returns ErrNil if the set is
+// empty.
+func (c *Client) SPop(ctx context.Context, key string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return "", ErrClientClosed
+	}
+
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+
+	return c.client.SPop(ctx, key).Result()
+}
+
+// SRandMember returns a random member without removing it. It returns ErrNil
+// if the set is empty.
+func (c *Client) SRandMember(ctx context.Context, key string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return "", ErrClientClosed
+	}
+
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+
+	return c.client.SRandMember(ctx, key).Result()
+}
+
+// SMIsMember checks membership of several members at once
+func (c *Client) SMIsMember(ctx context.Context, key string, members ...interface{}) ([]bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	return c.client.SMIsMember(ctx, key, members...).Result()
+}
+
+// ====================
+// Sorted Set Operations
+// ====================
+
+// ZAdd adds one or more members to a sorted set
+func (c *Client) ZAdd(ctx context.Context, key string, members ...redis.Z) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.ZAdd(ctx, key, members...).Result()
+}
+
+// ZRange returns the specified range of elements in a sorted set
+func (c *Client) ZRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	return c.client.ZRange(ctx, key, start, stop).Result()
+}
+
+// ZRangeWithScores returns the specified range with scores
+func (c *Client) ZRangeWithScores(ctx context.Context, key string, start, stop int64) ([]redis.Z, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
 	}
 
 	if key == "" {
-		return false, ErrInvalidKey
+		return nil, ErrInvalidKey
 	}
 
-	return c.client.SIsMember(ctx, key, member).Result()
+	return c.client.ZRangeWithScores(ctx, key, start, stop).Result()
 }
 
-// SRem removes one or more members from a set
-func (c *Client) SRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+// ZRem removes one or more members from a sorted set
+func (c *Client) ZRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -711,11 +1535,11 @@ func (c *Client) SRem(ctx context.Context, key string, members ...interface{}) (
 		return 0, ErrInvalidKey
 	}
 
-	return c.client.SRem(ctx, key, members...).Result()
+	return c.client.ZRem(ctx, key, members...).Result()
 }
 
-// SCard returns the number of members in a set
-func (c *Client) SCard(ctx context.Context, key string) (int64, error) {
+// ZCard returns the number of members in a sorted set
+func (c *Client) ZCard(ctx context.Context, key string) (int64, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -727,15 +1551,29 @@ func (c *Client) SCard(ctx context.Context, key string) (int64, error) {
 		return 0, ErrInvalidKey
 	}
 
-	return c.client.SCard(ctx, key).Result()
+	return c.client.ZCard(ctx, key).Result()
 }
 
-// ====================
-// Sorted Set Operations
-// ====================
+// ZScore returns the score of a member in a sorted set. It returns ErrNil if
+// the member does not exist.
+func (c *Client) ZScore(ctx context.Context, key, member string) (float64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
-// ZAdd adds one or more members to a sorted set
-func (c *Client) ZAdd(ctx context.Context, key string, members ...redis.Z) (int64, error) {
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.ZScore(ctx, key, member).Result()
+}
+
+// ZIncrBy increments the score of a member, adding it if needed, and returns
+// the new score
+func (c *Client) ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -747,11 +1585,11 @@ func (c *Client) ZAdd(ctx context.Context, key string, members ...redis.Z) (int6
 		return 0, ErrInvalidKey
 	}
 
-	return c.client.ZAdd(ctx, key, members...).Result()
+	return c.client.ZIncrBy(ctx, key, increment, member).Result()
 }
 
-// ZRange returns the specified range of elements in a sorted set
-func (c *Client) ZRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+// ZRevRange returns members by rank from highest to lowest score
+func (c *Client) ZRevRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -763,11 +1601,12 @@ func (c *Client) ZRange(ctx context.Context, key string, start, stop int64) ([]s
 		return nil, ErrInvalidKey
 	}
 
-	return c.client.ZRange(ctx, key, start, stop).Result()
+	return c.client.ZRevRange(ctx, key, start, stop).Result()
 }
 
-// ZRangeWithScores returns the specified range with scores
-func (c *Client) ZRangeWithScores(ctx context.Context, key string, start, stop int64) ([]redis.Z, error) {
+// ZRevRangeWithScores returns members and scores by rank from highest to
+// lowest score
+func (c *Client) ZRevRangeWithScores(ctx context.Context, key string, start, stop int64) ([]redis.Z, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -779,11 +1618,76 @@ func (c *Client) ZRangeWithScores(ctx context.Context, key string, start, stop i
 		return nil, ErrInvalidKey
 	}
 
-	return c.client.ZRangeWithScores(ctx, key, start, stop).Result()
+	return c.client.ZRevRangeWithScores(ctx, key, start, stop).Result()
 }
 
-// ZRem removes one or more members from a sorted set
-func (c *Client) ZRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+// ZRangeByScore returns members with scores between opt.Min and opt.Max, from
+// lowest to highest. Use "-inf" and "+inf" for open ends and a "(" prefix
+// for exclusive bounds.
+func (c *Client) ZRangeByScore(ctx context.Context, key string, opt *redis.ZRangeBy) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	if opt == nil {
+		return nil, fmt.Errorf("range options are required")
+	}
+
+	return c.client.ZRangeByScore(ctx, key, opt).Result()
+}
+
+// ZRangeByScoreWithScores returns members and scores between opt.Min and
+// opt.Max, from lowest to highest
+func (c *Client) ZRangeByScoreWithScores(ctx context.Context, key string, opt *redis.ZRangeBy) ([]redis.Z, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	if opt == nil {
+		return nil, fmt.Errorf("range options are required")
+	}
+
+	return c.client.ZRangeByScoreWithScores(ctx, key, opt).Result()
+}
+
+// ZRevRangeByScore returns members with scores between opt.Max and opt.Min,
+// from highest to lowest
+func (c *Client) ZRevRangeByScore(ctx context.Context, key string, opt *redis.ZRangeBy) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	if opt == nil {
+		return nil, fmt.Errorf("range options are required")
+	}
+
+	return c.client.ZRevRangeByScore(ctx, key, opt).Result()
+}
+
+// ZRank returns the rank of a member, lowest score first. It returns ErrNil if
+// the member does not exist.
+func (c *Client) ZRank(ctx context.Context, key, member string) (int64, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -795,11 +1699,12 @@ func (c *Client) ZRem(ctx context.Context, key string, members ...interface{}) (
 		return 0, ErrInvalidKey
 	}
 
-	return c.client.ZRem(ctx, key, members...).Result()
+	return c.client.ZRank(ctx, key, member).Result()
 }
 
-// ZCard returns the number of members in a sorted set
-func (c *Client) ZCard(ctx context.Context, key string) (int64, error) {
+// ZRevRank returns the rank of a member, highest score first. It returns
+// ErrNil if the member does not exist.
+func (c *Client) ZRevRank(ctx context.Context, key, member string) (int64, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -811,11 +1716,11 @@ func (c *Client) ZCard(ctx context.Context, key string) (int64, error) {
 		return 0, ErrInvalidKey
 	}
 
-	return c.client.ZCard(ctx, key).Result()
+	return c.client.ZRevRank(ctx, key, member).Result()
 }
 
-// ZScore returns the score of a member in a sorted set
-func (c *Client) ZScore(ctx context.Context, key, member string) (float64, error) {
+// ZCount counts members with scores between min and max
+func (c *Client) ZCount(ctx context.Context, key, min, max string) (int64, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -827,7 +1732,203 @@ func (c *Client) ZScore(ctx context.Context, key, member string) (float64, error
 		return 0, ErrInvalidKey
 	}
 
-	return c.client.ZScore(ctx, key, member).Result()
+	return c.client.ZCount(ctx, key, min, max).Result()
+}
+
+// ZRemRangeByScore removes members with scores between min and max
+func (c *Client) ZRemRangeByScore(ctx context.Context, key, min, max string) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.ZRemRangeByScore(ctx, key, min, max).Result()
+}
+
+// ZRemRangeByRank removes members by rank from start to stop, inclusive
+func (c *Client) ZRemRangeByRank(ctx context.Context, key string, start, stop int64) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.ZRemRangeByRank(ctx, key, start, stop).Result()
+}
+
+// ====================
+// HyperLogLog Operations
+// ====================
+
+// PFAdd adds elements to a HyperLogLog and returns 1 if its estimate changed
+func (c *Client) PFAdd(ctx context.Context, key string, elements ...interface{}) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.PFAdd(ctx, key, elements...).Result()
+}
+
+// PFCount returns the approximate number of distinct elements in the union of
+// the given HyperLogLogs
+func (c *Client) PFCount(ctx context.Context, keys ...string) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if len(keys) == 0 {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.PFCount(ctx, keys...).Result()
+}
+
+// PFMerge merges HyperLogLogs into destKey
+func (c *Client) PFMerge(ctx context.Context, destKey string, keys ...string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return ErrClientClosed
+	}
+
+	if destKey == "" {
+		return ErrInvalidKey
+	}
+
+	return c.client.PFMerge(ctx, destKey, keys...).Err()
+}
+
+// ====================
+// Geo Operations
+// ====================
+
+// GeoAdd adds members with their longitude and latitude
+func (c *Client) GeoAdd(ctx context.Context, key string, locations ...*redis.GeoLocation) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.GeoAdd(ctx, key, locations...).Result()
+}
+
+// GeoPos returns the positions of members. Missing members are nil.
+func (c *Client) GeoPos(ctx context.Context, key string, members ...string) ([]*redis.GeoPos, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	return c.client.GeoPos(ctx, key, members...).Result()
+}
+
+// GeoDist returns the distance between two members in unit ("m", "km", "mi"
+// or "ft"; empty means meters). It returns ErrNil if a member does not exist.
+func (c *Client) GeoDist(ctx context.Context, key, member1, member2, unit string) (float64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.GeoDist(ctx, key, member1, member2, unit).Result()
+}
+
+// GeoHash returns the geohash strings of members
+func (c *Client) GeoHash(ctx context.Context, key string, members ...string) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	return c.client.GeoHash(ctx, key, members...).Result()
+}
+
+// GeoSearch returns the members within a radius or box around a member or a
+// position
+func (c *Client) GeoSearch(ctx context.Context, key string, query *redis.GeoSearchQuery) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	if query == nil {
+		return nil, fmt.Errorf("search query is required")
+	}
+
+	return c.client.GeoSearch(ctx, key, query).Result()
+}
+
+// GeoSearchLocation is GeoSearch returning coordinates, distances and hashes
+// as requested by the query
+func (c *Client) GeoSearchLocation(ctx context.Context, key string, query *redis.GeoSearchLocationQuery) ([]redis.GeoLocation, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	if query == nil {
+		return nil, fmt.Errorf("search query is required")
+	}
+
+	return c.client.GeoSearchLocation(ctx, key, query).Result()
 }
 
 // ====================
@@ -860,13 +1961,30 @@ func (c *Client) Publish(ctx context.Context, channel string, message interface{
 	return c.client.Publish(ctx, channel, message).Result()
 }
 
-// Subscribe subscribes to the given channels
+// Subscribe subscribes to the given channels. It returns the go-redis PubSub
+// unchanged, so with a key prefix the Channel of received messages includes
+// it; use StripPrefix to remove it, or a Subscriber, which strips it for you.
 func (c *Client) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	if c.config.KeyPrefix != "" {
+		prefixed := make([]string, len(channels))
+		for i, channel := range channels {
+			prefixed[i] = c.PrefixKey(channel)
+		}
+		channels = prefixed
+	}
 	return c.client.Subscribe(ctx, channels...)
 }
 
-// PSubscribe subscribes to channels matching the given patterns
+// PSubscribe subscribes to channels matching the given patterns. As with
+// Subscribe, the Channel and Pattern of received messages keep the key prefix.
 func (c *Client) PSubscribe(ctx context.Context, patterns ...string) *redis.PubSub {
+	if c.config.KeyPrefix != "" {
+		prefixed := make([]string, len(patterns))
+		for i, pattern := range patterns {
+			prefixed[i] = c.prefixPattern(pattern)
+		}
+		patterns = prefixed
+	}
 	return c.client.PSubscribe(ctx, patterns...)
 }
 
@@ -883,5 +2001,22 @@ func (c *Client) Watch(ctx context.Context, fn func(*redis.Tx) error, keys ...st
 		return ErrClientClosed
 	}
 
+	// Cluster transactions run on a node client without the prefix and codec hooks
+	if c.config.Mode() == ModeCluster && (c.config.KeyPrefix != "" || c.config.ValueCodec != nil) {
+		prefixed := make([]string, len(keys))
+		for i, key := range keys {
+			prefixed[i] = c.PrefixKey(key)
+		}
+		return c.client.Watch(ctx, func(tx *redis.Tx) error {
+			if c.config.KeyPrefix != "" {
+				tx.AddHook(prefixHook{prefix: c.config.KeyPrefix})
+			}
+			if c.config.ValueCodec != nil {
+				tx.AddHook(codecHook{codec: c.config.ValueCodec})
+			}
+			return fn(tx)
+		}, prefixed...)
+	}
+
 	return c.client.Watch(ctx, fn, keys...)
 }
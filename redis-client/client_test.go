@@ -1,9 +1,16 @@
 package redisclient
 
 import (
+	"bufio"
 	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
 // TestConfig_Validate tests the configuration validation
@@ -95,6 +102,98 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "cluster without addr",
+			config: &Config{
+				ClusterAddrs: []string{"node1:6379", "node2:6379"},
+				PoolSize:     100,
+				DialTimeout:  5 * time.Second,
+			},
+			wantErr: false,
+		},
+		{
+			name: "cluster with DB",
+			config: &Config{
+				ClusterAddrs: []string{"node1:6379"},
+				DB:           1,
+				PoolSize:     100,
+				DialTimeout:  5 * time.Second,
+			},
+			wantErr: true,
+		},
+		{
+			name: "sentinel without sentinel addrs",
+			config: &Config{
+				MasterName:  "mymaster",
+				PoolSize:    100,
+				DialTimeout: 5 * time.Second,
+			},
+			wantErr: true,
+		},
+		{
+			name: "cluster and sentinel",
+			config: &Config{
+				ClusterAddrs:  []string{"node1:6379"},
+				MasterName:    "mymaster",
+				SentinelAddrs: []string{"sentinel:26379"},
+				PoolSize:      100,
+				DialTimeout:   5 * time.Second,
+			},
+			wantErr: true,
+		},
+		{
+			name: "cluster with hash tag key prefix",
+			config: &Config{
+				ClusterAddrs: []string{"node1:6379"},
+				KeyPrefix:    "{app}:",
+				PoolSize:     100,
+				DialTimeout:  5 * time.Second,
+			},
+			wantErr: true,
+		},
+		{
+			name: "pool size for unknown database name",
+			config: &Config{
+				Addr:              "localhost:6379",
+				PoolSize:          100,
+				DialTimeout:       5 * time.Second,
+				DatabasePoolSizes: map[string]int{"cache": 10},
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero database pool size",
+			config: &Config{
+				Addr:              "localhost:6379",
+				PoolSize:          100,
+				DialTimeout:       5 * time.Second,
+				DatabaseNames:     map[string]int{"cache": 1},
+				DatabasePoolSizes: map[string]int{"cache": 0},
+			},
+			wantErr: true,
+		},
+		{
+			name: "database pool size above max total conns",
+			config: &Config{
+				Addr:              "localhost:6379",
+				PoolSize:          100,
+				DialTimeout:       5 * time.Second,
+				DatabaseNames:     map[string]int{"cache": 1},
+				DatabasePoolSizes: map[string]int{"cache": 50},
+				MaxTotalConns:     20,
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative max total conns",
+			config: &Config{
+				Addr:          "localhost:6379",
+				PoolSize:      100,
+				DialTimeout:   5 * time.Second,
+				MaxTotalConns: -1,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -406,3 +505,225 @@ func TestSetEX_InvalidTTL(t *testing.T) {
 		}
 	})
 }
+
+// TestClient_DataStructureCommands tests validation of the extended command set
+func TestClient_DataStructureCommands(t *testing.T) {
+	ctx := context.Background()
+	closed := &Client{config: DefaultConfig(), closed: true}
+	open := &Client{config: DefaultConfig()}
+
+	closedCalls := map[string]func() error{
+		"GetDel":        func() error { _, err := closed.GetDel(ctx, "k"); return err },
+		"SetBit":        func() error { _, err := closed.SetBit(ctx, "k", 1, 1); return err },
+		"BitOp":         func() error { _, err := closed.BitOp(ctx, "AND", "d", "k"); return err },
+		"Unlink":        func() error { _, err := closed.Unlink(ctx, "k"); return err },
+		"HMGet":         func() error { _, err := closed.HMGet(ctx, "k", "f"); return err },
+		"HScan":         func() error { _, _, err := closed.HScan(ctx, "k", 0, "", 10); return err },
+		"BLPop":         func() error { _, err := closed.BLPop(ctx, time.Second, "k"); return err },
+		"LTrim":         func() error { return closed.LTrim(ctx, "k", 0, 1) },
+		"SInter":        func() error { _, err := closed.SInter(ctx, "a", "b"); return err },
+		"ZRangeByScore": func() error { _, err := closed.ZRangeByScore(ctx, "k", &redis.ZRangeBy{}); return err },
+		"PFMerge":       func() error { return closed.PFMerge(ctx, "d", "k") },
+		"GeoDist":       func() error { _, err := closed.GeoDist(ctx, "k", "a", "b", "km"); return err },
+	}
+	for name, call := range closedCalls {
+		if err := call(); err != ErrClientClosed {
+			t.Errorf("%s on closed client error = %v, want ErrClientClosed", name, err)
+		}
+	}
+
+	invalidKeyCalls := map[string]func() error{
+		"GetEx":       func() error { _, err := open.GetEx(ctx, "", time.Second); return err },
+		"GetBit":      func() error { _, err := open.GetBit(ctx, "", 0); return err },
+		"BitOp":       func() error { _, err := open.BitOp(ctx, "OR", "d"); return err },
+		"Rename":      func() error { return open.Rename(ctx, "k", "") },
+		"HSetNX":      func() error { _, err := open.HSetNX(ctx, "", "f", 1); return err },
+		"BRPop":       func() error { _, err := open.BRPop(ctx, time.Second); return err },
+		"LRem":        func() error { _, err := open.LRem(ctx, "", 0, "v"); return err },
+		"SUnion":      func() error { _, err := open.SUnion(ctx); return err },
+		"ZIncrBy":     func() error { _, err := open.ZIncrBy(ctx, "", 1, "m"); return err },
+		"PFCount":     func() error { _, err := open.PFCount(ctx); return err },
+		"GeoAdd":      func() error { _, err := open.GeoAdd(ctx, ""); return err },
+		"GeoSearch":   func() error { _, err := open.GeoSearch(ctx, "", nil); return err },
+		"SMIsMember":  func() error { _, err := open.SMIsMember(ctx, "", "m"); return err },
+		"ZRemByScore": func() error { _, err := open.ZRemRangeByScore(ctx, "", "0", "1"); return err },
+	}
+	for name, call := range invalidKeyCalls {
+		if err := call(); err != ErrInvalidKey {
+			t.Errorf("%s with empty key error = %v, want ErrInvalidKey", name, err)
+		}
+	}
+
+	if _, err := open.GetEx(ctx, "k", -time.Second); err != ErrInvalidTTL {
+		t.Errorf("GetEx() with negative TTL error = %v, want ErrInvalidTTL", err)
+	}
+	if _, err := open.SetBit(ctx, "k", 0, 2); err == nil {
+		t.Error("SetBit() should reject values other than 0 and 1")
+	}
+	if _, err := open.BitOp(ctx, "NAND", "d", "k"); err == nil {
+		t.Error("BitOp() should reject unknown operations")
+	}
+	if _, err := open.BitOp(ctx, "not", "d", "a", "b"); err == nil {
+		t.Error("BitOp(NOT) should require exactly one key")
+	}
+	if _, err := open.ZRangeByScore(ctx, "k", nil); err == nil {
+		t.Error("ZRangeByScore() should require range options")
+	}
+	if _, err := open.GeoSearchLocation(ctx, "k", nil); err == nil {
+		t.Error("GeoSearchLocation() should require a query")
+	}
+}
+
+// replyHook answers commands without a server. reply returns false for
+// commands that should go to the connection.
+type replyHook struct {
+	reply func(cmd redis.Cmder) (bool, error)
+}
+
+func (h replyHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h replyHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if handled, err := h.reply(cmd); handled {
+			cmd.SetErr(err)
+			return err
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (h replyHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			if handled, err := h.reply(cmd); !handled {
+				return next(ctx, cmds)
+			} else if err != nil {
+				cmd.SetErr(err)
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// newReplyClient returns a client whose commands are answered by reply
+func newReplyClient(t *testing.T, reply func(cmd redis.Cmder) (bool, error)) *Client {
+	t.Helper()
+	client, err := NewWithOptions(WithAddr("127.0.0.1:1"), WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("NewWithOptions() error = %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	client.client.AddHook(replyHook{reply: reply})
+	return client
+}
+
+// newStallServer starts a server that rejects connection setup commands and
+// never answers anything else, so blocking commands stay outstanding
+func newStallServer(t *testing.T) (addr string, commands <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	seen := make(chan string, 16)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					args, err := readCommand(r)
+					if err != nil {
+						return
+					}
+					name := strings.ToLower(args[0])
+					select {
+					case seen <- name:
+					default:
+					}
+					if name == "hello" || name == "client" || name == "ping" || name == "select" {
+						conn.Write([]byte("-ERR unknown command\r\n"))
+					}
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String(), seen
+}
+
+// readCommand reads one RESP array of bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("bad command header %q", line)
+	}
+	args := make([]string, n)
+	for i := range args {
+		if _, err := r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
+
+// TestClient_CloseDuringBlockingPop tests that Close does not wait for a blocking pop
+func TestClient_CloseDuringBlockingPop(t *testing.T) {
+	addr, commands := newStallServer(t)
+	client, err := NewWithOptions(WithAddr(addr), WithMaxRetries(0), WithMinIdleConns(0))
+	if err != nil {
+		t.Fatalf("NewWithOptions() error = %v", err)
+	}
+
+	popped := make(chan error, 1)
+	go func() {
+		_, err := client.BLPop(context.Background(), 0, "jobs")
+		popped <- err
+	}()
+
+	for name := range commands {
+		if name == "blpop" {
+			break
+		}
+	}
+
+	// Other commands are not held up by the outstanding pop
+	if _, err := client.BRPop(context.Background(), 0); err != ErrInvalidKey {
+		t.Errorf("BRPop() error = %v, want ErrInvalidKey", err)
+	}
+
+	closed := make(chan error, 1)
+	go func() { closed <- client.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close() is blocked by the outstanding BLPop")
+	}
+
+	select {
+	case err := <-popped:
+		if err != ErrClientClosed {
+			t.Errorf("BLPop() error = %v, want ErrClientClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("BLPop() did not return after Close()")
+	}
+}
@@ -0,0 +1,65 @@
+package redisclient
+
+import "strings"
+
+// ====================
+// Cluster Helpers
+// ====================
+
+// ClusterSlots is the number of hash slots in a Redis Cluster
+const ClusterSlots = 16384
+
+// HashTag wraps s in braces so that keys built from it hash to the same slot,
+// e.g. HashTag("user:42") + ":profile" and HashTag("user:42") + ":orders"
+func HashTag(s string) string {
+	return "{" + s + "}"
+}
+
+// KeySlot returns the cluster hash slot of a key. Only the hash tag is hashed
+// when the key contains a non-empty one.
+func KeySlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key) % ClusterSlots)
+}
+
+// SameSlot reports whether all keys hash to the same slot, which multi-key
+// commands, transactions and Lua scripts require in cluster mode
+func SameSlot(keys ...string) bool {
+	for i := 1; i < len(keys); i++ {
+		if KeySlot(keys[i]) != KeySlot(keys[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// GroupKeysBySlot splits keys by hash slot so multi-key commands can be sent
+// per slot. Keys keep their relative order within a group.
+func GroupKeysBySlot(keys ...string) map[int][]string {
+	groups := make(map[int][]string)
+	for _, key := range keys {
+		slot := KeySlot(key)
+		groups[slot] = append(groups[slot], key)
+	}
+	return groups
+}
+
+// crc16 implements CRC16-CCITT (XMODEM) as used by Redis Cluster
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
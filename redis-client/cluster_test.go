@@ -0,0 +1,92 @@
+package redisclient
+
+import (
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TestKeySlot tests slot calculation against values from CLUSTER KEYSLOT
+func TestKeySlot(t *testing.T) {
+	tests := []struct {
+		key  string
+		want int
+	}{
+		{"foo", 12182},
+		{"bar", 5061},
+		{"123456789", 12739},
+		{"{foo}.following", 12182},
+		{"x{bar}y{foo}", 5061},
+	}
+
+	for _, tt := range tests {
+		if got := KeySlot(tt.key); got != tt.want {
+			t.Errorf("KeySlot(%q) = %d, want %d", tt.key, got, tt.want)
+		}
+	}
+
+	// An empty hash tag hashes the whole key
+	if KeySlot("{}foo") == KeySlot("foo") || KeySlot("{}foo") != int(crc16("{}foo")%ClusterSlots) {
+		t.Error("empty hash tag should not be used")
+	}
+}
+
+// TestHashTagHelpers tests hash tag grouping helpers
+func TestHashTagHelpers(t *testing.T) {
+	tag := HashTag("user:42")
+	if tag != "{user:42}" {
+		t.Errorf("HashTag() = %q", tag)
+	}
+
+	if !SameSlot(tag+":profile", tag+":orders", "user:42") {
+		t.Error("keys with the same hash tag should share a slot")
+	}
+	if SameSlot("foo", "bar") {
+		t.Error("foo and bar should be in different slots")
+	}
+	if !SameSlot() || !SameSlot("single") {
+		t.Error("zero or one key is always in the same slot")
+	}
+
+	groups := GroupKeysBySlot("foo", "bar", "{foo}:a")
+	if len(groups) != 2 || len(groups[12182]) != 2 || groups[12182][1] != "{foo}:a" {
+		t.Errorf("GroupKeysBySlot() = %v", groups)
+	}
+}
+
+// TestClient_Modes tests that the client type follows the configured mode
+func TestClient_Modes(t *testing.T) {
+	cluster, err := NewWithOptions(WithClusterAddrs("127.0.0.1:7000", "127.0.0.1:7001"), WithReadFromReplica(true))
+	if err != nil {
+		t.Fatalf("NewWithOptions() cluster error = %v", err)
+	}
+	defer cluster.Close()
+
+	if cluster.Mode() != ModeCluster {
+		t.Errorf("Mode() = %v, want cluster", cluster.Mode())
+	}
+	if _, ok := cluster.UniversalClient().(*redis.ClusterClient); !ok {
+		t.Errorf("UniversalClient() = %T, want *redis.ClusterClient", cluster.UniversalClient())
+	}
+	if cluster.Client() != nil {
+		t.Error("Client() should be nil in cluster mode")
+	}
+	if _, err := cluster.nodeClient("tracking", 1); err == nil {
+		t.Error("nodeClient() should fail in cluster mode")
+	}
+
+	sentinel, err := NewWithOptions(WithSentinel("mymaster", "127.0.0.1:26379"), WithSentinelPassword("secret"))
+	if err != nil {
+		t.Fatalf("NewWithOptions() sentinel error = %v", err)
+	}
+	defer sentinel.Close()
+
+	if sentinel.Mode() != ModeSentinel || sentinel.Client() == nil {
+		t.Errorf("sentinel client = %v, %T", sentinel.Mode(), sentinel.UniversalClient())
+	}
+
+	standalone := &Client{config: DefaultConfig()}
+	if standalone.Mode() != ModeStandalone {
+		t.Errorf("Mode() = %v, want standalone", standalone.Mode())
+	}
+}
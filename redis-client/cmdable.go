@@ -0,0 +1,118 @@
+package redisclient
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cmdable is the command set shared by Client, DBClient and the in-memory
+// fake in the redistest package. Accept a Cmdable instead of a *Client in code
+// that should be unit-testable without a Redis server.
+//
+// Pipelines, transactions, subscriptions and the helpers built on them return
+// go-redis types and are not part of the interface. Neither are blocking pops,
+// scans, bitmaps, HyperLogLog and geo commands, which only Client and DBClient
+// provide.
+type Cmdable interface {
+	// Connection
+	Ping(ctx context.Context) error
+
+	// Strings
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, ttl ...time.Duration) error
+	SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
+	SetEX(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	GetSet(ctx context.Context, key string, value interface{}) (string, error)
+	MGet(ctx context.Context, keys ...string) ([]interface{}, error)
+	MSet(ctx context.Context, values ...interface{}) error
+	Incr(ctx context.Context, key string) (int64, error)
+	IncrBy(ctx context.Context, key string, value int64) (int64, error)
+	Decr(ctx context.Context, key string) (int64, error)
+	DecrBy(ctx context.Context, key string, value int64) (int64, error)
+	GetDel(ctx context.Context, key string) (string, error)
+	GetEx(ctx context.Context, key string, ttl time.Duration) (string, error)
+	IncrByFloat(ctx context.Context, key string, value float64) (float64, error)
+	Append(ctx context.Context, key, value string) (int64, error)
+	StrLen(ctx context.Context, key string) (int64, error)
+
+	// Keys
+	Del(ctx context.Context, keys ...string) (int64, error)
+	Exists(ctx context.Context, keys ...string) (int64, error)
+	Expire(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	Persist(ctx context.Context, key string) (bool, error)
+	Keys(ctx context.Context, pattern string) ([]string, error)
+	Unlink(ctx context.Context, keys ...string) (int64, error)
+	Type(ctx context.Context, key string) (string, error)
+	Rename(ctx context.Context, key, newKey string) error
+	ExpireAt(ctx context.Context, key string, tm time.Time) (bool, error)
+	PTTL(ctx context.Context, key string) (time.Duration, error)
+
+	// Hashes
+	HSet(ctx context.Context, key string, values ...interface{}) (int64, error)
+	HGet(ctx context.Context, key, field string) (string, error)
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	HDel(ctx context.Context, key string, fields ...string) (int64, error)
+	HExists(ctx context.Context, key, field string) (bool, error)
+	HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error)
+	HKeys(ctx context.Context, key string) ([]string, error)
+	HLen(ctx context.Context, key string) (int64, error)
+	HMGet(ctx context.Context, key string, fields ...string) ([]interface{}, error)
+	HSetNX(ctx context.Context, key, field string, value interface{}) (bool, error)
+	HVals(ctx context.Context, key string) ([]string, error)
+	HIncrByFloat(ctx context.Context, key, field string, incr float64) (float64, error)
+
+	// Lists
+	LPush(ctx context.Context, key string, values ...interface{}) (int64, error)
+	RPush(ctx context.Context, key string, values ...interface{}) (int64, error)
+	LPop(ctx context.Context, key string) (string, error)
+	RPop(ctx context.Context, key string) (string, error)
+	LRange(ctx context.Context, key string, start, stop int64) ([]string, error)
+	LLen(ctx context.Context, key string) (int64, error)
+	LTrim(ctx context.Context, key string, start, stop int64) error
+	LRem(ctx context.Context, key string, count int64, value interface{}) (int64, error)
+	LIndex(ctx context.Context, key string, index int64) (string, error)
+	LSet(ctx context.Context, key string, index int64, value interface{}) error
+
+	// Sets
+	SAdd(ctx context.Context, key string, members ...interface{}) (int64, error)
+	SMembers(ctx context.Context, key string) ([]string, error)
+	SIsMember(ctx context.Context, key string, member interface{}) (bool, error)
+	SRem(ctx context.Context, key string, members ...interface{}) (int64, error)
+	SCard(ctx context.Context, key string) (int64, error)
+	SInter(ctx context.Context, keys ...string) ([]string, error)
+	SUnion(ctx context.Context, keys ...string) ([]string, error)
+	SDiff(ctx context.Context, keys ...string) ([]string, error)
+	SPop(ctx context.Context, key string) (string, error)
+	SRandMember(ctx context.Context, key string) (string, error)
+	SMIsMember(ctx context.Context, key string, members ...interface{}) ([]bool, error)
+
+	// Sorted sets
+	ZAdd(ctx context.Context, key string, members ...redis.Z) (int64, error)
+	ZRange(ctx context.Context, key string, start, stop int64) ([]string, error)
+	ZRangeWithScores(ctx context.Context, key string, start, stop int64) ([]redis.Z, error)
+	ZRem(ctx context.Context, key string, members ...interface{}) (int64, error)
+	ZCard(ctx context.Context, key string) (int64, error)
+	ZScore(ctx context.Context, key, member string) (float64, error)
+	ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error)
+	ZRevRange(ctx context.Context, key string, start, stop int64) ([]string, error)
+	ZRevRangeWithScores(ctx context.Context, key string, start, stop int64) ([]redis.Z, error)
+	ZRangeByScore(ctx context.Context, key string, opt *redis.ZRangeBy) ([]string, error)
+	ZRangeByScoreWithScores(ctx context.Context, key string, opt *redis.ZRangeBy) ([]redis.Z, error)
+	ZRevRangeByScore(ctx context.Context, key string, opt *redis.ZRangeBy) ([]string, error)
+	ZRank(ctx context.Context, key, member string) (int64, error)
+	ZRevRank(ctx context.Context, key, member string) (int64, error)
+	ZCount(ctx context.Context, key, min, max string) (int64, error)
+	ZRemRangeByScore(ctx context.Context, key, min, max string) (int64, error)
+	ZRemRangeByRank(ctx context.Context, key string, start, stop int64) (int64, error)
+
+	// Pub/Sub
+	Publish(ctx context.Context, channel string, message interface{}) (int64, error)
+}
+
+var (
+	_ Cmdable = (*Client)(nil)
+	_ Cmdable = (*DBClient)(nil)
+)
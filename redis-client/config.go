@@ -2,7 +2,23 @@ package redisclient
 
 import (
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Mode is the Redis deployment the client connects to
+type Mode string
+
+const (
+	// ModeStandalone connects to a single Redis server at Addr
+	ModeStandalone Mode = "standalone"
+	// ModeCluster connects to a Redis Cluster through ClusterAddrs
+	ModeCluster Mode = "cluster"
+	// ModeSentinel connects to the master named MasterName through SentinelAddrs
+	ModeSentinel Mode = "sentinel"
 )
 
 // Config holds the configuration for Redis client
@@ -12,6 +28,16 @@ type Config struct {
 	Password string `json:"password" yaml:"password"` // password (optional)
 	DB       int    `json:"db" yaml:"db"`             // database number
 
+	// Cluster settings
+	ClusterAddrs    []string `json:"cluster_addrs" yaml:"cluster_addrs"`         // seed node addresses, enables cluster mode
+	ReadFromReplica bool     `json:"read_from_replica" yaml:"read_from_replica"` // route read-only commands to replicas
+	RouteByLatency  bool     `json:"route_by_latency" yaml:"route_by_latency"`   // route read-only commands to the closest node
+
+	// Sentinel settings
+	MasterName       string   `json:"master_name" yaml:"master_name"`             // master set name, enables sentinel mode
+	SentinelAddrs    []string `json:"sentinel_addrs" yaml:"sentinel_addrs"`       // sentinel addresses
+	SentinelPassword string   `json:"sentinel_password" yaml:"sentinel_password"` // sentinel password (optional)
+
 	// Connection pool settings
 	MaxRetries      int           `json:"max_retries" yaml:"max_retries"`
 	MinIdleConns    int           `json:"min_idle_conns" yaml:"min_idle_conns"`
@@ -32,8 +58,26 @@ type Config struct {
 	TLSKeyFile  string `json:"tls_key_file" yaml:"tls_key_file"`
 	TLSCAFile   string `json:"tls_ca_file" yaml:"tls_ca_file"`
 
+	// Key namespacing
+	KeyPrefix string `json:"key_prefix" yaml:"key_prefix"` // prepended to every key and pub/sub channel
+
+	// Observability
+	Metrics          *Metrics             `json:"-" yaml:"-"`                                   // command and pool metrics collector (optional)
+	SlowLogThreshold time.Duration        `json:"slow_log_threshold" yaml:"slow_log_threshold"` // log commands slower than this, 0 disables
+	SlowLogger       *slog.Logger         `json:"-" yaml:"-"`                                   // slow command logger, defaults to slog.Default()
+	Tracing          bool                 `json:"tracing" yaml:"tracing"`                       // create an OpenTelemetry span per command and pipeline
+	TracerProvider   trace.TracerProvider `json:"-" yaml:"-"`                                   // defaults to the global provider
+
+	// Value encoding
+	ValueCodec *ValueCodec `json:"-" yaml:"-"` // compresses and encrypts string and hash values (optional)
+
 	// Database name mappings (for multi-database manager)
 	DatabaseNames map[string]int `json:"database_names" yaml:"database_names"`
+
+	// Multi-database connection management
+	DatabasePoolSizes map[string]int `json:"database_pool_sizes" yaml:"database_pool_sizes"` // pool size per database name, default PoolSize
+	MaxTotalConns     int            `json:"max_total_conns" yaml:"max_total_conns"`         // connection budget shared by all databases, 0 means unlimited
+	DBIdleTimeout     time.Duration  `json:"db_idle_timeout" yaml:"db_idle_timeout"`         // close pools of databases unused for this long, 0 disables
 }
 
 // DefaultConfig returns the default configuration for Redis
@@ -56,10 +100,39 @@ func DefaultConfig() *Config {
 	}
 }
 
+// Mode returns the deployment mode implied by the configuration
+func (c *Config) Mode() Mode {
+	switch {
+	case len(c.ClusterAddrs) > 0:
+		return ModeCluster
+	case c.MasterName != "":
+		return ModeSentinel
+	default:
+		return ModeStandalone
+	}
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
-	if c.Addr == "" {
-		return fmt.Errorf("addr cannot be empty")
+	switch c.Mode() {
+	case ModeCluster:
+		if c.MasterName != "" {
+			return fmt.Errorf("cluster_addrs and master_name cannot both be set")
+		}
+		if c.DB != 0 {
+			return fmt.Errorf("db must be 0 in cluster mode")
+		}
+		if strings.ContainsAny(c.KeyPrefix, "{}") {
+			return fmt.Errorf("key_prefix cannot contain a hash tag in cluster mode")
+		}
+	case ModeSentinel:
+		if len(c.SentinelAddrs) == 0 {
+			return fmt.Errorf("sentinel_addrs cannot be empty when master_name is set")
+		}
+	default:
+		if c.Addr == "" {
+			return fmt.Errorf("addr cannot be empty")
+		}
 	}
 
 	if c.DB < 0 {
@@ -78,6 +151,35 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("dial_timeout must be positive")
 	}
 
+	if c.SlowLogThreshold < 0 {
+		return fmt.Errorf("slow_log_threshold must be non-negative")
+	}
+
+	for name, size := range c.DatabasePoolSizes {
+		if size <= 0 {
+			return fmt.Errorf("database_pool_sizes[%s] must be positive", name)
+		}
+		if _, ok := c.DatabaseNames[name]; !ok {
+			return fmt.Errorf("database_pool_sizes[%s] refers to an unknown database name", name)
+		}
+	}
+
+	if c.MaxTotalConns < 0 {
+		return fmt.Errorf("max_total_conns must be non-negative")
+	}
+
+	if c.MaxTotalConns > 0 {
+		for name, size := range c.DatabasePoolSizes {
+			if size > c.MaxTotalConns {
+				return fmt.Errorf("database_pool_sizes[%s] exceeds max_total_conns", name)
+			}
+		}
+	}
+
+	if c.DBIdleTimeout < 0 {
+		return fmt.Errorf("db_idle_timeout must be non-negative")
+	}
+
 	if c.TLSEnabled {
 		if c.TLSCertFile == "" && c.TLSKeyFile != "" {
 			return fmt.Errorf("tls_cert_file required when tls_key_file is set")
@@ -0,0 +1,81 @@
+package redisclient
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseCron tests cron expression parsing and next run times
+func TestParseCron(t *testing.T) {
+	from := time.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC) // a Monday
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 1, 15, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 9 * * *", time.Date(2024, 1, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2024, 1, 15, 13, 0, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * 3", time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC)}, // day of month OR day of week
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", from.Add(90 * time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := ParseCron(tt.spec, time.UTC)
+			if err != nil {
+				t.Fatalf("ParseCron() error = %v", err)
+			}
+			if got := schedule.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseCron_Invalid tests that malformed expressions are rejected
+func TestParseCron_Invalid(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@every -1s",
+		"@every soon",
+	}
+
+	for _, spec := range specs {
+		if _, err := ParseCron(spec, nil); err == nil {
+			t.Errorf("ParseCron(%q) should fail", spec)
+		}
+	}
+}
+
+// TestParseCron_Location tests that cron expressions use their time zone
+func TestParseCron_Location(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	schedule, err := ParseCron("0 9 * * *", loc)
+	if err != nil {
+		t.Fatalf("ParseCron() error = %v", err)
+	}
+
+	got := schedule.Next(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	if want := time.Date(2024, 1, 15, 6, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,448 @@
+package redisclient
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ====================
+// DBManager Connection Budget
+// ====================
+
+// dbPool tracks the connection pool opened for one database
+type dbPool struct {
+	size  int        // pool size allocated from the budget
+	usage *usageHook // command activity of the pool
+}
+
+// usageHook records when a client was last used and how many commands are in
+// flight, so idle pools can be found without touching the connections
+type usageHook struct {
+	lastUsed atomic.Int64 // unix nanoseconds
+	inFlight atomic.Int64
+}
+
+// newUsageHook returns a hook that counts the client as used now
+func newUsageHook() *usageHook {
+	h := &usageHook{}
+	h.lastUsed.Store(time.Now().UnixNano())
+	return h
+}
+
+// DialHook implements redis.Hook
+func (h *usageHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements redis.Hook
+func (h *usageHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		h.begin()
+		defer h.end()
+		return next(ctx, cmd)
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook
+func (h *usageHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		h.begin()
+		defer h.end()
+		return next(ctx, cmds)
+	}
+}
+
+func (h *usageHook) begin() {
+	h.inFlight.Add(1)
+	h.lastUsed.Store(time.Now().UnixNano())
+}
+
+func (h *usageHook) end() {
+	h.lastUsed.Store(time.Now().UnixNano())
+	h.inFlight.Add(-1)
+}
+
+// LastUsed returns when the last command started or finished
+func (h *usageHook) LastUsed() time.Time {
+	return time.Unix(0, h.lastUsed.Load())
+}
+
+// reopenHook makes an evicted client reserve its pool in the budget again
+// before it dials its first connection
+type reopenHook struct {
+	reopen func() error
+	done   atomic.Bool
+}
+
+// DialHook implements redis.Hook
+func (h *reopenHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if !h.done.Load() {
+			if err := h.reopen(); err != nil {
+				return nil, err
+			}
+			h.done.Store(true)
+		}
+		return next(ctx, network, addr)
+	}
+}
+
+// ProcessHook implements redis.Hook
+func (h *reopenHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return next
+}
+
+// ProcessPipelineHook implements redis.Hook
+func (h *reopenHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+// park replaces the connection pool of a client with an empty one and closes
+// the old pool, so the client stays usable but holds no connections. reopen
+// is called before the new pool dials.
+func (c *Client) park(usage *usageHook, reopen func() error) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrClientClosed
+	}
+
+	old := c.client
+	c.config.MinIdleConns = 0 // an empty pool must not dial on its own
+	if err := c.connect(); err != nil {
+		c.client = old
+		c.mu.Unlock()
+		return err
+	}
+	c.client.AddHook(usage)
+	c.client.AddHook(&reopenHook{reopen: reopen})
+	if c.config.Metrics != nil {
+		c.config.Metrics.retirePool(c.config.DB, poolStatsOf(old))
+	}
+	c.mu.Unlock()
+
+	return old.Close()
+}
+
+// DBStats reports the state of one database opened by a DBManager
+type DBStats struct {
+	DB       int       `json:"db"`        // database number
+	Names    []string  `json:"names"`     // configured names of the database
+	PoolSize int       `json:"pool_size"` // pool size allocated from the budget
+	InFlight int64     `json:"in_flight"` // commands and pipelines in progress
+	LastUsed time.Time `json:"last_used"` // when a command last started or finished
+	Pool     PoolStats `json:"pool"`      // connection pool statistics
+}
+
+// poolSizeFor returns the pool size configured for a database: the largest
+// size configured for any of its names, or PoolSize capped at a fair share of
+// MaxTotalConns. Caller must hold m.mu.
+func (m *DBManager) poolSizeFor(dbNum int) int {
+	size := 0
+	for name, n := range m.config.DatabasePoolSizes {
+		if m.databaseNames[name] == dbNum && n > size {
+			size = n
+		}
+	}
+	if size > 0 {
+		return size
+	}
+
+	size = m.config.PoolSize
+	if m.config.MaxTotalConns > 0 {
+		size = min(size, max(m.config.MaxTotalConns/m.knownDBs(dbNum), 1))
+	}
+	return size
+}
+
+// knownDBs counts the named, open and evicted databases and dbNum. Caller
+// must hold m.mu.
+func (m *DBManager) knownDBs(dbNum int) int {
+	known := map[int]bool{dbNum: true}
+	for _, n := range m.databaseNames {
+		known[n] = true
+	}
+	for n := range m.pools {
+		known[n] = true
+	}
+	for n := range m.evicted {
+		known[n] = true
+	}
+	return len(known)
+}
+
+// allocated returns the connections allocated to open pools. Caller must hold m.mu.
+func (m *DBManager) allocated() int {
+	total := 0
+	for _, pool := range m.pools {
+		total += pool.size
+	}
+	return total
+}
+
+// reservePool chooses the pool size for a new database within the budget,
+// evicting idle pools when eviction is enabled and the budget is short. It
+// returns ErrConnBudgetExhausted if not even one connection is left. Caller
+// must hold m.mu.
+func (m *DBManager) reservePool(dbNum int) (int, error) {
+	size := m.poolSizeFor(dbNum)
+	if m.config.MaxTotalConns == 0 {
+		return size, nil
+	}
+
+	available := m.makeRoom(size)
+	if available < 1 {
+		return 0, m.budgetExhausted()
+	}
+	return min(size, available), nil
+}
+
+// makeRoom evicts idle pools, least recently used first, until need
+// connections are free or no idle pool is left, and returns the free
+// connections. Caller must hold m.mu.
+func (m *DBManager) makeRoom(need int) int {
+	available := m.config.MaxTotalConns - m.allocated()
+	if available < need && m.config.DBIdleTimeout > 0 {
+		for _, candidate := range m.idleDBs(0) {
+			if available >= need {
+				break
+			}
+			available += m.pools[candidate].size
+			m.evictDB(candidate)
+		}
+	}
+	return available
+}
+
+// budgetExhausted returns ErrConnBudgetExhausted with the allocation. Caller
+// must hold m.mu.
+func (m *DBManager) budgetExhausted() error {
+	return fmt.Errorf("%w: %d of %d connections allocated", ErrConnBudgetExhausted, m.allocated(), m.config.MaxTotalConns)
+}
+
+// reopen returns the pool of an evicted client to the budget. The pool keeps
+// its size, so it fails with ErrConnBudgetExhausted if that much room cannot
+// be made.
+func (m *DBManager) reopen(dbNum int, client *Client, pool *dbPool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return ErrClientClosed
+	}
+	if m.evicted[dbNum] != client {
+		return nil // reopened by a concurrent dial
+	}
+
+	if m.config.MaxTotalConns > 0 && m.makeRoom(pool.size) < pool.size {
+		return fmt.Errorf("failed to reopen DB %d: %w", dbNum, m.budgetExhausted())
+	}
+
+	delete(m.evicted, dbNum)
+	m.clients[dbNum] = client
+	m.pools[dbNum] = pool
+	return nil
+}
+
+// idleDBs returns the open databases without commands in flight or
+// connections in use that were unused for at least idleFor, least recently
+// used first. Caller must hold m.mu.
+func (m *DBManager) idleDBs(idleFor time.Duration) []int {
+	type candidate struct {
+		db       int
+		lastUsed time.Time
+	}
+
+	now := time.Now()
+	var candidates []candidate
+	for dbNum, pool := range m.pools {
+		lastUsed := pool.usage.LastUsed()
+		if pool.usage.inFlight.Load() > 0 || now.Sub(lastUsed) < idleFor {
+			continue
+		}
+		if stats := m.clients[dbNum].Stats(); stats.TotalConns > stats.IdleConns {
+			continue
+		}
+		candidates = append(candidates, candidate{db: dbNum, lastUsed: lastUsed})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].lastUsed.Before(candidates[j].lastUsed)
+	})
+	dbs := make([]int, len(candidates))
+	for i, c := range candidates {
+		dbs[i] = c.db
+	}
+	return dbs
+}
+
+// evictDB closes the connections of a database and returns them to the
+// budget. Its client stays usable and reopens the pool on its next dial.
+// Caller must hold m.mu.
+func (m *DBManager) evictDB(dbNum int) {
+	client, pool := m.clients[dbNum], m.pools[dbNum]
+	delete(m.clients, dbNum)
+	delete(m.pools, dbNum)
+
+	err := client.park(pool.usage, func() error {
+		return m.reopen(dbNum, client, pool)
+	})
+	if err != nil {
+		client.Close()
+		return
+	}
+	m.evicted[dbNum] = client
+}
+
+// closeDB closes and forgets a database and its client. Caller must hold m.mu.
+func (m *DBManager) closeDB(dbNum int) error {
+	client, ok := m.clients[dbNum]
+	if !ok {
+		client, ok = m.evicted[dbNum]
+	}
+	if !ok {
+		return nil
+	}
+	delete(m.clients, dbNum)
+	delete(m.pools, dbNum)
+	delete(m.evicted, dbNum)
+	return client.Close()
+}
+
+// CloseDB closes the connection pool of a database and returns its
+// connections to the budget. Clients previously returned for it are closed;
+// the next DB call opens a new pool.
+func (m *DBManager) CloseDB(identifier DBIdentifier) error {
+	dbNum, ok := m.resolveDBNumber(identifier)
+	if !ok {
+		return fmt.Errorf("invalid database identifier: %v (not found in configuration)", identifier)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return ErrClientClosed
+	}
+	if err := m.closeDB(dbNum); err != nil {
+		return fmt.Errorf("DB %d: %w", dbNum, err)
+	}
+	return nil
+}
+
+// EvictIdle closes the pools of databases unused for at least idleFor that
+// have no commands in flight, and returns the evicted database numbers. Their
+// clients stay usable and reopen the pool when they are used again.
+func (m *DBManager) EvictIdle(idleFor time.Duration) []int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil
+	}
+
+	evicted := m.idleDBs(idleFor)
+	for _, dbNum := range evicted {
+		m.evictDB(dbNum)
+	}
+	sort.Ints(evicted)
+	return evicted
+}
+
+// runJanitor evicts idle pools until stop is closed
+func (m *DBManager) runJanitor(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	interval := max(m.config.DBIdleTimeout/2, time.Millisecond)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			m.EvictIdle(m.config.DBIdleTimeout)
+		}
+	}
+}
+
+// stopJanitor stops the idle eviction goroutine if it runs
+func (m *DBManager) stopJanitor() {
+	if m.janitorStop == nil {
+		return
+	}
+	m.janitorOnce.Do(func() {
+		close(m.janitorStop)
+		<-m.janitorDone
+	})
+}
+
+// PingDB pings one database, opening its pool if needed
+func (m *DBManager) PingDB(ctx context.Context, identifier DBIdentifier) error {
+	client, err := m.DB(identifier)
+	if err != nil {
+		return err
+	}
+	return client.Ping(ctx)
+}
+
+// PingEach pings every open database and returns the result per database
+// number; a nil error means the database is healthy
+func (m *DBManager) PingEach(ctx context.Context) map[int]error {
+	m.mu.RLock()
+	clients := make(map[int]*Client, len(m.clients))
+	for dbNum, client := range m.clients {
+		clients[dbNum] = client
+	}
+	m.mu.RUnlock()
+
+	results := make(map[int]error, len(clients))
+	for dbNum, client := range clients {
+		results[dbNum] = client.Ping(ctx)
+	}
+	return results
+}
+
+// Stats returns the state of every open database, ordered by database number
+func (m *DBManager) Stats() []DBStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make(map[int][]string)
+	for name, dbNum := range m.databaseNames {
+		names[dbNum] = append(names[dbNum], name)
+	}
+
+	stats := make([]DBStats, 0, len(m.pools))
+	for dbNum, pool := range m.pools {
+		sort.Strings(names[dbNum])
+		stats = append(stats, DBStats{
+			DB:       dbNum,
+			Names:    names[dbNum],
+			PoolSize: pool.size,
+			InFlight: pool.usage.inFlight.Load(),
+			LastUsed: pool.usage.LastUsed(),
+			Pool:     m.clients[dbNum].Stats(),
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].DB < stats[j].DB
+	})
+	return stats
+}
+
+// AllocatedConns returns the connections allocated to open pools, which
+// MaxTotalConns limits
+func (m *DBManager) AllocatedConns() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.allocated()
+}
@@ -0,0 +1,239 @@
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestDBManager_PoolSizes tests per-database pool sizes and the connection budget
+func TestDBManager_PoolSizes(t *testing.T) {
+	manager, err := NewDBManagerWithOptions(
+		WithPoolSize(20),
+		WithMinIdleConns(0),
+		WithDatabaseName("cache", 1),
+		WithDatabaseName("sessions", 2),
+		WithDatabasePoolSize("sessions", 5),
+		WithMaxTotalConns(30),
+	)
+	if err != nil {
+		t.Fatalf("NewDBManagerWithOptions() error = %v", err)
+	}
+	defer manager.Close()
+
+	sessions, err := manager.DB("sessions")
+	if err != nil {
+		t.Fatalf("DB(sessions) error = %v", err)
+	}
+	if sessions.config.PoolSize != 5 {
+		t.Errorf("sessions PoolSize = %d, want 5", sessions.config.PoolSize)
+	}
+	if sessions.config.MaxIdleConns != 5 {
+		t.Errorf("sessions MaxIdleConns = %d, want 5", sessions.config.MaxIdleConns)
+	}
+
+	// PoolSize is capped at a fair share of the budget between the two named databases
+	cache, err := manager.DB("cache")
+	if err != nil {
+		t.Fatalf("DB(cache) error = %v", err)
+	}
+	if cache.config.PoolSize != 15 {
+		t.Errorf("cache PoolSize = %d, want 15", cache.config.PoolSize)
+	}
+
+	// A third database gets a third of the budget, which is what is left
+	db0, err := manager.DB(0)
+	if err != nil {
+		t.Fatalf("DB(0) error = %v", err)
+	}
+	if db0.config.PoolSize != 10 {
+		t.Errorf("DB 0 PoolSize = %d, want 10", db0.config.PoolSize)
+	}
+	if got := manager.AllocatedConns(); got != 30 {
+		t.Errorf("AllocatedConns() = %d, want 30", got)
+	}
+
+	// Without idle eviction the budget cannot be freed
+	if _, err := manager.DB(3); !errors.Is(err, ErrConnBudgetExhausted) {
+		t.Errorf("DB(3) error = %v, want ErrConnBudgetExhausted", err)
+	}
+
+	if err := manager.CloseDB("cache"); err != nil {
+		t.Fatalf("CloseDB(cache) error = %v", err)
+	}
+	if !isClosed(cache) {
+		t.Error("CloseDB() should close the database's client")
+	}
+	if _, err := manager.DB(3); err != nil {
+		t.Errorf("DB(3) after CloseDB() error = %v", err)
+	}
+}
+
+// TestDBManager_BudgetEviction tests that idle pools make room when the budget is short
+func TestDBManager_BudgetEviction(t *testing.T) {
+	manager, err := NewDBManagerWithOptions(
+		WithAddr("127.0.0.1:1"),
+		WithDialTimeout(100*time.Millisecond),
+		WithMaxRetries(0),
+		WithPoolSize(10),
+		WithMaxTotalConns(20),
+		WithDBIdleTimeout(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("NewDBManagerWithOptions() error = %v", err)
+	}
+	defer manager.Close()
+
+	first := manager.MustDB(1)
+	manager.MustDB(2)
+
+	// DB 2 is used more recently, so DB 1 is evicted first
+	manager.pools[2].usage.lastUsed.Store(time.Now().Add(time.Minute).UnixNano())
+
+	db3, err := manager.DB(3)
+	if err != nil {
+		t.Fatalf("DB(3) error = %v", err)
+	}
+	if db3.config.PoolSize != 6 {
+		t.Errorf("DB 3 PoolSize = %d, want a third of the budget", db3.config.PoolSize)
+	}
+	if dbs := manager.ActiveDBs(); len(dbs) != 2 {
+		t.Errorf("ActiveDBs() = %v, want 2 databases", dbs)
+	}
+
+	// The evicted client stays usable and is handed out again
+	if isClosed(first) {
+		t.Fatal("evicting DB 1 should not close its client")
+	}
+	if again := manager.MustDB(1); again != first {
+		t.Error("DB() should return the evicted client")
+	}
+
+	// Using it reserves its pool again, evicting the least recently used DB 3
+	ctx := context.Background()
+	if err := first.Ping(ctx); err == nil || errors.Is(err, ErrClientClosed) {
+		t.Errorf("Ping() error = %v, want a connection error", err)
+	}
+	if _, ok := manager.pools[1]; !ok {
+		t.Error("using an evicted client should reopen its pool")
+	}
+	if _, ok := manager.pools[3]; ok {
+		t.Error("DB 3 should have been evicted to make room")
+	}
+	if got := manager.AllocatedConns(); got != 20 {
+		t.Errorf("AllocatedConns() = %d, want 20", got)
+	}
+
+	// Databases with commands in flight are never evicted
+	manager.pools[1].usage.inFlight.Add(1)
+	manager.pools[2].usage.inFlight.Add(1)
+	if _, err := manager.DB(4); !errors.Is(err, ErrConnBudgetExhausted) {
+		t.Errorf("DB(4) error = %v, want ErrConnBudgetExhausted", err)
+	}
+	if err := db3.Ping(ctx); !errors.Is(err, ErrConnBudgetExhausted) {
+		t.Errorf("Ping() on evicted DB 3 error = %v, want ErrConnBudgetExhausted", err)
+	}
+}
+
+// TestDBManager_EvictIdle tests background eviction of unused pools
+func TestDBManager_EvictIdle(t *testing.T) {
+	manager, err := NewDBManagerWithOptions(WithDBIdleTimeout(20 * time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewDBManagerWithOptions() error = %v", err)
+	}
+	defer manager.Close()
+
+	client := manager.MustDB(1)
+	manager.MustDB(2)
+	manager.pools[2].usage.inFlight.Add(1)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(manager.ActiveDBs()) != 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if dbs := manager.ActiveDBs(); len(dbs) != 1 || dbs[0] != 2 {
+		t.Fatalf("ActiveDBs() = %v, want [2]", dbs)
+	}
+	if isClosed(client) {
+		t.Error("eviction should not close the client")
+	}
+	if again := manager.MustDB(1); again != client {
+		t.Error("DB() should return the evicted client")
+	}
+
+	// Closing the manager closes evicted clients too
+	if err := manager.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if !isClosed(client) {
+		t.Error("Close() should close evicted clients")
+	}
+	if got := manager.EvictIdle(0); got != nil {
+		t.Errorf("EvictIdle() after Close() = %v, want nil", got)
+	}
+}
+
+// TestDBManager_Stats tests per-database stats and health checks
+func TestDBManager_Stats(t *testing.T) {
+	manager, err := NewDBManagerWithOptions(
+		WithAddr("127.0.0.1:1"),
+		WithDialTimeout(100*time.Millisecond),
+		WithMaxRetries(0),
+		WithMinIdleConns(0),
+		WithDatabaseName("cache", 1),
+		WithDatabaseName("hot-cache", 1),
+		WithDatabasePoolSize("cache", 4),
+	)
+	if err != nil {
+		t.Fatalf("NewDBManagerWithOptions() error = %v", err)
+	}
+	defer manager.Close()
+
+	manager.MustDB(0)
+	manager.MustDB("cache")
+
+	stats := manager.Stats()
+	if len(stats) != 2 {
+		t.Fatalf("Stats() returned %d databases, want 2", len(stats))
+	}
+	if stats[0].DB != 0 || stats[1].DB != 1 {
+		t.Errorf("Stats() order = %d, %d, want 0, 1", stats[0].DB, stats[1].DB)
+	}
+	if stats[1].PoolSize != 4 {
+		t.Errorf("cache PoolSize = %d, want 4", stats[1].PoolSize)
+	}
+	if len(stats[1].Names) != 2 || stats[1].Names[0] != "cache" || stats[1].Names[1] != "hot-cache" {
+		t.Errorf("cache Names = %v, want [cache hot-cache]", stats[1].Names)
+	}
+
+	ctx := context.Background()
+	before := stats[1].LastUsed
+	if err := manager.PingDB(ctx, "cache"); err == nil {
+		t.Error("PingDB() to an unreachable server should fail")
+	}
+	if after := manager.Stats()[1].LastUsed; !after.After(before) {
+		t.Error("a command should update LastUsed")
+	}
+
+	results := manager.PingEach(ctx)
+	if len(results) != 2 {
+		t.Fatalf("PingEach() returned %d results, want 2", len(results))
+	}
+	for db, err := range results {
+		if err == nil {
+			t.Errorf("PingEach() DB %d error = nil, want connection error", db)
+		}
+	}
+
+	if err := manager.PingDB(ctx, "unknown"); err == nil {
+		t.Error("PingDB() with an unknown name should fail")
+	}
+}
+
+// isClosed reports whether a client was closed
+func isClosed(c *Client) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.closed
+}
@@ -0,0 +1,389 @@
+package redisclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ====================
+// Leader Election
+// ====================
+
+// campaignScript takes the lease if it is free or already ours
+// KEYS[1] = lease key, ARGV[1] = candidate ID, ARGV[2] = TTL in ms
+var campaignScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// ElectionOptions configures a LeaderElector
+type ElectionOptions struct {
+	ID             string              // Candidate identity, unique per replica (default: hostname-pid-random)
+	TTL            time.Duration       // Lease duration; a crashed leader is replaced after at most this long
+	RenewInterval  time.Duration       // How often the leader renews the lease, default TTL/3
+	RetryInterval  time.Duration       // How often followers try to take the lease and observe the leader
+	OnLeaderChange func(leader string) // Called with the new leader ID, "" if unknown; must not block
+	ErrorHandler   func(err error)     // Called for campaign and renewal errors
+}
+
+// DefaultElectionOptions returns the default election options
+func DefaultElectionOptions() ElectionOptions {
+	return ElectionOptions{
+		TTL:           15 * time.Second,
+		RetryInterval: time.Second,
+	}
+}
+
+// ElectionOption is a functional option for configuring a LeaderElector
+type ElectionOption func(*ElectionOptions)
+
+// WithElectionID sets the candidate identity
+func WithElectionID(id string) ElectionOption {
+	return func(o *ElectionOptions) {
+		o.ID = id
+	}
+}
+
+// WithElectionTTL sets the lease duration and renewal interval. An interval of
+// 0 uses TTL/3.
+func WithElectionTTL(ttl, renewInterval time.Duration) ElectionOption {
+	return func(o *ElectionOptions) {
+		o.TTL = ttl
+		o.RenewInterval = renewInterval
+	}
+}
+
+// WithElectionRetryInterval sets how often followers try to take the lease
+func WithElectionRetryInterval(d time.Duration) ElectionOption {
+	return func(o *ElectionOptions) {
+		o.RetryInterval = d
+	}
+}
+
+// WithLeaderChangeHandler sets the callback for leader changes
+func WithLeaderChangeHandler(fn func(leader string)) ElectionOption {
+	return func(o *ElectionOptions) {
+		o.OnLeaderChange = fn
+	}
+}
+
+// WithElectionErrorHandler sets the callback for background errors
+func WithElectionErrorHandler(fn func(err error)) ElectionOption {
+	return func(o *ElectionOptions) {
+		o.ErrorHandler = fn
+	}
+}
+
+// LeaderElector elects one leader among replicas with a lease key. The leader
+// renews the lease in the background; if it cannot renew within the TTL it
+// steps down, and another candidate takes the lease once it expires.
+type LeaderElector struct {
+	client  *Client
+	key     string
+	opts    ElectionOptions
+	changes chan string
+
+	mu         sync.Mutex
+	leader     string        // last observed leader ID
+	leading    bool          // whether this candidate holds the lease
+	term       chan struct{} // closed when the current term ends
+	stopRenew  context.CancelFunc
+	renewDone  chan struct{}
+	running    bool
+	notifyLock sync.Mutex // orders leader change notifications
+}
+
+// NewLeaderElector creates a candidate for the election stored at key
+func (c *Client) NewLeaderElector(key string, opts ...ElectionOption) (*LeaderElector, error) {
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	options := DefaultElectionOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.TTL < 10*time.Millisecond {
+		return nil, fmt.Errorf("election TTL must be at least 10ms")
+	}
+	if options.RenewInterval <= 0 {
+		options.RenewInterval = options.TTL / 3
+	}
+	if options.RenewInterval >= options.TTL {
+		return nil, fmt.Errorf("renew interval must be shorter than the TTL")
+	}
+	if options.RetryInterval <= 0 {
+		return nil, fmt.Errorf("retry interval must be positive")
+	}
+	if options.ID == "" {
+		suffix, err := generateLockToken()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate candidate id: %w", err)
+		}
+		options.ID = defaultConsumerName() + "-" + suffix[:8]
+	}
+
+	term := make(chan struct{})
+	close(term)
+
+	return &LeaderElector{
+		client:  c,
+		key:     key,
+		opts:    options,
+		changes: make(chan string, 1),
+		term:    term,
+	}, nil
+}
+
+// ID returns this candidate's identity
+func (e *LeaderElector) ID() string {
+	return e.opts.ID
+}
+
+// IsLeader returns true while this candidate holds the lease
+func (e *LeaderElector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leading
+}
+
+// Leader returns the ID of the current leader, or ErrNil if there is none
+func (e *LeaderElector) Leader(ctx context.Context) (string, error) {
+	return e.client.Get(ctx, e.key)
+}
+
+// Changes returns a channel that receives the leader ID whenever it changes,
+// "" when it is unknown. Only the latest change is buffered.
+func (e *LeaderElector) Changes() <-chan string {
+	return e.changes
+}
+
+// Done returns a channel that is closed when this candidate's current term as
+// leader ends. It is already closed while the candidate is not leading.
+func (e *LeaderElector) Done() <-chan struct{} {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.term
+}
+
+// Campaign blocks until this candidate becomes leader, retrying every
+// RetryInterval. Once elected the lease is renewed in the background until
+// Resign is called or a renewal fails for longer than the TTL. It returns the
+// context error if ctx is done first.
+func (e *LeaderElector) Campaign(ctx context.Context) error {
+	for {
+		if e.IsLeader() {
+			return nil
+		}
+
+		start := time.Now()
+		won, err := e.client.runScript(ctx, campaignScript, []string{e.key}, e.opts.ID, e.opts.TTL.Milliseconds()).Int()
+		switch {
+		case err == ErrClientClosed:
+			return err
+		case err != nil:
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			e.reportError(fmt.Errorf("failed to campaign for %s: %w", e.key, err))
+		case won == 1:
+			e.elected(start)
+			return nil
+		default:
+			e.observe(ctx)
+		}
+
+		timer := time.NewTimer(e.opts.RetryInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Resign gives up the lease if this candidate holds it, letting another
+// candidate take over without waiting for the TTL
+func (e *LeaderElector) Resign(ctx context.Context) error {
+	e.mu.Lock()
+	stop, done := e.stopRenew, e.renewDone
+	e.mu.Unlock()
+
+	if stop == nil {
+		return nil
+	}
+	stop()
+	<-done
+
+	if !e.stepDown() {
+		return nil
+	}
+	e.setLeader("")
+	if _, err := e.client.runScript(ctx, releaseScript, []string{e.key}, e.opts.ID).Result(); err != nil {
+		return fmt.Errorf("failed to resign from %s: %w", e.key, err)
+	}
+	return nil
+}
+
+// Run campaigns until ctx is cancelled, campaigning again whenever leadership
+// is lost, and resigns on return
+func (e *LeaderElector) Run(ctx context.Context) error {
+	e.mu.Lock()
+	if e.running {
+		e.mu.Unlock()
+		return fmt.Errorf("leader elector is already running")
+	}
+	e.running = true
+	e.mu.Unlock()
+
+	defer func() {
+		resignCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if err := e.Resign(resignCtx); err != nil {
+			e.reportError(err)
+		}
+
+		e.mu.Lock()
+		e.running = false
+		e.mu.Unlock()
+	}()
+
+	for {
+		if err := e.Campaign(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-e.Done():
+		}
+	}
+}
+
+// elected starts a term whose lease was granted at start
+func (e *LeaderElector) elected(start time.Time) {
+	renewCtx, stop := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	e.mu.Lock()
+	e.leading = true
+	e.term = make(chan struct{})
+	e.stopRenew = stop
+	e.renewDone = done
+	e.mu.Unlock()
+
+	e.setLeader(e.opts.ID)
+
+	go func() {
+		defer close(done)
+		e.renewLoop(renewCtx, start)
+	}()
+}
+
+// renewLoop extends the lease every RenewInterval and steps down when the
+// lease is taken over or could not be renewed before it expired
+func (e *LeaderElector) renewLoop(ctx context.Context, renewed time.Time) {
+	ticker := time.NewTicker(e.opts.RenewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		start := time.Now()
+		ok, err := e.client.runScript(ctx, extendScript, []string{e.key}, e.opts.ID, e.opts.TTL.Milliseconds()).Int()
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			e.reportError(fmt.Errorf("failed to renew lease %s: %w", e.key, err))
+			// Step down before the lease can expire under us
+			if time.Since(renewed)+e.opts.RenewInterval < e.opts.TTL {
+				continue
+			}
+		} else if ok == 1 {
+			renewed = start
+			continue
+		}
+
+		// Lost the lease; another candidate may already lead
+		if e.stepDown() {
+			e.setLeader("")
+		}
+		return
+	}
+}
+
+// stepDown ends the current term and reports whether one was active
+func (e *LeaderElector) stepDown() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stopRenew = nil
+	if !e.leading {
+		return false
+	}
+	e.leading = false
+	close(e.term)
+	return true
+}
+
+// observe records the current leader seen by a follower
+func (e *LeaderElector) observe(ctx context.Context) {
+	leader, err := e.client.Get(ctx, e.key)
+	if err != nil && !IsNil(err) {
+		return
+	}
+	e.setLeader(leader)
+}
+
+// setLeader records the leader ID and notifies listeners when it changed
+func (e *LeaderElector) setLeader(leader string) {
+	e.notifyLock.Lock()
+	defer e.notifyLock.Unlock()
+
+	e.mu.Lock()
+	changed := e.leader != leader
+	e.leader = leader
+	e.mu.Unlock()
+	if !changed {
+		return
+	}
+
+	// Latest value wins when the listener is behind
+	select {
+	case <-e.changes:
+	default:
+	}
+	e.changes <- leader
+
+	if e.opts.OnLeaderChange != nil {
+		e.opts.OnLeaderChange(leader)
+	}
+}
+
+// reportError passes a background error to the error handler
+func (e *LeaderElector) reportError(err error) {
+	if e.opts.ErrorHandler != nil {
+		e.opts.ErrorHandler(err)
+	}
+}
@@ -0,0 +1,91 @@
+package redisclient
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestNewLeaderElector tests option validation and defaults
+func TestNewLeaderElector(t *testing.T) {
+	client := &Client{config: DefaultConfig()}
+
+	if _, err := client.NewLeaderElector(""); err != ErrInvalidKey {
+		t.Errorf("NewLeaderElector(\"\") error = %v, want %v", err, ErrInvalidKey)
+	}
+	if _, err := client.NewLeaderElector("leader", WithElectionTTL(time.Millisecond, 0)); err == nil {
+		t.Error("NewLeaderElector() should reject a tiny TTL")
+	}
+	if _, err := client.NewLeaderElector("leader", WithElectionTTL(time.Second, time.Second)); err == nil {
+		t.Error("NewLeaderElector() should reject a renew interval not shorter than the TTL")
+	}
+	if _, err := client.NewLeaderElector("leader", WithElectionRetryInterval(0)); err == nil {
+		t.Error("NewLeaderElector() should reject a zero retry interval")
+	}
+
+	e, err := client.NewLeaderElector("leader", WithElectionTTL(9*time.Second, 0))
+	if err != nil {
+		t.Fatalf("NewLeaderElector() error = %v", err)
+	}
+	if e.opts.RenewInterval != 3*time.Second {
+		t.Errorf("RenewInterval = %v, want TTL/3", e.opts.RenewInterval)
+	}
+	if e.ID() == "" {
+		t.Error("ID() should default to a generated identity")
+	}
+	if e.IsLeader() {
+		t.Error("IsLeader() should be false before campaigning")
+	}
+	select {
+	case <-e.Done():
+	default:
+		t.Error("Done() should be closed while not leading")
+	}
+
+	named, _ := client.NewLeaderElector("leader", WithElectionID("replica-1"))
+	if named.ID() != "replica-1" {
+		t.Errorf("ID() = %q, want replica-1", named.ID())
+	}
+}
+
+// TestLeaderElector_ClosedClient tests that campaigning on a closed client fails
+func TestLeaderElector_ClosedClient(t *testing.T) {
+	client := &Client{config: DefaultConfig(), closed: true}
+	e, err := client.NewLeaderElector("leader")
+	if err != nil {
+		t.Fatalf("NewLeaderElector() error = %v", err)
+	}
+
+	if err := e.Campaign(context.Background()); err != ErrClientClosed {
+		t.Errorf("Campaign() error = %v, want %v", err, ErrClientClosed)
+	}
+	if err := e.Run(context.Background()); err != ErrClientClosed {
+		t.Errorf("Run() error = %v, want %v", err, ErrClientClosed)
+	}
+	if err := e.Resign(context.Background()); err != nil {
+		t.Errorf("Resign() when not leading error = %v", err)
+	}
+}
+
+// TestLeaderElector_Changes tests leader change notifications
+func TestLeaderElector_Changes(t *testing.T) {
+	var seen []string
+	client := &Client{config: DefaultConfig()}
+	e, err := client.NewLeaderElector("leader", WithLeaderChangeHandler(func(leader string) {
+		seen = append(seen, leader)
+	}))
+	if err != nil {
+		t.Fatalf("NewLeaderElector() error = %v", err)
+	}
+
+	e.setLeader("a")
+	e.setLeader("a")
+	e.setLeader("b")
+
+	if len(seen) != 2 || seen[0] != "a" || seen[1] != "b" {
+		t.Errorf("callback saw %v, want [a b]", seen)
+	}
+	if got := <-e.Changes(); got != "b" {
+		t.Errorf("Changes() = %q, want the latest leader b", got)
+	}
+}
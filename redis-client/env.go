@@ -33,6 +33,25 @@ func LoadConfigFromEnv(prefix string) (*Config, error) {
 		TLSCAFile:       env.GetString("TLS_CA_FILE", ""),
 	}
 
+	// Cluster and sentinel settings
+	config.ClusterAddrs = env.GetStringSlice("CLUSTER_ADDRS", nil)
+	config.ReadFromReplica = env.GetBool("READ_FROM_REPLICA", false)
+	config.RouteByLatency = env.GetBool("ROUTE_BY_LATENCY", false)
+	config.MasterName = env.GetString("MASTER_NAME", "")
+	config.SentinelAddrs = env.GetStringSlice("SENTINEL_ADDRS", nil)
+	config.SentinelPassword = env.GetString("SENTINEL_PASSWORD", "")
+
+	// Key namespacing
+	config.KeyPrefix = env.GetString("KEY_PREFIX", "")
+
+	// Observability
+	config.SlowLogThreshold = env.GetDuration("SLOW_LOG_THRESHOLD", 0)
+	config.Tracing = env.GetBool("TRACING", false)
+
+	// Multi-database connection management
+	config.MaxTotalConns = env.GetInt("MAX_TOTAL_CONNS", 0)
+	config.DBIdleTimeout = env.GetDuration("DB_IDLE_TIMEOUT", 0)
+
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration from environment: %w", err)
 	}
@@ -21,6 +21,36 @@ var (
 
 	// ErrInvalidKey is returned when key is empty or invalid
 	ErrInvalidKey = errors.New("invalid key")
+
+	// ErrLockNotAcquired is returned when a lock is held by someone else
+	ErrLockNotAcquired = errors.New("lock not acquired")
+
+	// ErrNotFound is returned by cache loaders when a value does not exist, and
+	// by the cache for negatively cached keys
+	ErrNotFound = errors.New("not found")
+
+	// ErrCacheCorrupt is returned when a cached value cannot be decoded
+	ErrCacheCorrupt = errors.New("corrupt cache entry")
+
+	// ErrLockNotHeld is returned when releasing or extending a lock that expired or was taken over
+	ErrLockNotHeld = errors.New("lock not held")
+
+	// ErrJobExists is returned when enqueuing a job whose unique key is taken
+	ErrJobExists = errors.New("job already exists")
+
+	// ErrTxConflict is returned when a transaction's watched keys kept changing
+	// until its retries ran out
+	ErrTxConflict = errors.New("transaction conflict")
+
+	// ErrValueDecode is returned when a stored value cannot be decrypted or decompressed
+	ErrValueDecode = errors.New("failed to decode value")
+
+	// ErrUnknownKeyID is returned when a value was encrypted with a key that is not registered
+	ErrUnknownKeyID = errors.New("unknown encryption key id")
+
+	// ErrConnBudgetExhausted is returned when a DBManager has no connections
+	// left in MaxTotalConns to open another database
+	ErrConnBudgetExhausted = errors.New("connection budget exhausted")
 )
 
 // IsNil returns true if the error is redis.Nil (key doesn't exist)
@@ -1,15 +1,30 @@
 module github.com/isimtekin/go-packages/redis-client
 
-go 1.21
+go 1.24.0
 
 require (
+	github.com/isimtekin/go-packages/crypto-utils v0.0.0-00010101000000-000000000000
 	github.com/isimtekin/go-packages/env-util v0.0.0-00010101000000-000000000000
+	github.com/klauspost/compress v1.18.1
 	github.com/redis/go-redis/v9 v9.16.0
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	go.opentelemetry.io/otel v1.38.0
+	go.opentelemetry.io/otel/trace v1.38.0
+	golang.org/x/sync v0.9.0
 )
 
 require (
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
+	go.opentelemetry.io/otel/metric v1.38.0 // indirect
+	golang.org/x/crypto v0.44.0 // indirect
 )
 
-replace github.com/isimtekin/go-packages/env-util => ../env-util
+replace (
+	github.com/isimtekin/go-packages/crypto-utils => ../crypto-utils
+	github.com/isimtekin/go-packages/env-util => ../env-util
+)
@@ -0,0 +1,240 @@
+package redisclient
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Eviction selects how the local cache tier evicts entries when it is full
+type Eviction int
+
+const (
+	// EvictLRU evicts the least recently used entry
+	EvictLRU Eviction = iota
+	// EvictLFU evicts the least frequently used entry, oldest first on ties
+	EvictLFU
+)
+
+// String returns the name of the eviction policy
+func (e Eviction) String() string {
+	switch e {
+	case EvictLRU:
+		return "lru"
+	case EvictLFU:
+		return "lfu"
+	default:
+		return "unknown"
+	}
+}
+
+// localEntry is an item in the local cache
+type localEntry[V any] struct {
+	key       string
+	value     V
+	expiresAt time.Time // Zero means no expiry
+	freq      int       // Access count, LFU only
+	elem      *list.Element
+}
+
+// localCache is a bounded in-process cache with LRU or LFU eviction
+type localCache[V any] struct {
+	mu       sync.Mutex
+	size     int
+	ttl      time.Duration
+	eviction Eviction
+	items    map[string]*localEntry[V]
+
+	// LRU: most recently used at the front
+	order *list.List
+
+	// LFU: one list per access count, most recently used at the front
+	freqs   map[int]*list.List
+	minFreq int
+
+	evictions uint64
+}
+
+// newLocalCache creates a local cache holding at most size entries
+func newLocalCache[V any](size int, ttl time.Duration, eviction Eviction) *localCache[V] {
+	return &localCache[V]{
+		size:     size,
+		ttl:      ttl,
+		eviction: eviction,
+		items:    make(map[string]*localEntry[V]),
+		order:    list.New(),
+		freqs:    make(map[int]*list.List),
+	}
+}
+
+// get returns a live entry and records the access
+func (lc *localCache[V]) get(key string) (V, bool) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	var zero V
+
+	entry, ok := lc.items[key]
+	if !ok {
+		return zero, false
+	}
+
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		lc.remove(entry)
+		return zero, false
+	}
+
+	lc.touch(entry)
+	return entry.value, true
+}
+
+// set stores a value, evicting an entry if the cache is full
+func (lc *localCache[V]) set(key string, value V) {
+	if lc.size <= 0 {
+		return
+	}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	var expiresAt time.Time
+	if lc.ttl > 0 {
+		expiresAt = time.Now().Add(lc.ttl)
+	}
+
+	if entry, ok := lc.items[key]; ok {
+		entry.value = value
+		entry.expiresAt = expiresAt
+		lc.touch(entry)
+		return
+	}
+
+	if len(lc.items) >= lc.size {
+		lc.evict()
+	}
+
+	entry := &localEntry[V]{key: key, value: value, expiresAt: expiresAt}
+	lc.items[key] = entry
+
+	if lc.eviction == EvictLFU {
+		entry.freq = 1
+		entry.elem = lc.freqList(1).PushFront(entry)
+		lc.minFreq = 1
+		return
+	}
+	entry.elem = lc.order.PushFront(entry)
+}
+
+// delete removes keys
+func (lc *localCache[V]) delete(keys ...string) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	for _, key := range keys {
+		if entry, ok := lc.items[key]; ok {
+			lc.remove(entry)
+		}
+	}
+}
+
+// clear removes all entries
+func (lc *localCache[V]) clear() {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	lc.items = make(map[string]*localEntry[V])
+	lc.order.Init()
+	lc.freqs = make(map[int]*list.List)
+	lc.minFreq = 0
+}
+
+// len returns the number of entries, including expired ones not yet removed
+func (lc *localCache[V]) len() int {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	return len(lc.items)
+}
+
+// evictionCount returns the number of entries evicted for space
+func (lc *localCache[V]) evictionCount() uint64 {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	return lc.evictions
+}
+
+// touch records an access
+func (lc *localCache[V]) touch(entry *localEntry[V]) {
+	if lc.eviction != EvictLFU {
+		lc.order.MoveToFront(entry.elem)
+		return
+	}
+
+	old := lc.freqs[entry.freq]
+	old.Remove(entry.elem)
+	if old.Len() == 0 {
+		delete(lc.freqs, entry.freq)
+		if lc.minFreq == entry.freq {
+			lc.minFreq++
+		}
+	}
+
+	entry.freq++
+	entry.elem = lc.freqList(entry.freq).PushFront(entry)
+}
+
+// evict removes one entry according to the policy
+func (lc *localCache[V]) evict() {
+	var victim *list.Element
+
+	if lc.eviction == EvictLFU {
+		if l, ok := lc.freqs[lc.minFreq]; ok {
+			victim = l.Back()
+		}
+	} else {
+		victim = lc.order.Back()
+	}
+
+	if victim != nil {
+		lc.remove(victim.Value.(*localEntry[V]))
+		lc.evictions++
+	}
+}
+
+// remove deletes an entry from the map and its list
+func (lc *localCache[V]) remove(entry *localEntry[V]) {
+	delete(lc.items, entry.key)
+
+	if lc.eviction != EvictLFU {
+		lc.order.Remove(entry.elem)
+		return
+	}
+
+	l := lc.freqs[entry.freq]
+	l.Remove(entry.elem)
+	if l.Len() == 0 {
+		delete(lc.freqs, entry.freq)
+		if lc.minFreq == entry.freq {
+			lc.recomputeMinFreq()
+		}
+	}
+}
+
+// recomputeMinFreq finds the lowest access count after a removal
+func (lc *localCache[V]) recomputeMinFreq() {
+	lc.minFreq = 0
+	for freq := range lc.freqs {
+		if lc.minFreq == 0 || freq < lc.minFreq {
+			lc.minFreq = freq
+		}
+	}
+}
+
+// freqList returns the list for an access count, creating it if needed
+func (lc *localCache[V]) freqList(freq int) *list.List {
+	l, ok := lc.freqs[freq]
+	if !ok {
+		l = list.New()
+		lc.freqs[freq] = l
+	}
+	return l
+}
@@ -0,0 +1,498 @@
+package redisclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	mrand "math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ====================
+// Distributed Locks
+// ====================
+
+var (
+	// acquireScript sets the lock if it is free and increments the fencing counter
+	// KEYS[1] = lock key, KEYS[2] = fencing key, ARGV[1] = token, ARGV[2] = TTL in ms
+	acquireScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0
+`)
+
+	// releaseScript deletes the lock only if it is still held by the token
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+	// extendScript resets the lock TTL only if it is still held by the token
+	extendScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+)
+
+// LockOptions configures a Mutex
+type LockOptions struct {
+	TTL           time.Duration // Lock expiry, renewed by the watchdog when AutoRenew is set
+	RetryDelay    time.Duration // Initial delay between Acquire attempts
+	MaxRetryDelay time.Duration // Upper bound for the exponential backoff
+	MaxRetries    int           // Maximum Acquire attempts, 0 retries until the context is done
+	AutoRenew     bool          // Extend the TTL in the background while the lock is held
+	RenewInterval time.Duration // Watchdog interval, default TTL/3
+	DriftFactor   float64       // Clock drift allowance for Redlock validity, fraction of TTL
+}
+
+// DefaultLockOptions returns the default lock options
+func DefaultLockOptions() LockOptions {
+	return LockOptions{
+		TTL:           30 * time.Second,
+		RetryDelay:    50 * time.Millisecond,
+		MaxRetryDelay: 2 * time.Second,
+		MaxRetries:    0,
+		AutoRenew:     false,
+		DriftFactor:   0.01,
+	}
+}
+
+// LockOption is a functional option for configuring a Mutex
+type LockOption func(*LockOptions)
+
+// WithLockTTL sets the lock expiry
+func WithLockTTL(ttl time.Duration) LockOption {
+	return func(o *LockOptions) {
+		o.TTL = ttl
+	}
+}
+
+// WithLockRetry sets the backoff used by Acquire
+func WithLockRetry(delay, maxDelay time.Duration, maxRetries int) LockOption {
+	return func(o *LockOptions) {
+		o.RetryDelay = delay
+		o.MaxRetryDelay = maxDelay
+		o.MaxRetries = maxRetries
+	}
+}
+
+// WithLockAutoRenew enables the watchdog that extends the TTL while the lock is held.
+// An interval of 0 uses TTL/3.
+func WithLockAutoRenew(interval time.Duration) LockOption {
+	return func(o *LockOptions) {
+		o.AutoRenew = true
+		o.RenewInterval = interval
+	}
+}
+
+// Mutex is a distributed mutex on a single Redis instance, or on several
+// independent instances when created with NewRedlock
+type Mutex struct {
+	clients    []*Client
+	key        string
+	fencingKey string
+	opts       LockOptions
+}
+
+// NewMutex creates a distributed mutex for the given key
+func (c *Client) NewMutex(key string, opts ...LockOption) *Mutex {
+	return newMutex([]*Client{c}, key, opts)
+}
+
+// NewRedlock creates a distributed mutex using the Redlock algorithm across
+// independent Redis instances. The lock is held when a majority of instances
+// grant it within the lock validity time.
+func NewRedlock(clients []*Client, key string, opts ...LockOption) *Mutex {
+	return newMutex(clients, key, opts)
+}
+
+// newMutex creates a mutex with options applied over the defaults
+func newMutex(clients []*Client, key string, opts []LockOption) *Mutex {
+	options := DefaultLockOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.RenewInterval <= 0 {
+		options.RenewInterval = options.TTL / 3
+	}
+
+	return &Mutex{
+		clients:    clients,
+		key:        key,
+		fencingKey: fencingKey(key),
+		opts:       options,
+	}
+}
+
+// Key returns the lock key
+func (m *Mutex) Key() string {
+	return m.key
+}
+
+// TryLock attempts to acquire the lock once. It returns ErrLockNotAcquired when
+// the lock is held by someone else.
+func (m *Mutex) TryLock(ctx context.Context) (*Lock, error) {
+	if m.key == "" {
+		return nil, ErrInvalidKey
+	}
+	if m.opts.TTL <= 0 {
+		return nil, ErrInvalidTTL
+	}
+	if len(m.clients) == 0 {
+		return nil, fmt.Errorf("mutex has no clients")
+	}
+
+	token, err := generateLockToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate lock token: %w", err)
+	}
+
+	start := time.Now()
+	granted := 0
+	var fencing int64
+	var lastErr error
+
+	for _, client := range m.clients {
+		n, err := m.acquireOn(ctx, client, token)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if n > 0 {
+			granted++
+			if n > fencing {
+				fencing = n
+			}
+		}
+	}
+
+	// The lock is only usable for what is left of the TTL, minus clock drift
+	drift := time.Duration(float64(m.opts.TTL)*m.opts.DriftFactor) + 2*time.Millisecond
+	validity := m.opts.TTL - time.Since(start) - drift
+
+	if granted < m.quorum() || validity <= 0 {
+		m.releaseAll(context.WithoutCancel(ctx), token)
+		if granted == 0 && lastErr != nil {
+			return nil, fmt.Errorf("failed to acquire lock %q: %w", m.key, lastErr)
+		}
+		return nil, ErrLockNotAcquired
+	}
+
+	lock := &Lock{
+		mutex:        m,
+		token:        token,
+		fencingToken: fencing,
+		lost:         make(chan struct{}),
+	}
+
+	if m.opts.AutoRenew {
+		lock.startWatchdog()
+	}
+
+	return lock, nil
+}
+
+// Acquire blocks until the lock is acquired, retrying with exponential backoff
+// and jitter. It returns the context error if ctx is done first.
+func (m *Mutex) Acquire(ctx context.Context) (*Lock, error) {
+	delay := m.opts.RetryDelay
+	if delay <= 0 {
+		delay = DefaultLockOptions().RetryDelay
+	}
+
+	for attempt := 1; ; attempt++ {
+		lock, err := m.TryLock(ctx)
+		if err == nil {
+			return lock, nil
+		}
+		if err != ErrLockNotAcquired {
+			return nil, err
+		}
+		if m.opts.MaxRetries > 0 && attempt >= m.opts.MaxRetries {
+			return nil, ErrLockNotAcquired
+		}
+
+		// Jitter keeps competing waiters from retrying in lockstep
+		wait := delay/2 + time.Duration(mrand.Int63n(int64(delay/2)+1))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		delay *= 2
+		if m.opts.MaxRetryDelay > 0 && delay > m.opts.MaxRetryDelay {
+			delay = m.opts.MaxRetryDelay
+		}
+	}
+}
+
+// WithLock acquires the lock, runs fn and releases the lock. The context passed
+// to fn is cancelled if the watchdog loses the lock.
+func (m *Mutex) WithLock(ctx context.Context, fn func(ctx context.Context, lock *Lock) error) error {
+	lock, err := m.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	fnCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-lock.Lost():
+			cancel()
+		case <-fnCtx.Done():
+		}
+	}()
+
+	fnErr := fn(fnCtx, lock)
+
+	if err := lock.Release(context.WithoutCancel(ctx)); err != nil && fnErr == nil {
+		return err
+	}
+	return fnErr
+}
+
+// quorum returns the number of instances that must grant the lock
+func (m *Mutex) quorum() int {
+	return len(m.clients)/2 + 1
+}
+
+// acquireOn runs the acquire script on one instance and returns the fencing
+// token, or 0 if the lock is held by someone else
+func (m *Mutex) acquireOn(ctx context.Context, client *Client, token string) (int64, error) {
+	client.mu.RLock()
+	defer client.mu.RUnlock()
+
+	if client.closed {
+		return 0, ErrClientClosed
+	}
+
+	ctx, cancel := m.instanceContext(ctx)
+	defer cancel()
+
+	return acquireScript.Run(ctx, client.client,
+		[]string{m.key, m.fencingKey}, token, m.opts.TTL.Milliseconds()).Int64()
+}
+
+// releaseAll releases the token on every instance, ignoring errors
+func (m *Mutex) releaseAll(ctx context.Context, token string) int {
+	released := 0
+	for _, client := range m.clients {
+		if ok, err := m.runOwned(ctx, client, releaseScript, token); err == nil && ok {
+			released++
+		}
+	}
+	return released
+}
+
+// runOwned runs a compare-and-act script on one instance
+func (m *Mutex) runOwned(ctx context.Context, client *Client, script *redis.Script, token string, args ...interface{}) (bool, error) {
+	client.mu.RLock()
+	defer client.mu.RUnlock()
+
+	if client.closed {
+		return false, ErrClientClosed
+	}
+
+	ctx, cancel := m.instanceContext(ctx)
+	defer cancel()
+
+	n, err := script.Run(ctx, client.client, []string{m.key}, append([]interface{}{token}, args...)...).Int64()
+	return n == 1, err
+}
+
+// instanceContext bounds a single-instance call in Redlock mode so one slow
+// instance cannot consume the lock validity
+func (m *Mutex) instanceContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if len(m.clients) == 1 {
+		return ctx, func() {}
+	}
+	timeout := m.opts.TTL / 10
+	if timeout < 50*time.Millisecond {
+		timeout = 50 * time.Millisecond
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Lock is a held distributed lock
+type Lock struct {
+	mutex        *Mutex
+	token        string
+	fencingToken int64
+
+	mu       sync.Mutex
+	released bool
+	stop     chan struct{}
+	done     chan struct{}
+	lost     chan struct{}
+	lostOnce sync.Once
+}
+
+// Key returns the lock key
+func (l *Lock) Key() string {
+	return l.mutex.key
+}
+
+// Token returns the random owner token stored in the lock key
+func (l *Lock) Token() string {
+	return l.token
+}
+
+// FencingToken returns a number that increases with every acquisition of the
+// lock. Pass it to the protected resource so it can reject writes from a
+// previous holder whose lock expired. In Redlock mode it is the highest
+// counter among the instances that granted the lock.
+func (l *Lock) FencingToken() int64 {
+	return l.fencingToken
+}
+
+// Lost returns a channel closed when the watchdog fails to extend the lock
+func (l *Lock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Extend resets the lock TTL. It returns ErrLockNotHeld if the lock expired or
+// was taken over.
+func (l *Lock) Extend(ctx context.Context, ttl time.Duration) error {
+	if ttl <= 0 {
+		return ErrInvalidTTL
+	}
+
+	m := l.mutex
+	granted := 0
+	var lastErr error
+
+	for _, client := range m.clients {
+		ok, err := m.runOwned(ctx, client, extendScript, l.token, ttl.Milliseconds())
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if ok {
+			granted++
+		}
+	}
+
+	if granted >= m.quorum() {
+		return nil
+	}
+	if granted == 0 && lastErr != nil {
+		return fmt.Errorf("failed to extend lock %q: %w", m.key, lastErr)
+	}
+	return ErrLockNotHeld
+}
+
+// Release releases the lock if it is still held by this owner. It returns
+// ErrLockNotHeld if the lock expired or was taken over.
+func (l *Lock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	if l.released {
+		l.mu.Unlock()
+		return ErrLockNotHeld
+	}
+	l.released = true
+	stop, done := l.stop, l.done
+	l.mu.Unlock()
+
+	// Stop the watchdog before deleting so it cannot re-extend the key
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+
+	m := l.mutex
+	released := 0
+	var lastErr error
+
+	for _, client := range m.clients {
+		ok, err := m.runOwned(ctx, client, releaseScript, l.token)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if ok {
+			released++
+		}
+	}
+
+	if released >= m.quorum() {
+		return nil
+	}
+	if released == 0 && lastErr != nil {
+		return fmt.Errorf("failed to release lock %q: %w", m.key, lastErr)
+	}
+	return ErrLockNotHeld
+}
+
+// startWatchdog extends the lock every RenewInterval until it is released
+func (l *Lock) startWatchdog() {
+	l.stop = make(chan struct{})
+	l.done = make(chan struct{})
+
+	go func() {
+		defer close(l.done)
+
+		ticker := time.NewTicker(l.mutex.opts.RenewInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-l.stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), l.mutex.opts.RenewInterval)
+				err := l.Extend(ctx, l.mutex.opts.TTL)
+				cancel()
+
+				// Transient errors are retried on the next tick; the lock is only
+				// lost once it is no longer held
+				if err == ErrLockNotHeld {
+					l.markLost()
+					return
+				}
+			}
+		}
+	}()
+}
+
+// markLost closes the lost channel once
+func (l *Lock) markLost() {
+	l.lostOnce.Do(func() {
+		close(l.lost)
+	})
+}
+
+// fencingKey returns the fencing counter key for a lock key. The counter shares
+// the lock key's hash slot so both can be updated by one script in cluster mode.
+// Keys that already contain braces need an explicit hash tag to stay in one slot.
+func fencingKey(key string) string {
+	if strings.ContainsAny(key, "{}") {
+		return key + ":fence"
+	}
+	return "{" + key + "}:fence"
+}
+
+// generateLockToken generates a random owner token
+func generateLockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
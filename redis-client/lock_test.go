@@ -0,0 +1,109 @@
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestFencingKey tests that fencing keys share the lock key's hash slot
+func TestFencingKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "lock:orders", want: "{lock:orders}:fence"},
+		{key: "lock:{user:1}", want: "lock:{user:1}:fence"},
+		{key: "{jobs}:lock", want: "{jobs}:lock:fence"},
+	}
+
+	for _, tt := range tests {
+		if got := fencingKey(tt.key); got != tt.want {
+			t.Errorf("fencingKey(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+// TestNewMutex_Options tests lock option defaults and overrides
+func TestNewMutex_Options(t *testing.T) {
+	client := &Client{config: DefaultConfig()}
+
+	m := client.NewMutex("lock:a")
+	if m.opts.TTL != 30*time.Second {
+		t.Errorf("default TTL = %v, want 30s", m.opts.TTL)
+	}
+	if m.opts.RenewInterval != 10*time.Second {
+		t.Errorf("default RenewInterval = %v, want TTL/3", m.opts.RenewInterval)
+	}
+	if m.opts.AutoRenew {
+		t.Error("AutoRenew should be disabled by default")
+	}
+
+	m = client.NewMutex("lock:a",
+		WithLockTTL(6*time.Second),
+		WithLockRetry(10*time.Millisecond, time.Second, 5),
+		WithLockAutoRenew(0),
+	)
+	if m.opts.TTL != 6*time.Second || m.opts.RenewInterval != 2*time.Second || !m.opts.AutoRenew {
+		t.Errorf("unexpected options %+v", m.opts)
+	}
+	if m.opts.RetryDelay != 10*time.Millisecond || m.opts.MaxRetries != 5 {
+		t.Errorf("unexpected retry options %+v", m.opts)
+	}
+}
+
+// TestRedlock_Quorum tests the majority needed for Redlock
+func TestRedlock_Quorum(t *testing.T) {
+	for n, want := range map[int]int{1: 1, 2: 2, 3: 2, 4: 3, 5: 3} {
+		m := NewRedlock(make([]*Client, n), "lock:a")
+		if got := m.quorum(); got != want {
+			t.Errorf("quorum(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+// TestMutex_InvalidInput tests lock validation errors
+func TestMutex_InvalidInput(t *testing.T) {
+	client := &Client{config: DefaultConfig()}
+	ctx := context.Background()
+
+	if _, err := client.NewMutex("").TryLock(ctx); err != ErrInvalidKey {
+		t.Errorf("TryLock with empty key error = %v, want ErrInvalidKey", err)
+	}
+
+	if _, err := client.NewMutex("lock:a", WithLockTTL(0)).TryLock(ctx); err != ErrInvalidTTL {
+		t.Errorf("TryLock with zero TTL error = %v, want ErrInvalidTTL", err)
+	}
+}
+
+// TestMutex_ClosedClient tests locking on a closed client
+func TestMutex_ClosedClient(t *testing.T) {
+	client := &Client{config: DefaultConfig(), closed: true}
+
+	_, err := client.NewMutex("lock:a").Acquire(context.Background())
+	if !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Acquire() on closed client error = %v, want ErrClientClosed", err)
+	}
+}
+
+// TestMutex_Unreachable tests that connection errors are returned instead of retried
+func TestMutex_Unreachable(t *testing.T) {
+	client, err := NewWithOptions(
+		WithAddr("127.0.0.1:1"),
+		WithMaxRetries(0),
+		WithDialTimeout(100*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("NewWithOptions() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_, err = client.NewMutex("lock:a").Acquire(ctx)
+	if err == nil || errors.Is(err, ErrLockNotAcquired) || ctx.Err() != nil {
+		t.Errorf("Acquire() error = %v, want connection error", err)
+	}
+}
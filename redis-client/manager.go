@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
 // DBManager manages multiple Redis database connections as a singleton
@@ -12,8 +14,14 @@ type DBManager struct {
 	config        *Config
 	clients       map[int]*Client // map of DB number to Client
 	databaseNames map[string]int  // map of name to DB number
+	pools         map[int]*dbPool // map of DB number to its pool allocation
+	evicted       map[int]*Client // clients whose pools were evicted, reopened on their next dial
 	mu            sync.RWMutex
 	closed        bool
+
+	janitorStop chan struct{} // stops idle eviction, nil if disabled
+	janitorDone chan struct{}
+	janitorOnce sync.Once
 }
 
 var (
@@ -40,9 +48,17 @@ func NewDBManager(config *Config) (*DBManager, error) {
 		config:        config,
 		clients:       make(map[int]*Client),
 		databaseNames: databaseNames,
+		pools:         make(map[int]*dbPool),
+		evicted:       make(map[int]*Client),
 		closed:        false,
 	}
 
+	if config.DBIdleTimeout > 0 {
+		manager.janitorStop = make(chan struct{})
+		manager.janitorDone = make(chan struct{})
+		go manager.runJanitor(manager.janitorStop, manager.janitorDone)
+	}
+
 	return manager, nil
 }
 
@@ -106,6 +122,12 @@ func InitGlobalManagerWithOptions(opts ...Option) error {
 // Accepts either int (database number) or string (database name from config)
 // Creates a new connection if it doesn't exist
 //
+// The pool is sized from DatabasePoolSizes or PoolSize, capped by what is
+// left of MaxTotalConns. If DBIdleTimeout is set the pool may later be
+// closed while idle. The returned client stays usable: its next command
+// reopens the pool within the budget, and fails with ErrConnBudgetExhausted
+// if the room cannot be made.
+//
 // Examples:
 //   manager.DB(0)        // By number
 //   manager.DB("cache")  // By name (if configured)
@@ -126,15 +148,30 @@ func (m *DBManager) DB(identifier DBIdentifier) (*Client, error) {
 	if client, exists := m.clients[dbNum]; exists {
 		return client, nil
 	}
+	if client, exists := m.evicted[dbNum]; exists {
+		return client, nil
+	}
+
+	size, err := m.reservePool(dbNum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for DB %v: %w", identifier, err)
+	}
 
 	// Create new client for this database
 	config := m.cloneConfigWithDB(dbNum)
+	config.PoolSize = size
+	config.MinIdleConns = min(config.MinIdleConns, size)
+	config.MaxIdleConns = min(config.MaxIdleConns, size)
 	client, err := New(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create client for DB %v: %w", identifier, err)
 	}
 
+	usage := newUsageHook()
+	client.client.AddHook(usage)
+
 	m.clients[dbNum] = client
+	m.pools[dbNum] = &dbPool{size: size, usage: usage}
 	return client, nil
 }
 
@@ -170,6 +207,8 @@ func (m *DBManager) MustDB(identifier DBIdentifier) *Client {
 
 // Close closes all database connections
 func (m *DBManager) Close() error {
+	m.stopJanitor()
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -183,8 +222,15 @@ func (m *DBManager) Close() error {
 			errs = append(errs, fmt.Errorf("DB %d: %w", dbNum, err))
 		}
 	}
+	for dbNum, client := range m.evicted {
+		if err := client.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("DB %d: %w", dbNum, err))
+		}
+	}
 
 	m.clients = make(map[int]*Client)
+	m.pools = make(map[int]*dbPool)
+	m.evicted = make(map[int]*Client)
 	m.closed = true
 
 	if len(errs) > 0 {
@@ -299,11 +345,7 @@ func (dc *DBClient) Get(ctx context.Context, key string) (string, error) {
 
 // Set sets the value of a key with optional TTL
 func (dc *DBClient) Set(ctx context.Context, key string, value interface{}, ttl ...time.Duration) error {
-	var duration time.Duration
-	if len(ttl) > 0 {
-		duration = ttl[0]
-	}
-	return dc.client.Set(ctx, key, value, duration)
+	return dc.client.Set(ctx, key, value, ttl...)
 }
 
 // Del deletes one or more keys
@@ -365,3 +407,632 @@ func (dc *DBClient) Incr(ctx context.Context, key string) (int64, error) {
 func (dc *DBClient) Decr(ctx context.Context, key string) (int64, error) {
 	return dc.client.Decr(ctx, key)
 }
+
+// XAdd appends an entry to a stream
+func (dc *DBClient) XAdd(ctx context.Context, stream string, values map[string]interface{}) (string, error) {
+	return dc.client.XAdd(ctx, stream, values)
+}
+
+// XRange returns the entries of a stream between two IDs
+func (dc *DBClient) XRange(ctx context.Context, stream, start, stop string) ([]redis.XMessage, error) {
+	return dc.client.XRange(ctx, stream, start, stop)
+}
+
+// XLen returns the number of entries in a stream
+func (dc *DBClient) XLen(ctx context.Context, stream string) (int64, error) {
+	return dc.client.XLen(ctx, stream)
+}
+
+// XTrimMaxLen trims a stream to at most maxLen entries
+func (dc *DBClient) XTrimMaxLen(ctx context.Context, stream string, maxLen int64, approx bool) (int64, error) {
+	return dc.client.XTrimMaxLen(ctx, stream, maxLen, approx)
+}
+
+// NewStreamConsumer creates a stream consumer on this database
+func (dc *DBClient) NewStreamConsumer(stream, group string, handler StreamHandler, opts ...StreamConsumerOption) (*StreamConsumer, error) {
+	return dc.client.NewStreamConsumer(stream, group, handler, opts...)
+}
+
+// ScanIter iterates over keys with SCAN
+func (dc *DBClient) ScanIter(ctx context.Context, opts ...ScanOption) *ScanIterator[string] {
+	return dc.client.ScanIter(ctx, opts...)
+}
+
+// DeleteByPattern deletes every key matching pattern
+func (dc *DBClient) DeleteByPattern(ctx context.Context, pattern string, opts ...ScanOption) (int64, error) {
+	return dc.client.DeleteByPattern(ctx, pattern, opts...)
+}
+
+// ExpireByPattern sets a TTL on every key matching pattern
+func (dc *DBClient) ExpireByPattern(ctx context.Context, pattern string, ttl time.Duration, opts ...ScanOption) (int64, error) {
+	return dc.client.ExpireByPattern(ctx, pattern, ttl, opts...)
+}
+
+// NewQueue creates a job queue on this database
+func (dc *DBClient) NewQueue(name string, opts ...QueueOption) (*Queue, error) {
+	return dc.client.NewQueue(name, opts...)
+}
+
+// RegisterScripts registers and loads scripts on this database's client
+func (dc *DBClient) RegisterScripts(ctx context.Context, scripts ...*Script) error {
+	return dc.client.RegisterScripts(ctx, scripts...)
+}
+
+// RunScript executes a registered script by name
+func (dc *DBClient) RunScript(ctx context.Context, name string, keys []string, args ...interface{}) *ScriptResult {
+	return dc.client.RunScript(ctx, name, keys, args...)
+}
+
+// Stats returns connection pool statistics of this database's client
+func (dc *DBClient) Stats() PoolStats {
+	return dc.client.Stats()
+}
+
+// Ping checks if the connection is alive
+func (dc *DBClient) Ping(ctx context.Context) error {
+	return dc.client.Ping(ctx)
+}
+
+// SetNX sets the value of a key only if it does not exist
+func (dc *DBClient) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return dc.client.SetNX(ctx, key, value, ttl)
+}
+
+// SetEX sets the value and expiration of a key
+func (dc *DBClient) SetEX(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return dc.client.SetEX(ctx, key, value, ttl)
+}
+
+// GetSet sets a new value and returns the old value
+func (dc *DBClient) GetSet(ctx context.Context, key string, value interface{}) (string, error) {
+	return dc.client.GetSet(ctx, key, value)
+}
+
+// MGet retrieves values of multiple keys
+func (dc *DBClient) MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
+	return dc.client.MGet(ctx, keys...)
+}
+
+// MSet sets multiple key-value pairs
+func (dc *DBClient) MSet(ctx context.Context, values ...interface{}) error {
+	return dc.client.MSet(ctx, values...)
+}
+
+// IncrBy increments the integer value of a key by the given amount
+func (dc *DBClient) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	return dc.client.IncrBy(ctx, key, value)
+}
+
+// DecrBy decrements the integer value of a key by the given amount
+func (dc *DBClient) DecrBy(ctx context.Context, key string, value int64) (int64, error) {
+	return dc.client.DecrBy(ctx, key, value)
+}
+
+// Expire sets a key's time to live
+func (dc *DBClient) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return dc.client.Expire(ctx, key, ttl)
+}
+
+// TTL returns the time to live for a key
+func (dc *DBClient) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return dc.client.TTL(ctx, key)
+}
+
+// Persist removes the expiration from a key
+func (dc *DBClient) Persist(ctx context.Context, key string) (bool, error) {
+	return dc.client.Persist(ctx, key)
+}
+
+// Keys returns all keys matching a pattern
+func (dc *DBClient) Keys(ctx context.Context, pattern string) ([]string, error) {
+	return dc.client.Keys(ctx, pattern)
+}
+
+// HDel deletes hash fields
+func (dc *DBClient) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	return dc.client.HDel(ctx, key, fields...)
+}
+
+// HExists checks if a hash field exists
+func (dc *DBClient) HExists(ctx context.Context, key, field string) (bool, error) {
+	return dc.client.HExists(ctx, key, field)
+}
+
+// HIncrBy increments a hash field by the given amount
+func (dc *DBClient) HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error) {
+	return dc.client.HIncrBy(ctx, key, field, incr)
+}
+
+// HKeys gets all hash field names
+func (dc *DBClient) HKeys(ctx context.Context, key string) ([]string, error) {
+	return dc.client.HKeys(ctx, key)
+}
+
+// HLen gets the number of hash fields
+func (dc *DBClient) HLen(ctx context.Context, key string) (int64, error) {
+	return dc.client.HLen(ctx, key)
+}
+
+// LPop removes and returns the first list element
+func (dc *DBClient) LPop(ctx context.Context, key string) (string, error) {
+	return dc.client.LPop(ctx, key)
+}
+
+// RPop removes and returns the last list element
+func (dc *DBClient) RPop(ctx context.Context, key string) (string, error) {
+	return dc.client.RPop(ctx, key)
+}
+
+// LLen gets the length of a list
+func (dc *DBClient) LLen(ctx context.Context, key string) (int64, error) {
+	return dc.client.LLen(ctx, key)
+}
+
+// SIsMember checks if a member is in a set
+func (dc *DBClient) SIsMember(ctx context.Context, key string, member interface{}) (bool, error) {
+	return dc.client.SIsMember(ctx, key, member)
+}
+
+// SRem removes set members
+func (dc *DBClient) SRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	return dc.client.SRem(ctx, key, members...)
+}
+
+// SCard gets the number of set members
+func (dc *DBClient) SCard(ctx context.Context, key string) (int64, error) {
+	return dc.client.SCard(ctx, key)
+}
+
+// ZAdd adds sorted set members
+func (dc *DBClient) ZAdd(ctx context.Context, key string, members ...redis.Z) (int64, error) {
+	return dc.client.ZAdd(ctx, key, members...)
+}
+
+// ZRange returns sorted set members by index
+func (dc *DBClient) ZRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	return dc.client.ZRange(ctx, key, start, stop)
+}
+
+// ZRangeWithScores returns sorted set members with scores by index
+func (dc *DBClient) ZRangeWithScores(ctx context.Context, key string, start, stop int64) ([]redis.Z, error) {
+	return dc.client.ZRangeWithScores(ctx, key, start, stop)
+}
+
+// ZRem removes sorted set members
+func (dc *DBClient) ZRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	return dc.client.ZRem(ctx, key, members...)
+}
+
+// ZCard gets the number of sorted set members
+func (dc *DBClient) ZCard(ctx context.Context, key string) (int64, error) {
+	return dc.client.ZCard(ctx, key)
+}
+
+// ZScore gets the score of a sorted set member
+func (dc *DBClient) ZScore(ctx context.Context, key, member string) (float64, error) {
+	return dc.client.ZScore(ctx, key, member)
+}
+
+// Publish posts a message to a channel
+func (dc *DBClient) Publish(ctx context.Context, channel string, message interface{}) (int64, error) {
+	return dc.client.Publish(ctx, channel, message)
+}
+
+// GetDel gets the value of a key and deletes it. It returns ErrNil if the key
+// does not exist.
+func (dc *DBClient) GetDel(ctx context.Context, key string) (string, error) {
+	return dc.client.GetDel(ctx, key)
+}
+
+// GetEx gets the value of a key and sets its TTL. A ttl of 0 leaves the
+// expiration unchanged. It returns ErrNil if the key does not exist.
+func (dc *DBClient) GetEx(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return dc.client.GetEx(ctx, key, ttl)
+}
+
+// IncrByFloat increments the float value of a key by the given amount
+func (dc *DBClient) IncrByFloat(ctx context.Context, key string, value float64) (float64, error) {
+	return dc.client.IncrByFloat(ctx, key, value)
+}
+
+// Append appends a value to a string and returns its new length
+func (dc *DBClient) Append(ctx context.Context, key, value string) (int64, error) {
+	return dc.client.Append(ctx, key, value)
+}
+
+// StrLen returns the length of a string value
+func (dc *DBClient) StrLen(ctx context.Context, key string) (int64, error) {
+	return dc.client.StrLen(ctx, key)
+}
+
+// SetBit sets the bit at offset to value (0 or 1) and returns the previous bit
+func (dc *DBClient) SetBit(ctx context.Context, key string, offset int64, value int) (int64, error) {
+	return dc.client.SetBit(ctx, key, offset, value)
+}
+
+// GetBit returns the bit at offset
+func (dc *DBClient) GetBit(ctx context.Context, key string, offset int64) (int64, error) {
+	return dc.client.GetBit(ctx, key, offset)
+}
+
+// BitCount counts the set bits in a string. A nil bitCount counts the whole
+// string; otherwise Start and End select a byte range, or a bit range if Unit
+// is "BIT".
+func (dc *DBClient) BitCount(ctx context.Context, key string, bitCount *redis.BitCount) (int64, error) {
+	return dc.client.BitCount(ctx, key, bitCount)
+}
+
+// BitPos returns the position of the first bit set to bit (0 or 1), optionally
+// within a start and end byte
+func (dc *DBClient) BitPos(ctx context.Context, key string, bit int64, pos ...int64) (int64, error) {
+	return dc.client.BitPos(ctx, key, bit, pos...)
+}
+
+// BitOp stores the result of a bitwise operation of the given keys in destKey
+func (dc *DBClient) BitOp(ctx context.Context, op, destKey string, keys ...string) (int64, error) {
+	return dc.client.BitOp(ctx, op, destKey, keys...)
+}
+
+// Unlink deletes keys like Del, but reclaims memory in the background
+func (dc *DBClient) Unlink(ctx context.Context, keys ...string) (int64, error) {
+	return dc.client.Unlink(ctx, keys...)
+}
+
+// Type returns the type of the value stored at key, or "none" if the key does
+// not exist
+func (dc *DBClient) Type(ctx context.Context, key string) (string, error) {
+	return dc.client.Type(ctx, key)
+}
+
+// Rename renames a key, overwriting newKey if it exists
+func (dc *DBClient) Rename(ctx context.Context, key, newKey string) error {
+	return dc.client.Rename(ctx, key, newKey)
+}
+
+// ExpireAt sets a key to expire at the given time
+func (dc *DBClient) ExpireAt(ctx context.Context, key string, tm time.Time) (bool, error) {
+	return dc.client.ExpireAt(ctx, key, tm)
+}
+
+// PTTL returns the time to live for a key with millisecond precision
+func (dc *DBClient) PTTL(ctx context.Context, key string) (time.Duration, error) {
+	return dc.client.PTTL(ctx, key)
+}
+
+// HMGet returns the values of hash fields. Missing fields are nil.
+func (dc *DBClient) HMGet(ctx context.Context, key string, fields ...string) ([]interface{}, error) {
+	return dc.client.HMGet(ctx, key, fields...)
+}
+
+// HSetNX sets a hash field only if it does not exist
+func (dc *DBClient) HSetNX(ctx context.Context, key, field string, value interface{}) (bool, error) {
+	return dc.client.HSetNX(ctx, key, field, value)
+}
+
+// HVals returns all values in a hash
+func (dc *DBClient) HVals(ctx context.Context, key string) ([]string, error) {
+	return dc.client.HVals(ctx, key)
+}
+
+// HIncrByFloat increments the float value of a hash field by the given amount
+func (dc *DBClient) HIncrByFloat(ctx context.Context, key, field string, incr float64) (float64, error) {
+	return dc.client.HIncrByFloat(ctx, key, field, incr)
+}
+
+// HScan returns one page of field-value pairs and the next cursor. Iteration is
+// complete when the cursor is 0; HScanIter does the paging for you.
+func (dc *DBClient) HScan(ctx context.Context, key string, cursor uint64, match string, count int64) ([]string, uint64, error) {
+	return dc.client.HScan(ctx, key, cursor, match, count)
+}
+
+// BLPop removes and returns the first element of the first non-empty list, as
+// a key and value pair. It blocks for up to timeout, or until ctx is done if
+// timeout is 0, and returns ErrNil if no element arrived. Close interrupts it
+// with ErrClientClosed.
+func (dc *DBClient) BLPop(ctx context.Context, timeout time.Duration, keys ...string) ([]string, error) {
+	return dc.client.BLPop(ctx, timeout, keys...)
+}
+
+// BRPop removes and returns the last element of the first non-empty list, as
+// a key and value pair. It blocks like BLPop.
+func (dc *DBClient) BRPop(ctx context.Context, timeout time.Duration, keys ...string) ([]string, error) {
+	return dc.client.BRPop(ctx, timeout, keys...)
+}
+
+// LTrim trims a list to the elements from start to stop, inclusive
+func (dc *DBClient) LTrim(ctx context.Context, key string, start, stop int64) error {
+	return dc.client.LTrim(ctx, key, start, stop)
+}
+
+// LRem removes count occurrences of value: from the head if count is positive,
+func (dc *DBClient) LRem(ctx context.Context, key string, count int64, value interface{}) (int64, error) {
+	return dc.client.LRem(ctx, key, count, value)
+}
+
+// LIndex returns the element at index. Negative indexes count from the end. It
+func (dc *DBClient) LIndex(ctx context.Context, key string, index int64) (string, error) {
+	return dc.client.LIndex(ctx, key, index)
+}
+
+// LSet sets the element at index
+func (dc *DBClient) LSet(ctx context.Context, key string, index int64, value interface{}) error {
+	return dc.client.LSet(ctx, key, index, value)
+}
+
+// SInter returns the members present in all of the given sets
+func (dc *DBClient) SInter(ctx context.Context, keys ...string) ([]string, error) {
+	return dc.client.SInter(ctx, keys...)
+}
+
+// SUnion returns the members present in any of the given sets
+func (dc *DBClient) SUnion(ctx context.Context, keys ...string) ([]string, error) {
+	return dc.client.SUnion(ctx, keys...)
+}
+
+// SDiff returns the members of the first set that are in none of the others
+func (dc *DBClient) SDiff(ctx context.Context, keys ...string) ([]string, error) {
+	return dc.client.SDiff(ctx, keys...)
+}
+
+// SPop removes and returns a random member. It returns ErrNil if the set is
+// empty.
+func (dc *DBClient) SPop(ctx context.Context, key string) (string, error) {
+	return dc.client.SPop(ctx, key)
+}
+
+// SRandMember returns a random member without removing it. It returns ErrNil
+func (dc *DBClient) SRandMember(ctx context.Context, key string) (string, error) {
+	return dc.client.SRandMember(ctx, key)
+}
+
+// SMIsMember checks membership of several members at once
+func (dc *DBClient) SMIsMember(ctx context.Context, key string, members ...interface{}) ([]bool, error) {
+	return dc.client.SMIsMember(ctx, key, members...)
+}
+
+// ZIncrBy increments the score of a member, adding it if needed, and returns
+func (dc *DBClient) ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error) {
+	return dc.client.ZIncrBy(ctx, key, increment, member)
+}
+
+// ZRevRange returns members by rank from highest to lowest score
+func (dc *DBClient) ZRevRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	return dc.client.ZRevRange(ctx, key, start, stop)
+}
+
+// ZRevRangeWithScores returns members and scores by rank from highest to
+// lowest score
+func (dc *DBClient) ZRevRangeWithScores(ctx context.Context, key string, start, stop int64) ([]redis.Z, error) {
+	return dc.client.ZRevRangeWithScores(ctx, key, start, stop)
+}
+
+// ZRangeByScore returns members with scores between opt.Min and opt.Max, from
+// lowest to highest. Use "-inf" and "+inf" for open ends and a "(" prefix
+// for exclusive bounds.
+func (dc *DBClient) ZRangeByScore(ctx context.Context, key string, opt *redis.ZRangeBy) ([]string, error) {
+	return dc.client.ZRangeByScore(ctx, key, opt)
+}
+
+// ZRangeByScoreWithScores returns members and scores between opt.Min and
+// opt.Max, from lowest to highest
+func (dc *DBClient) ZRangeByScoreWithScores(ctx context.Context, key string, opt *redis.ZRangeBy) ([]redis.Z, error) {
+	return dc.client.ZRangeByScoreWithScores(ctx, key, opt)
+}
+
+// ZRevRangeByScore returns members with scores between opt.Max and opt.Min,
+func (dc *DBClient) ZRevRangeByScore(ctx context.Context, key string, opt *redis.ZRangeBy) ([]string, error) {
+	return dc.client.ZRevRangeByScore(ctx, key, opt)
+}
+
+// ZRank returns the rank of a member, lowest score first. It returns ErrNil if
+// the member does not exist.
+func (dc *DBClient) ZRank(ctx context.Context, key, member string) (int64, error) {
+	return dc.client.ZRank(ctx, key, member)
+}
+
+// ZRevRank returns the rank of a member, highest score first. It returns
+func (dc *DBClient) ZRevRank(ctx context.Context, key, member string) (int64, error) {
+	return dc.client.ZRevRank(ctx, key, member)
+}
+
+// ZCount counts members with scores between min and max
+func (dc *DBClient) ZCount(ctx context.Context, key, min, max string) (int64, error) {
+	return dc.client.ZCount(ctx, key, min, max)
+}
+
+// ZRemRangeByScore removes members with scores between min and max
+func (dc *DBClient) ZRemRangeByScore(ctx context.Context, key, min, max string) (int64, error) {
+	return dc.client.ZRemRangeByScore(ctx, key, min, max)
+}
+
+// ZRemRangeByRank removes members by rank from start to stop, inclusive
+func (dc *DBClient) ZRemRangeByRank(ctx context.Context, key string, start, stop int64) (int64, error) {
+	return dc.client.ZRemRangeByRank(ctx, key, start, stop)
+}
+
+// PFAdd adds elements to a HyperLogLog and returns 1 if its estimate changed
+func (dc *DBClient) PFAdd(ctx context.Context, key string, elements ...interface{}) (int64, error) {
+	return dc.client.PFAdd(ctx, key, elements...)
+}
+
+// PFCount returns the approximate number of distinct elements in the union of
+// the given HyperLogLogs
+func (dc *DBClient) PFCount(ctx context.Context, keys ...string) (int64, error) {
+	return dc.client.PFCount(ctx, keys...)
+}
+
+// PFMerge merges HyperLogLogs into destKey
+func (dc *DBClient) PFMerge(ctx context.Context, destKey string, keys ...string) error {
+	return dc.client.PFMerge(ctx, destKey, keys...)
+}
+
+// GeoAdd adds members with their longitude and latitude
+func (dc *DBClient) GeoAdd(ctx context.Context, key string, locations ...*redis.GeoLocation) (int64, error) {
+	return dc.client.GeoAdd(ctx, key, locations...)
+}
+
+// GeoPos returns the positions of members. Missing members are nil.
+func (dc *DBClient) GeoPos(ctx context.Context, key string, members ...string) ([]*redis.GeoPos, error) {
+	return dc.client.GeoPos(ctx, key, members...)
+}
+
+// GeoDist returns the distance between two members in unit ("m", "km", "mi"
+func (dc *DBClient) GeoDist(ctx context.Context, key, member1, member2, unit string) (float64, error) {
+	return dc.client.GeoDist(ctx, key, member1, member2, unit)
+}
+
+// GeoHash returns the geohash strings of members
+func (dc *DBClient) GeoHash(ctx context.Context, key string, members ...string) ([]string, error) {
+	return dc.client.GeoHash(ctx, key, members...)
+}
+
+// GeoSearch returns the members within a radius or box around a member or a
+// position
+func (dc *DBClient) GeoSearch(ctx context.Context, key string, query *redis.GeoSearchQuery) ([]string, error) {
+	return dc.client.GeoSearch(ctx, key, query)
+}
+
+// GeoSearchLocation is GeoSearch returning coordinates, distances and hashes
+func (dc *DBClient) GeoSearchLocation(ctx context.Context, key string, query *redis.GeoSearchLocationQuery) ([]redis.GeoLocation, error) {
+	return dc.client.GeoSearchLocation(ctx, key, query)
+}
+
+// Mode returns the deployment mode the client is connected to
+func (dc *DBClient) Mode() Mode {
+	return dc.client.Mode()
+}
+
+// KeyPrefix returns the prefix added to every key and channel
+func (dc *DBClient) KeyPrefix() string {
+	return dc.client.KeyPrefix()
+}
+
+// PrefixKey returns the key as stored in Redis
+func (dc *DBClient) PrefixKey(key string) string {
+	return dc.client.PrefixKey(key)
+}
+
+// StripPrefix removes the key prefix
+func (dc *DBClient) StripPrefix(key string) string {
+	return dc.client.StripPrefix(key)
+}
+
+// HScanIter iterates over the fields of a hash with HSCAN
+func (dc *DBClient) HScanIter(ctx context.Context, key string, opts ...ScanOption) *ScanIterator[FieldValue] {
+	return dc.client.HScanIter(ctx, key, opts...)
+}
+
+// SScanIter iterates over the members of a set with SSCAN
+func (dc *DBClient) SScanIter(ctx context.Context, key string, opts ...ScanOption) *ScanIterator[string] {
+	return dc.client.SScanIter(ctx, key, opts...)
+}
+
+// ZScanIter iterates over the members and scores of a sorted set with ZSCAN
+func (dc *DBClient) ZScanIter(ctx context.Context, key string, opts ...ScanOption) *ScanIterator[redis.Z] {
+	return dc.client.ZScanIter(ctx, key, opts...)
+}
+
+// Pipeline creates a new pipeline on this database
+func (dc *DBClient) Pipeline() redis.Pipeliner {
+	return dc.client.Pipeline()
+}
+
+// TxPipeline creates a new transaction pipeline on this database
+func (dc *DBClient) TxPipeline() redis.Pipeliner {
+	return dc.client.TxPipeline()
+}
+
+// Watch watches the given keys for changes
+func (dc *DBClient) Watch(ctx context.Context, fn func(*redis.Tx) error, keys ...string) error {
+	return dc.client.Watch(ctx, fn, keys...)
+}
+
+// Transaction runs an optimistic transaction with retries on this database
+func (dc *DBClient) Transaction(ctx context.Context, keys []string, fn func(*redis.Tx) error, opts ...TxOption) error {
+	return dc.client.Transaction(ctx, keys, fn, opts...)
+}
+
+// UpdateString runs a watched read-modify-write of a string value on this database
+func (dc *DBClient) UpdateString(ctx context.Context, key string, fn func(value string, exists bool) (string, error), opts ...TxOption) error {
+	return dc.client.UpdateString(ctx, key, fn, opts...)
+}
+
+// UpdateInt64 runs a watched read-modify-write of an integer value on this database
+func (dc *DBClient) UpdateInt64(ctx context.Context, key string, fn func(value int64) (int64, error), opts ...TxOption) error {
+	return dc.client.UpdateInt64(ctx, key, fn, opts...)
+}
+
+// Subscribe subscribes to the given channels
+func (dc *DBClient) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	return dc.client.Subscribe(ctx, channels...)
+}
+
+// PSubscribe subscribes to channels matching the given patterns
+func (dc *DBClient) PSubscribe(ctx context.Context, patterns ...string) *redis.PubSub {
+	return dc.client.PSubscribe(ctx, patterns...)
+}
+
+// NewMutex creates a distributed mutex for the given key on this database
+func (dc *DBClient) NewMutex(key string, opts ...LockOption) *Mutex {
+	return dc.client.NewMutex(key, opts...)
+}
+
+// NewLeaderElector creates a leader election candidate on this database
+func (dc *DBClient) NewLeaderElector(key string, opts ...ElectionOption) (*LeaderElector, error) {
+	return dc.client.NewLeaderElector(key, opts...)
+}
+
+// NewScheduler creates a distributed scheduler on this database
+func (dc *DBClient) NewScheduler(name string, opts ...SchedulerOption) (*Scheduler, error) {
+	return dc.client.NewScheduler(name, opts...)
+}
+
+// Script returns a registered script by name
+func (dc *DBClient) Script(name string) (*Script, bool) {
+	return dc.client.Script(name)
+}
+
+// LoadScripts loads all registered scripts with SCRIPT LOAD
+func (dc *DBClient) LoadScripts(ctx context.Context) error {
+	return dc.client.LoadScripts(ctx)
+}
+
+// XAddWithArgs appends an entry with explicit ID or trimming options
+func (dc *DBClient) XAddWithArgs(ctx context.Context, args *redis.XAddArgs) (string, error) {
+	return dc.client.XAddWithArgs(ctx, args)
+}
+
+// XRangeN returns at most count entries of a stream between two IDs
+func (dc *DBClient) XRangeN(ctx context.Context, stream, start, stop string, count int64) ([]redis.XMessage, error) {
+	return dc.client.XRangeN(ctx, stream, start, stop, count)
+}
+
+// XRevRange returns the entries of a stream between two IDs, newest first
+func (dc *DBClient) XRevRange(ctx context.Context, stream, start, stop string) ([]redis.XMessage, error) {
+	return dc.client.XRevRange(ctx, stream, start, stop)
+}
+
+// XDel removes entries from a stream
+func (dc *DBClient) XDel(ctx context.Context, stream string, ids ...string) (int64, error) {
+	return dc.client.XDel(ctx, stream, ids...)
+}
+
+// XTrimMinID removes stream entries with IDs lower than minID
+func (dc *DBClient) XTrimMinID(ctx context.Context, stream, minID string, approx bool) (int64, error) {
+	return dc.client.XTrimMinID(ctx, stream, minID, approx)
+}
+
+// XGroupCreate creates a consumer group, creating the stream if needed
+func (dc *DBClient) XGroupCreate(ctx context.Context, stream, group, start string) error {
+	return dc.client.XGroupCreate(ctx, stream, group, start)
+}
+
+// XAck acknowledges entries for a consumer group
+func (dc *DBClient) XAck(ctx context.Context, stream, group string, ids ...string) (int64, error) {
+	return dc.client.XAck(ctx, stream, group, ids...)
+}
+
+// XPending returns a summary of the pending entries of a consumer group
+func (dc *DBClient) XPending(ctx context.Context, stream, group string) (*redis.XPending, error) {
+	return dc.client.XPending(ctx, stream, group)
+}
@@ -0,0 +1,320 @@
+package redisclient
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ====================
+// Metrics
+// ====================
+
+// DefaultLatencyBuckets are the default histogram buckets in seconds
+var DefaultLatencyBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+// Metrics collects per-command latency histograms, error counts and pool
+// statistics, and exposes them in the Prometheus text format. One Metrics can
+// be shared by several clients; series are labelled by database number.
+type Metrics struct {
+	namespace string
+	buckets   []float64
+
+	mu     sync.RWMutex
+	series map[seriesKey]*commandSeries
+	pools  map[*Client]struct{}
+
+	// retired holds the pool counters of closed and replaced pools per
+	// database, so the exported counters never go down
+	retired map[int]PoolStats
+}
+
+// seriesKey identifies the series of one command on one database
+type seriesKey struct {
+	db      int
+	command string
+}
+
+// commandSeries holds the counters of one command
+type commandSeries struct {
+	buckets []atomic.Uint64
+	count   atomic.Uint64
+	sum     atomic.Int64 // nanoseconds
+	errors  atomic.Uint64
+}
+
+// CommandMetrics is a snapshot of the metrics of one command
+type CommandMetrics struct {
+	DB       int           `json:"db"`
+	Command  string        `json:"command"`
+	Count    uint64        `json:"count"`
+	Errors   uint64        `json:"errors"`
+	Duration time.Duration `json:"duration"` // total time spent
+}
+
+// MetricsOption configures Metrics
+type MetricsOption func(*Metrics)
+
+// WithMetricsNamespace sets the metric name prefix (default "redis")
+func WithMetricsNamespace(namespace string) MetricsOption {
+	return func(m *Metrics) {
+		m.namespace = namespace
+	}
+}
+
+// WithLatencyBuckets sets the histogram buckets in seconds
+func WithLatencyBuckets(buckets ...float64) MetricsOption {
+	return func(m *Metrics) {
+		m.buckets = append([]float64(nil), buckets...)
+		sort.Float64s(m.buckets)
+	}
+}
+
+// NewMetrics creates a metrics collector. Pass it to WithMetrics.
+func NewMetrics(opts ...MetricsOption) *Metrics {
+	m := &Metrics{
+		namespace: "redis",
+		buckets:   DefaultLatencyBuckets,
+		series:    make(map[seriesKey]*commandSeries),
+		pools:     make(map[*Client]struct{}),
+		retired:   make(map[int]PoolStats),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// observe records one command or pipeline
+func (m *Metrics) observe(db int, command string, d time.Duration, failed bool) {
+	key := seriesKey{db: db, command: command}
+
+	m.mu.RLock()
+	s, ok := m.series[key]
+	m.mu.RUnlock()
+
+	if !ok {
+		m.mu.Lock()
+		if s, ok = m.series[key]; !ok {
+			s = &commandSeries{buckets: make([]atomic.Uint64, len(m.buckets))}
+			m.series[key] = s
+		}
+		m.mu.Unlock()
+	}
+
+	seconds := d.Seconds()
+	for i, le := range m.buckets {
+		if seconds <= le {
+			s.buckets[i].Add(1)
+		}
+	}
+	s.count.Add(1)
+	s.sum.Add(int64(d))
+	if failed {
+		s.errors.Add(1)
+	}
+}
+
+// addPool includes a client's pool statistics in the output
+func (m *Metrics) addPool(c *Client) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pools[c] = struct{}{}
+}
+
+// removePool drops a closed client's pool statistics, keeping the final
+// counters of its pool
+func (m *Metrics) removePool(c *Client, final PoolStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.pools, c)
+	m.retire(c.config.DB, final)
+}
+
+// retirePool keeps the final counters of a pool that a client replaced
+func (m *Metrics) retirePool(db int, final PoolStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retire(db, final)
+}
+
+// retire adds the counters of a pool to the database's retired totals. Caller
+// must hold m.mu.
+func (m *Metrics) retire(db int, final PoolStats) {
+	total := m.retired[db]
+	total.Hits += final.Hits
+	total.Misses += final.Misses
+	total.Timeouts += final.Timeouts
+	m.retired[db] = total
+}
+
+// Commands returns a snapshot of all command series, sorted by database and command
+func (m *Metrics) Commands() []CommandMetrics {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]CommandMetrics, 0, len(m.series))
+	for key, s := range m.series {
+		result = append(result, CommandMetrics{
+			DB:       key.db,
+			Command:  key.command,
+			Count:    s.count.Load(),
+			Errors:   s.errors.Load(),
+			Duration: time.Duration(s.sum.Load()),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].DB != result[j].DB {
+			return result[i].DB < result[j].DB
+		}
+		return result[i].Command < result[j].Command
+	})
+	return result
+}
+
+// WritePrometheus writes all metrics in the Prometheus text exposition format
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	m.mu.RLock()
+	keys := make([]seriesKey, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	clients := make([]*Client, 0, len(m.pools))
+	for c := range m.pools {
+		clients = append(clients, c)
+	}
+	retired := make(map[int]PoolStats, len(m.retired))
+	for db, stats := range m.retired {
+		retired[db] = stats
+	}
+	m.mu.RUnlock()
+
+	// Pool stats are read without holding the lock, as Close removes clients
+	pools := make(map[int]PoolStats)
+	for _, c := range clients {
+		stats := c.Stats()
+		total := pools[c.config.DB]
+		total.Hits += stats.Hits
+		total.Misses += stats.Misses
+		total.Timeouts += stats.Timeouts
+		total.TotalConns += stats.TotalConns
+		total.IdleConns += stats.IdleConns
+		total.StaleConns += stats.StaleConns
+		pools[c.config.DB] = total
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].db != keys[j].db {
+			return keys[i].db < keys[j].db
+		}
+		return keys[i].command < keys[j].command
+	})
+
+	pw := &promWriter{w: w}
+	duration := m.namespace + "_command_duration_seconds"
+	pw.header(duration, "histogram", "Latency of Redis commands and pipelines.")
+	for _, key := range keys {
+		s := m.lookup(key)
+		labels := fmt.Sprintf(`db="%d",command="%s"`, key.db, key.command)
+		for i, le := range m.buckets {
+			pw.line(duration+"_bucket", labels+`,le="`+formatFloat(le)+`"`, strconv.FormatUint(s.buckets[i].Load(), 10))
+		}
+		count := strconv.FormatUint(s.count.Load(), 10)
+		pw.line(duration+"_bucket", labels+`,le="+Inf"`, count)
+		pw.line(duration+"_sum", labels, formatFloat(time.Duration(s.sum.Load()).Seconds()))
+		pw.line(duration+"_count", labels, count)
+	}
+
+	errors := m.namespace + "_command_errors_total"
+	pw.header(errors, "counter", "Redis commands and pipelines that failed, excluding nil replies.")
+	for _, key := range keys {
+		labels := fmt.Sprintf(`db="%d",command="%s"`, key.db, key.command)
+		pw.line(errors, labels, strconv.FormatUint(m.lookup(key).errors.Load(), 10))
+	}
+
+	dbs := make([]int, 0, len(pools))
+	for db := range pools {
+		dbs = append(dbs, db)
+	}
+	sort.Ints(dbs)
+
+	// Counters also cover databases whose clients are all closed
+	counterDBs := append([]int(nil), dbs...)
+	for db := range retired {
+		if _, ok := pools[db]; !ok {
+			counterDBs = append(counterDBs, db)
+		}
+	}
+	sort.Ints(counterDBs)
+
+	counters := []struct {
+		name, help string
+		value      func(PoolStats) uint32
+	}{
+		{"pool_hits_total", "Connections taken from the pool.", func(s PoolStats) uint32 { return s.Hits }},
+		{"pool_misses_total", "Connections dialed because the pool had none.", func(s PoolStats) uint32 { return s.Misses }},
+		{"pool_timeouts_total", "Waits for a connection that timed out.", func(s PoolStats) uint32 { return s.Timeouts }},
+	}
+	for _, counter := range counters {
+		name := m.namespace + "_" + counter.name
+		pw.header(name, "counter", counter.help)
+		for _, db := range counterDBs {
+			value := uint64(counter.value(pools[db])) + uint64(counter.value(retired[db]))
+			pw.line(name, fmt.Sprintf(`db="%d"`, db), strconv.FormatUint(value, 10))
+		}
+	}
+
+	conns := m.namespace + "_pool_connections"
+	pw.header(conns, "gauge", "Connections in the pool by state.")
+	for _, db := range dbs {
+		s := pools[db]
+		pw.line(conns, fmt.Sprintf(`db="%d",state="total"`, db), strconv.FormatUint(uint64(s.TotalConns), 10))
+		pw.line(conns, fmt.Sprintf(`db="%d",state="idle"`, db), strconv.FormatUint(uint64(s.IdleConns), 10))
+		pw.line(conns, fmt.Sprintf(`db="%d",state="stale"`, db), strconv.FormatUint(uint64(s.StaleConns), 10))
+	}
+
+	return pw.err
+}
+
+// ServeHTTP serves the metrics, so Metrics can be mounted at /metrics
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.WritePrometheus(w)
+}
+
+// lookup returns an existing series
+func (m *Metrics) lookup(key seriesKey) *commandSeries {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.series[key]
+}
+
+// promWriter writes exposition lines, keeping the first error
+type promWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *promWriter) header(name, kind, help string) {
+	p.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func (p *promWriter) line(name, labels, value string) {
+	p.printf("%s{%s} %s\n", name, labels, value)
+}
+
+func (p *promWriter) printf(format string, args ...interface{}) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+// formatFloat formats a float the way Prometheus clients do
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
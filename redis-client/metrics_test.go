@@ -0,0 +1,115 @@
+package redisclient
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMetrics_Observe tests histogram buckets, counts and errors
+func TestMetrics_Observe(t *testing.T) {
+	m := NewMetrics(WithLatencyBuckets(0.1, 0.01))
+
+	m.observe(0, "get", 5*time.Millisecond, false)
+	m.observe(0, "get", 50*time.Millisecond, true)
+	m.observe(0, "get", time.Second, false)
+	m.observe(2, "set", time.Millisecond, false)
+
+	commands := m.Commands()
+	if len(commands) != 2 {
+		t.Fatalf("Commands() returned %d series, want 2", len(commands))
+	}
+	get := commands[0]
+	if get.DB != 0 || get.Command != "get" || get.Count != 3 || get.Errors != 1 {
+		t.Errorf("get series = %+v", get)
+	}
+	if get.Duration != 1055*time.Millisecond {
+		t.Errorf("get duration = %v, want 1.055s", get.Duration)
+	}
+	if commands[1].DB != 2 || commands[1].Command != "set" {
+		t.Errorf("second series = %+v, want db 2 set", commands[1])
+	}
+
+	var out strings.Builder
+	if err := m.WritePrometheus(&out); err != nil {
+		t.Fatalf("WritePrometheus() error = %v", err)
+	}
+	for _, want := range []string{
+		"# TYPE redis_command_duration_seconds histogram\n",
+		`redis_command_duration_seconds_bucket{db="0",command="get",le="0.01"} 1` + "\n",
+		`redis_command_duration_seconds_bucket{db="0",command="get",le="0.1"} 2` + "\n",
+		`redis_command_duration_seconds_bucket{db="0",command="get",le="+Inf"} 3` + "\n",
+		`redis_command_duration_seconds_sum{db="0",command="get"} 1.055` + "\n",
+		`redis_command_duration_seconds_count{db="0",command="get"} 3` + "\n",
+		`redis_command_errors_total{db="0",command="get"} 1` + "\n",
+		`redis_command_errors_total{db="2",command="set"} 0` + "\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q\n%s", want, out.String())
+		}
+	}
+}
+
+// TestMetrics_Pools tests pool statistics of registered clients
+func TestMetrics_Pools(t *testing.T) {
+	m := NewMetrics(WithMetricsNamespace("cache"))
+
+	client, err := NewWithOptions(WithAddr("127.0.0.1:1"), WithMetrics(m), WithMaxRetries(0), WithDialTimeout(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewWithOptions() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE cache_pool_hits_total counter\n",
+		`cache_pool_misses_total{db="0"} 0` + "\n",
+		`cache_pool_connections{db="0",state="idle"} `,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("output missing %q\n%s", want, body)
+		}
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	client.Close()
+	var out strings.Builder
+	m.WritePrometheus(&out)
+	if strings.Contains(out.String(), `cache_pool_connections{db="0"`) {
+		t.Errorf("closed client still reported:\n%s", out.String())
+	}
+}
+
+// TestMetrics_RetiredPools tests that pool counters keep the totals of closed
+// and replaced pools
+func TestMetrics_RetiredPools(t *testing.T) {
+	m := NewMetrics(WithMetricsNamespace("cache"))
+
+	client := &Client{config: DefaultConfig()}
+	client.config.DB = 2
+	m.addPool(client)
+	m.retirePool(2, PoolStats{Hits: 2, Misses: 1, TotalConns: 4})
+	m.removePool(client, PoolStats{Hits: 3, Timeouts: 1})
+
+	var out strings.Builder
+	if err := m.WritePrometheus(&out); err != nil {
+		t.Fatalf("WritePrometheus() error = %v", err)
+	}
+	body := out.String()
+	for _, want := range []string{
+		`cache_pool_hits_total{db="2"} 5` + "\n",
+		`cache_pool_misses_total{db="2"} 1` + "\n",
+		`cache_pool_timeouts_total{db="2"} 1` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("output missing %q\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, `cache_pool_connections{db="2"`) {
+		t.Errorf("retired pool reported as connections:\n%s", body)
+	}
+}
@@ -0,0 +1,227 @@
+package redisclient
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ====================
+// Observability
+// ====================
+
+// tracerName is the instrumentation name reported with spans
+const tracerName = "github.com/isimtekin/go-packages/redis-client"
+
+// PoolStats holds connection pool statistics
+type PoolStats struct {
+	Hits       uint32 `json:"hits"`        // connections taken from the pool
+	Misses     uint32 `json:"misses"`      // connections dialed because the pool had none
+	Timeouts   uint32 `json:"timeouts"`    // waits for a connection that timed out
+	TotalConns uint32 `json:"total_conns"` // open connections
+	IdleConns  uint32 `json:"idle_conns"`  // idle connections
+	StaleConns uint32 `json:"stale_conns"` // connections removed from the pool
+}
+
+// Stats returns connection pool statistics. In cluster mode they are summed
+// over all nodes. A closed client returns zero stats.
+func (c *Client) Stats() PoolStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed || c.client == nil {
+		return PoolStats{}
+	}
+	return poolStatsOf(c.client)
+}
+
+// poolStatsOf returns the pool statistics of a go-redis client
+func poolStatsOf(client redis.UniversalClient) PoolStats {
+	s := client.PoolStats()
+	return PoolStats{
+		Hits:       s.Hits,
+		Misses:     s.Misses,
+		Timeouts:   s.Timeouts,
+		TotalConns: s.TotalConns,
+		IdleConns:  s.IdleConns,
+		StaleConns: s.StaleConns,
+	}
+}
+
+// observeHook records metrics, logs slow commands and creates spans
+type observeHook struct {
+	db       int
+	addr     string
+	metrics  *Metrics
+	slow     time.Duration
+	logger   *slog.Logger
+	tracer   trace.Tracer
+	baseAttr []attribute.KeyValue
+}
+
+// newObserveHook returns the instrumentation hook for the configuration, or
+// nil if nothing is enabled
+func newObserveHook(config *Config) *observeHook {
+	if config.Metrics == nil && config.SlowLogThreshold <= 0 && !config.Tracing {
+		return nil
+	}
+
+	h := &observeHook{
+		db:      config.DB,
+		metrics: config.Metrics,
+		slow:    config.SlowLogThreshold,
+		logger:  config.SlowLogger,
+	}
+
+	switch config.Mode() {
+	case ModeCluster:
+		h.addr = strings.Join(config.ClusterAddrs, ",")
+	case ModeSentinel:
+		h.addr = config.MasterName
+	default:
+		h.addr = config.Addr
+	}
+
+	if h.slow > 0 && h.logger == nil {
+		h.logger = slog.Default()
+	}
+
+	if config.Tracing {
+		provider := config.TracerProvider
+		if provider == nil {
+			provider = otel.GetTracerProvider()
+		}
+		h.tracer = provider.Tracer(tracerName)
+		h.baseAttr = []attribute.KeyValue{
+			attribute.String("db.system", "redis"),
+			attribute.Int("db.redis.database_index", config.DB),
+			attribute.String("server.address", h.addr),
+		}
+	}
+
+	return h
+}
+
+// DialHook implements redis.Hook
+func (h *observeHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements redis.Hook
+func (h *observeHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		name := cmd.Name()
+
+		var span trace.Span
+		if h.tracer != nil {
+			ctx, span = h.tracer.Start(ctx, "redis."+name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(h.baseAttr...),
+				trace.WithAttributes(attribute.String("db.operation.name", name)))
+		}
+
+		start := time.Now()
+		err := next(ctx, cmd)
+		elapsed := time.Since(start)
+
+		h.record(ctx, name, elapsed, err, span, []redis.Cmder{cmd})
+		return err
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook
+func (h *observeHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		var span trace.Span
+		if h.tracer != nil {
+			ctx, span = h.tracer.Start(ctx, "redis.pipeline",
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(h.baseAttr...),
+				trace.WithAttributes(
+					attribute.String("db.operation.name", "pipeline"),
+					attribute.Int("db.operation.batch.size", len(cmds)),
+				))
+		}
+
+		start := time.Now()
+		err := next(ctx, cmds)
+		elapsed := time.Since(start)
+
+		// A pipeline fails if any command failed
+		failure := err
+		if failure == nil {
+			for _, cmd := range cmds {
+				if cmdErr := cmd.Err(); cmdErr != nil && !IsNil(cmdErr) {
+					failure = cmdErr
+					break
+				}
+			}
+		}
+
+		h.record(ctx, "pipeline", elapsed, failure, span, cmds)
+		return err
+	}
+}
+
+// record updates metrics, the slow log and the span for a finished call
+func (h *observeHook) record(ctx context.Context, name string, elapsed time.Duration, err error, span trace.Span, cmds []redis.Cmder) {
+	failed := err != nil && !IsNil(err)
+
+	if h.metrics != nil {
+		h.metrics.observe(h.db, name, elapsed, failed)
+	}
+
+	if h.slow > 0 && elapsed >= h.slow {
+		attrs := []any{
+			slog.String("command", name),
+			slog.Duration("duration", elapsed),
+			slog.Int("db", h.db),
+		}
+		if len(cmds) == 1 {
+			if keys := commandKeys(cmds[0]); len(keys) > 0 {
+				attrs = append(attrs, slog.Any("keys", keys))
+			}
+		} else {
+			attrs = append(attrs, slog.Int("commands", len(cmds)))
+		}
+		if failed {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+		h.logger.WarnContext(ctx, "slow redis command", attrs...)
+	}
+
+	if span != nil {
+		if failed {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// commandKeys returns up to five key arguments of a command for logging.
+// Values are never logged.
+func commandKeys(cmd redis.Cmder) []string {
+	keyArgs, ok := keyCommands[cmd.Name()]
+	if !ok {
+		return nil
+	}
+
+	args := cmd.Args()
+	var keys []string
+	for _, i := range keyArgs(args) {
+		if len(keys) == 5 {
+			break
+		}
+		keys = append(keys, fmt.Sprint(args[i]))
+	}
+	return keys
+}
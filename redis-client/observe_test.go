@@ -0,0 +1,124 @@
+package redisclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordingProvider records the spans it starts
+type recordingProvider struct {
+	noop.TracerProvider
+	spans []*recordingSpan
+}
+
+func (p *recordingProvider) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return recordingTracer{provider: p}
+}
+
+type recordingTracer struct {
+	noop.Tracer
+	provider *recordingProvider
+}
+
+func (t recordingTracer) Start(ctx context.Context, name string, _ ...trace.SpanStartOption) (context.Context, trace.Span) {
+	span := &recordingSpan{name: name}
+	t.provider.spans = append(t.provider.spans, span)
+	return ctx, span
+}
+
+type recordingSpan struct {
+	noop.Span
+	name   string
+	status codes.Code
+	ended  bool
+}
+
+func (s *recordingSpan) SetStatus(code codes.Code, _ string) { s.status = code }
+func (s *recordingSpan) End(...trace.SpanEndOption)          { s.ended = true }
+
+// TestObserveHook tests metrics, slow log and spans for commands and pipelines
+func TestObserveHook(t *testing.T) {
+	ctx := context.Background()
+	var logs bytes.Buffer
+	provider := &recordingProvider{}
+
+	config := DefaultConfig()
+	config.Metrics = NewMetrics()
+	config.SlowLogThreshold = 10 * time.Millisecond
+	config.SlowLogger = slog.New(slog.NewTextHandler(&logs, nil))
+	config.Tracing = true
+	config.TracerProvider = provider
+	hook := newObserveHook(config)
+
+	process := hook.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+		if cmd.Name() == "get" {
+			time.Sleep(20 * time.Millisecond)
+			cmd.SetErr(redis.Nil)
+			return redis.Nil
+		}
+		cmd.SetErr(errors.New("ERR boom"))
+		return cmd.Err()
+	})
+	process(ctx, redis.NewStringCmd(ctx, "get", "user:1"))
+	process(ctx, redis.NewStatusCmd(ctx, "set", "user:2", "secret"))
+
+	pipeline := hook.ProcessPipelineHook(func(ctx context.Context, cmds []redis.Cmder) error {
+		return nil
+	})
+	pipeline(ctx, []redis.Cmder{redis.NewStringCmd(ctx, "get", "a"), redis.NewStringCmd(ctx, "get", "b")})
+
+	commands := config.Metrics.Commands()
+	if len(commands) != 3 {
+		t.Fatalf("Commands() = %+v, want get, pipeline and set", commands)
+	}
+	if commands[0].Command != "get" || commands[0].Errors != 0 {
+		t.Errorf("nil reply counted as error: %+v", commands[0])
+	}
+	if commands[2].Command != "set" || commands[2].Errors != 1 {
+		t.Errorf("set series = %+v, want 1 error", commands[2])
+	}
+
+	log := logs.String()
+	if !strings.Contains(log, "command=get") || !strings.Contains(log, "user:1") {
+		t.Errorf("slow log missing get: %s", log)
+	}
+	if strings.Contains(log, "secret") || strings.Contains(log, "command=set") {
+		t.Errorf("fast command or value logged: %s", log)
+	}
+
+	if len(provider.spans) != 3 {
+		t.Fatalf("started %d spans, want 3", len(provider.spans))
+	}
+	wantNames := []string{"redis.get", "redis.set", "redis.pipeline"}
+	wantStatus := []codes.Code{codes.Unset, codes.Error, codes.Unset}
+	for i, span := range provider.spans {
+		if span.name != wantNames[i] || span.status != wantStatus[i] || !span.ended {
+			t.Errorf("span %d = %+v, want %s with status %v", i, span, wantNames[i], wantStatus[i])
+		}
+	}
+}
+
+// TestNewObserveHook_Disabled tests that no hook is installed by default
+func TestNewObserveHook_Disabled(t *testing.T) {
+	if hook := newObserveHook(DefaultConfig()); hook != nil {
+		t.Error("newObserveHook() should return nil without metrics, slow log or tracing")
+	}
+}
+
+// TestClient_StatsClosed tests pool stats of a closed client
+func TestClient_StatsClosed(t *testing.T) {
+	client := &Client{config: DefaultConfig(), closed: true}
+	if stats := client.Stats(); stats != (PoolStats{}) {
+		t.Errorf("Stats() = %+v, want zero", stats)
+	}
+}
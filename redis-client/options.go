@@ -1,6 +1,11 @@
 package redisclient
 
-import "time"
+import (
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
 
 // Option is a functional option for configuring the Redis client
 type Option func(*Config)
@@ -124,3 +129,107 @@ func WithDatabaseName(name string, dbNum int) Option {
 		c.DatabaseNames[name] = dbNum
 	}
 }
+
+// WithDatabasePoolSize sets the connection pool size of a named database
+// opened by a DBManager. The name must also be mapped with WithDatabaseName.
+func WithDatabasePoolSize(name string, size int) Option {
+	return func(c *Config) {
+		if c.DatabasePoolSizes == nil {
+			c.DatabasePoolSizes = make(map[string]int)
+		}
+		c.DatabasePoolSizes[name] = size
+	}
+}
+
+// WithMaxTotalConns limits the connections a DBManager opens across all
+// databases. 0 means unlimited.
+func WithMaxTotalConns(n int) Option {
+	return func(c *Config) {
+		c.MaxTotalConns = n
+	}
+}
+
+// WithDBIdleTimeout makes a DBManager close the pools of databases that were
+// not used for d. They are reopened on the next DB call.
+func WithDBIdleTimeout(d time.Duration) Option {
+	return func(c *Config) {
+		c.DBIdleTimeout = d
+	}
+}
+
+// WithKeyPrefix prepends prefix to every key and pub/sub channel, e.g. "staging:"
+func WithKeyPrefix(prefix string) Option {
+	return func(c *Config) {
+		c.KeyPrefix = prefix
+	}
+}
+
+// WithValueCodec compresses and encrypts string and hash values with codec
+// and decodes them transparently when they are read
+func WithValueCodec(codec *ValueCodec) Option {
+	return func(c *Config) {
+		c.ValueCodec = codec
+	}
+}
+
+// WithMetrics records command latencies, errors and pool statistics in m.
+// One Metrics can be shared by several clients.
+func WithMetrics(m *Metrics) Option {
+	return func(c *Config) {
+		c.Metrics = m
+	}
+}
+
+// WithSlowLog logs commands that take at least threshold. A nil logger uses
+// slog.Default().
+func WithSlowLog(threshold time.Duration, logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.SlowLogThreshold = threshold
+		c.SlowLogger = logger
+	}
+}
+
+// WithTracing creates an OpenTelemetry span per command and pipeline. A nil
+// provider uses the global tracer provider.
+func WithTracing(provider trace.TracerProvider) Option {
+	return func(c *Config) {
+		c.Tracing = true
+		c.TracerProvider = provider
+	}
+}
+
+// WithClusterAddrs enables cluster mode with the given seed node addresses
+func WithClusterAddrs(addrs ...string) Option {
+	return func(c *Config) {
+		c.ClusterAddrs = addrs
+	}
+}
+
+// WithReadFromReplica routes read-only commands to replicas in cluster and sentinel mode
+func WithReadFromReplica(enabled bool) Option {
+	return func(c *Config) {
+		c.ReadFromReplica = enabled
+	}
+}
+
+// WithRouteByLatency routes read-only commands to the node with the lowest latency
+func WithRouteByLatency(enabled bool) Option {
+	return func(c *Config) {
+		c.RouteByLatency = enabled
+	}
+}
+
+// WithSentinel enables sentinel mode for the named master
+func WithSentinel(masterName string, sentinelAddrs ...string) Option {
+	return func(c *Config) {
+		c.MasterName = masterName
+		c.SentinelAddrs = sentinelAddrs
+	}
+}
+
+// WithSentinelPassword sets the password used to authenticate with sentinels
+func WithSentinelPassword(password string) Option {
+	return func(c *Config) {
+		c.SentinelPassword = password
+	}
+}
@@ -0,0 +1,369 @@
+package redisclient
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ====================
+// Key Prefix
+// ====================
+
+// keyArgs returns the indexes of the key arguments of a command
+type keyArgs func(args []interface{}) []int
+
+// keyCommands maps command names to their key arguments. Commands that are not
+// listed pass through unchanged.
+var keyCommands = map[string]keyArgs{}
+
+func init() {
+	for _, name := range []string{
+		// Strings and generic key commands
+		"get", "set", "setnx", "setex", "psetex", "getset", "getdel", "getex", "getrange", "setrange",
+		"append", "strlen", "incr", "incrby", "incrbyfloat", "decr", "decrby",
+		"expire", "pexpire", "expireat", "pexpireat", "expiretime", "pexpiretime",
+		"ttl", "pttl", "persist", "type", "dump", "restore",
+		// Hashes
+		"hset", "hsetnx", "hget", "hmset", "hmget", "hdel", "hexists", "hgetall", "hkeys", "hvals",
+		"hlen", "hincrby", "hincrbyfloat", "hscan", "hstrlen", "hrandfield",
+		// Lists
+		"lpush", "rpush", "lpushx", "rpushx", "lpop", "rpop", "llen", "lrange", "lindex", "lset",
+		"linsert", "lrem", "ltrim", "lpos",
+		// Sets
+		"sadd", "srem", "smembers", "sismember", "smismember", "scard", "spop", "srandmember", "sscan",
+		// Sorted sets
+		"zadd", "zrem", "zincrby", "zscore", "zmscore", "zrank", "zrevrank", "zcard", "zcount", "zlexcount",
+		"zrange", "zrangebyscore", "zrevrangebyscore", "zrangebylex", "zrevrangebylex", "zrevrange",
+		"zremrangebyrank", "zremrangebyscore", "zremrangebylex", "zpopmin", "zpopmax", "zrandmember", "zscan",
+		// Bitmaps, HyperLogLog and geo
+		"setbit", "getbit", "bitcount", "bitpos", "bitfield", "bitfield_ro", "pfadd",
+		"geoadd", "geopos", "geodist", "geohash", "georadius", "georadius_ro",
+		"georadiusbymember", "georadiusbymember_ro", "geosearch",
+		// Streams
+		"xadd", "xrange", "xrevrange", "xlen", "xdel", "xtrim", "xack", "xpending", "xclaim", "xautoclaim", "xsetid",
+		// Pub/sub
+		"publish", "spublish",
+	} {
+		keyCommands[name] = argRange(1, 1, 1)
+	}
+
+	for _, name := range []string{
+		"del", "unlink", "exists", "touch", "watch", "mget",
+		"sinter", "sunion", "sdiff", "sinterstore", "sunionstore", "sdiffstore", "pfcount", "pfmerge",
+	} {
+		keyCommands[name] = argRange(1, 0, 1)
+	}
+
+	for _, name := range []string{
+		"rename", "renamenx", "copy", "smove", "lmove", "blmove", "rpoplpush", "brpoplpush",
+		"zrangestore", "geosearchstore", "lcs",
+	} {
+		keyCommands[name] = argRange(1, 2, 1)
+	}
+
+	// The last argument is the timeout
+	for _, name := range []string{"blpop", "brpop", "bzpopmin", "bzpopmax"} {
+		keyCommands[name] = argRange(1, -1, 1)
+	}
+
+	keyCommands["mset"] = argRange(1, 0, 2)
+	keyCommands["msetnx"] = argRange(1, 0, 2)
+	keyCommands["bitop"] = argRange(2, 0, 1)
+	keyCommands["sort"] = sortKeys
+	keyCommands["sort_ro"] = sortKeys
+
+	// Commands with a key count before the keys
+	for _, name := range []string{"eval", "evalsha", "eval_ro", "evalsha_ro", "fcall", "fcall_ro", "blmpop", "bzmpop"} {
+		keyCommands[name] = numKeys(2)
+	}
+	for _, name := range []string{"zunion", "zinter", "zdiff", "zintercard", "sintercard", "lmpop", "zmpop"} {
+		keyCommands[name] = numKeys(1)
+	}
+	for _, name := range []string{"zunionstore", "zinterstore", "zdiffstore"} {
+		keyCommands[name] = func(args []interface{}) []int {
+			return append([]int{1}, numKeys(2)(args)...)
+		}
+	}
+
+	keyCommands["xread"] = streamKeys
+	keyCommands["xreadgroup"] = streamKeys
+
+	// Container commands whose subcommand takes a key
+	keyCommands["object"] = subcommandKey("encoding", "freq", "idletime", "refcount")
+	keyCommands["memory"] = subcommandKey("usage")
+	keyCommands["xgroup"] = subcommandKey("create", "destroy", "createconsumer", "delconsumer", "setid")
+	keyCommands["xinfo"] = subcommandKey("stream", "groups", "consumers")
+	keyCommands["pubsub"] = subcommandKey("numsub", "shardnumsub")
+}
+
+// argRange selects arguments from first to last with step. A last of 0 means
+// the final argument, and a negative last counts back from it.
+func argRange(first, last, step int) keyArgs {
+	return func(args []interface{}) []int {
+		end := last
+		if last <= 0 {
+			end = len(args) - 1 + last
+		}
+		var idx []int
+		for i := first; i <= end && i < len(args); i += step {
+			idx = append(idx, i)
+		}
+		return idx
+	}
+}
+
+// numKeys selects the keys that follow a key count at pos
+func numKeys(pos int) keyArgs {
+	return func(args []interface{}) []int {
+		if pos >= len(args) {
+			return nil
+		}
+		n, err := strconv.Atoi(fmt.Sprint(args[pos]))
+		if err != nil {
+			return nil
+		}
+		var idx []int
+		for i := pos + 1; i <= pos+n && i < len(args); i++ {
+			idx = append(idx, i)
+		}
+		return idx
+	}
+}
+
+// streamKeys selects the stream names after the STREAMS keyword of XREAD and
+// XREADGROUP, which are followed by as many IDs
+func streamKeys(args []interface{}) []int {
+	for i, arg := range args {
+		if s, ok := arg.(string); ok && strings.EqualFold(s, "streams") {
+			n := (len(args) - i - 1) / 2
+			var idx []int
+			for j := i + 1; j <= i+n; j++ {
+				idx = append(idx, j)
+			}
+			return idx
+		}
+	}
+	return nil
+}
+
+// sortKeys selects the sorted key and the STORE destination. BY and GET
+// patterns are not prefixed.
+func sortKeys(args []interface{}) []int {
+	idx := []int{1}
+	for i := 2; i < len(args)-1; i++ {
+		if s, ok := args[i].(string); ok && strings.EqualFold(s, "store") {
+			idx = append(idx, i+1)
+		}
+	}
+	return idx
+}
+
+// subcommandKey selects the arguments after the listed subcommands
+func subcommandKey(subcommands ...string) keyArgs {
+	return func(args []interface{}) []int {
+		if len(args) < 3 {
+			return nil
+		}
+		sub := strings.ToLower(fmt.Sprint(args[1]))
+		for _, s := range subcommands {
+			if sub == s {
+				if sub == "numsub" || sub == "shardnumsub" {
+					return argRange(2, 0, 1)(args)
+				}
+				return []int{2}
+			}
+		}
+		return nil
+	}
+}
+
+// rawKeysKey marks a context whose commands already carry full key names
+type rawKeysKey struct{}
+
+// withRawKeys returns a context whose commands the prefix hook leaves alone
+func withRawKeys(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rawKeysKey{}, true)
+}
+
+// prefixHook adds the key prefix to command arguments and removes it from
+// key names in replies
+type prefixHook struct {
+	prefix string
+}
+
+// DialHook implements redis.Hook
+func (h prefixHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements redis.Hook
+func (h prefixHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if ctx.Value(rawKeysKey{}) != nil {
+			return next(ctx, cmd)
+		}
+		h.apply(cmd)
+		err := next(ctx, cmd)
+		h.strip(cmd)
+		return err
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook
+func (h prefixHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if ctx.Value(rawKeysKey{}) != nil {
+			return next(ctx, cmds)
+		}
+		for _, cmd := range cmds {
+			h.apply(cmd)
+		}
+		err := next(ctx, cmds)
+		for _, cmd := range cmds {
+			h.strip(cmd)
+		}
+		return err
+	}
+}
+
+// apply prefixes the key arguments of cmd in place
+func (h prefixHook) apply(cmd redis.Cmder) {
+	args := cmd.Args()
+	name := cmd.Name()
+
+	switch name {
+	case "keys":
+		if len(args) > 1 {
+			args[1] = escapeGlob(h.prefix) + fmt.Sprint(args[1])
+		}
+		return
+	case "scan":
+		for i := 2; i < len(args)-1; i++ {
+			if s, ok := args[i].(string); ok && strings.EqualFold(s, "match") {
+				args[i+1] = escapeGlob(h.prefix) + fmt.Sprint(args[i+1])
+			}
+		}
+		return
+	case "pubsub":
+		if len(args) > 2 && strings.EqualFold(fmt.Sprint(args[1]), "channels") {
+			args[2] = escapeGlob(h.prefix) + fmt.Sprint(args[2])
+			return
+		}
+	}
+
+	keys, ok := keyCommands[name]
+	if !ok {
+		return
+	}
+	for _, i := range keys(args) {
+		switch v := args[i].(type) {
+		case string:
+			args[i] = h.prefix + v
+		case []byte:
+			args[i] = append([]byte(h.prefix), v...)
+		default:
+			args[i] = h.prefix + fmt.Sprint(v)
+		}
+	}
+}
+
+// strip removes the prefix from key names in the reply of cmd
+func (h prefixHook) strip(cmd redis.Cmder) {
+	if cmd.Err() != nil {
+		return
+	}
+
+	switch c := cmd.(type) {
+	case *redis.StringSliceCmd:
+		switch cmd.Name() {
+		case "keys", "pubsub":
+			c.SetVal(h.stripAll(c.Val()))
+		case "blpop", "brpop":
+			if val := c.Val(); len(val) == 2 {
+				c.SetVal([]string{h.stripOne(val[0]), val[1]})
+			}
+		}
+	case *redis.ScanCmd:
+		if cmd.Name() == "scan" {
+			keys, cursor := c.Val()
+			c.SetVal(h.stripAll(keys), cursor)
+		}
+	case *redis.StringCmd:
+		if cmd.Name() == "randomkey" {
+			c.SetVal(h.stripOne(c.Val()))
+		}
+	case *redis.ZWithKeyCmd:
+		if val := c.Val(); val != nil {
+			val.Key = h.stripOne(val.Key)
+			c.SetVal(val)
+		}
+	case *redis.KeyValuesCmd:
+		key, values := c.Val()
+		c.SetVal(h.stripOne(key), values)
+	case *redis.ZSliceWithKeyCmd:
+		key, values := c.Val()
+		c.SetVal(h.stripOne(key), values)
+	case *redis.XStreamSliceCmd:
+		streams := c.Val()
+		for i := range streams {
+			streams[i].Stream = h.stripOne(streams[i].Stream)
+		}
+		c.SetVal(streams)
+	}
+}
+
+// stripOne removes the prefix from a key
+func (h prefixHook) stripOne(key string) string {
+	return strings.TrimPrefix(key, h.prefix)
+}
+
+// stripAll removes the prefix from every key
+func (h prefixHook) stripAll(keys []string) []string {
+	for i, key := range keys {
+		keys[i] = h.stripOne(key)
+	}
+	return keys
+}
+
+// escapeGlob escapes glob metacharacters so s matches literally in a pattern
+func escapeGlob(s string) string {
+	if !strings.ContainsAny(s, `*?[]\`) {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// KeyPrefix returns the prefix added to every key and channel
+func (c *Client) KeyPrefix() string {
+	return c.config.KeyPrefix
+}
+
+// PrefixKey returns the key as stored in Redis, e.g. to build key names
+// inside Lua scripts or for tools that bypass the client
+func (c *Client) PrefixKey(key string) string {
+	return c.config.KeyPrefix + key
+}
+
+// StripPrefix removes the key prefix, e.g. from the Channel of pub/sub messages
+func (c *Client) StripPrefix(key string) string {
+	return strings.TrimPrefix(key, c.config.KeyPrefix)
+}
+
+// prefixPattern returns a glob pattern matching pattern under the key prefix
+func (c *Client) prefixPattern(pattern string) string {
+	if pattern == "" {
+		pattern = "*"
+	}
+	return escapeGlob(c.config.KeyPrefix) + pattern
+}
@@ -0,0 +1,152 @@
+package redisclient
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TestPrefixHook_Apply tests which command arguments get the key prefix
+func TestPrefixHook_Apply(t *testing.T) {
+	ctx := context.Background()
+	hook := prefixHook{prefix: "stg:"}
+
+	tests := []struct {
+		name string
+		args []interface{}
+		want []interface{}
+	}{
+		{"single key", []interface{}{"set", "a", "1"}, []interface{}{"set", "stg:a", "1"}},
+		{"all keys", []interface{}{"del", "a", "b"}, []interface{}{"del", "stg:a", "stg:b"}},
+		{"key value pairs", []interface{}{"mset", "a", "1", "b", "2"}, []interface{}{"mset", "stg:a", "1", "stg:b", "2"}},
+		{"timeout last", []interface{}{"blpop", "a", "b", 5}, []interface{}{"blpop", "stg:a", "stg:b", 5}},
+		{"two keys", []interface{}{"lmove", "a", "b", "left", "right"}, []interface{}{"lmove", "stg:a", "stg:b", "left", "right"}},
+		{"script keys", []interface{}{"evalsha", "abc", 1, "a", "arg"}, []interface{}{"evalsha", "abc", 1, "stg:a", "arg"}},
+		{"store with numkeys", []interface{}{"zunionstore", "d", 2, "a", "b"}, []interface{}{"zunionstore", "stg:d", 2, "stg:a", "stg:b"}},
+		{"streams", []interface{}{"xreadgroup", "group", "g", "c", "streams", "s1", "s2", ">", ">"},
+			[]interface{}{"xreadgroup", "group", "g", "c", "streams", "stg:s1", "stg:s2", ">", ">"}},
+		{"subcommand", []interface{}{"xgroup", "create", "s", "g", "$"}, []interface{}{"xgroup", "create", "stg:s", "g", "$"}},
+		{"subcommand without key", []interface{}{"object", "help"}, []interface{}{"object", "help"}},
+		{"scan match", []interface{}{"scan", 0, "match", "user:*", "count", 10}, []interface{}{"scan", 0, "match", "stg:user:*", "count", 10}},
+		{"hscan match is a field pattern", []interface{}{"hscan", "h", 0, "match", "f*"}, []interface{}{"hscan", "stg:h", 0, "match", "f*"}},
+		{"keys pattern", []interface{}{"keys", "*"}, []interface{}{"keys", "stg:*"}},
+		{"publish", []interface{}{"publish", "events", "hi"}, []interface{}{"publish", "stg:events", "hi"}},
+		{"unknown command", []interface{}{"client", "list"}, []interface{}{"client", "list"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := redis.NewCmd(ctx, tt.args...)
+			hook.apply(cmd)
+			if !reflect.DeepEqual(cmd.Args(), tt.want) {
+				t.Errorf("apply() = %v, want %v", cmd.Args(), tt.want)
+			}
+		})
+	}
+}
+
+// TestPrefixHook_Strip tests prefix removal from replies
+func TestPrefixHook_Strip(t *testing.T) {
+	ctx := context.Background()
+	hook := prefixHook{prefix: "stg:"}
+
+	keys := redis.NewStringSliceCmd(ctx, "keys", "stg:*")
+	keys.SetVal([]string{"stg:a", "stg:b"})
+	hook.strip(keys)
+	if !reflect.DeepEqual(keys.Val(), []string{"a", "b"}) {
+		t.Errorf("keys = %v", keys.Val())
+	}
+
+	scan := redis.NewScanCmd(ctx, nil, "scan", 0)
+	scan.SetVal([]string{"stg:a"}, 7)
+	hook.strip(scan)
+	if page, cursor := scan.Val(); page[0] != "a" || cursor != 7 {
+		t.Errorf("scan = %v, %d", page, cursor)
+	}
+
+	pop := redis.NewStringSliceCmd(ctx, "blpop", "stg:l", 0)
+	pop.SetVal([]string{"stg:l", "stg:value"})
+	hook.strip(pop)
+	if !reflect.DeepEqual(pop.Val(), []string{"l", "stg:value"}) {
+		t.Errorf("blpop = %v, values must not be stripped", pop.Val())
+	}
+
+	streams := redis.NewXStreamSliceCmd(ctx, "xread", "streams", "stg:s", "0")
+	streams.SetVal([]redis.XStream{{Stream: "stg:s"}})
+	hook.strip(streams)
+	if streams.Val()[0].Stream != "s" {
+		t.Errorf("xread stream = %q", streams.Val()[0].Stream)
+	}
+
+	get := redis.NewStringCmd(ctx, "get", "stg:a")
+	get.SetVal("stg:value")
+	hook.strip(get)
+	if get.Val() != "stg:value" {
+		t.Error("GET values must not be stripped")
+	}
+}
+
+// TestKeyPrefixHelpers tests prefix helpers and pattern escaping
+func TestKeyPrefixHelpers(t *testing.T) {
+	client := &Client{config: DefaultConfig()}
+	if client.PrefixKey("a") != "a" || client.prefixPattern("") != "*" {
+		t.Error("no prefix should leave keys unchanged")
+	}
+
+	client.config.KeyPrefix = "app[1]:"
+	if client.KeyPrefix() != "app[1]:" || client.PrefixKey("a") != "app[1]:a" || client.StripPrefix("app[1]:a") != "a" {
+		t.Errorf("PrefixKey/StripPrefix with prefix %q", client.KeyPrefix())
+	}
+	if got := client.prefixPattern("user:*"); got != `app\[1\]:user:*` {
+		t.Errorf("prefixPattern() = %q", got)
+	}
+
+	if withRawKeys(context.Background()).Value(rawKeysKey{}) == nil {
+		t.Error("withRawKeys() should mark the context")
+	}
+}
+
+// TestClient_KeyPrefixOption tests that the option installs the hook
+func TestClient_KeyPrefixOption(t *testing.T) {
+	client, err := NewWithOptions(WithAddr("127.0.0.1:1"), WithKeyPrefix("stg:"), WithMaxRetries(0), WithDialTimeout(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewWithOptions() error = %v", err)
+	}
+	defer client.Close()
+
+	if client.KeyPrefix() != "stg:" {
+		t.Errorf("KeyPrefix() = %q, want stg:", client.KeyPrefix())
+	}
+}
+
+// TestClient_SubscribeKeepsPrefix tests that Subscribe and PSubscribe return
+// the raw go-redis PubSub, subscribed to the prefixed names
+func TestClient_SubscribeKeepsPrefix(t *testing.T) {
+	client, err := NewWithOptions(WithAddr("127.0.0.1:1"), WithKeyPrefix("stg:"), WithMaxRetries(0), WithDialTimeout(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewWithOptions() error = %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	sub := client.Subscribe(ctx, "events")
+	defer sub.Close()
+	if got := sub.String(); got != "PubSub(stg:events)" {
+		t.Errorf("Subscribe() = %s, want PubSub(stg:events)", got)
+	}
+
+	psub := client.PSubscribe(ctx, "orders:*")
+	defer psub.Close()
+	if got := psub.String(); got != "PubSub(stg:orders:*)" {
+		t.Errorf("PSubscribe() = %s, want PubSub(stg:orders:*)", got)
+	}
+
+	// Received names keep the prefix until stripped
+	msg := &redis.Message{Channel: "stg:orders:1", Pattern: "stg:orders:*"}
+	if client.StripPrefix(msg.Channel) != "orders:1" || client.StripPrefix(msg.Pattern) != "orders:*" {
+		t.Errorf("StripPrefix(%q, %q)", msg.Channel, msg.Pattern)
+	}
+}
@@ -0,0 +1,744 @@
+package redisclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ====================
+// Job Queue
+// ====================
+
+var (
+	// enqueueScript stores a job and makes it ready or delayed. A unique key
+	// that is already taken returns the existing job ID instead.
+	enqueueScript = redis.NewScript(`
+if ARGV[4] ~= '' then
+	local existing = redis.call('HGET', KEYS[4], ARGV[4])
+	if existing then
+		return existing
+	end
+	redis.call('HSET', KEYS[4], ARGV[4], ARGV[1])
+end
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+if tonumber(ARGV[3]) > 0 then
+	redis.call('ZADD', KEYS[3], ARGV[3], ARGV[1])
+else
+	redis.call('LPUSH', KEYS[2], ARGV[1])
+end
+return ARGV[1]`)
+
+	// completeScript removes a finished job and releases its unique key
+	completeScript = redis.NewScript(`
+redis.call('LREM', KEYS[2], 1, ARGV[1])
+redis.call('HDEL', KEYS[1], ARGV[1])
+if ARGV[2] ~= '' and redis.call('HGET', KEYS[3], ARGV[2]) == ARGV[1] then
+	redis.call('HDEL', KEYS[3], ARGV[2])
+end
+return 1`)
+
+	// retryScript moves a failed job from processing to the delayed set
+	retryScript = redis.NewScript(`
+redis.call('LREM', KEYS[2], 1, ARGV[1])
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+redis.call('ZADD', KEYS[3], ARGV[3], ARGV[1])
+return 1`)
+
+	// buryScript moves a job that ran out of retries to the dead-letter list
+	buryScript = redis.NewScript(`
+redis.call('LREM', KEYS[2], 1, ARGV[1])
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+redis.call('LPUSH', KEYS[3], ARGV[1])
+if ARGV[3] ~= '' and redis.call('HGET', KEYS[4], ARGV[3]) == ARGV[1] then
+	redis.call('HDEL', KEYS[4], ARGV[3])
+end
+return 1`)
+
+	// promoteScript moves due delayed jobs to the ready list
+	promoteScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[1], id)
+	redis.call('LPUSH', KEYS[2], id)
+end
+return #ids`)
+
+	// reapScript returns the jobs of workers that stopped heartbeating (or of
+	// the worker named in ARGV[3]) to the front of the ready list. Processing
+	// list names are derived from ARGV[2] and share the queue's hash tag.
+	reapScript = redis.NewScript(`
+local workers
+if ARGV[3] ~= '' then
+	workers = {ARGV[3]}
+else
+	workers = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+end
+local moved = 0
+for _, worker in ipairs(workers) do
+	local list = ARGV[2] .. worker
+	while redis.call('LMOVE', list, KEYS[2], 'RIGHT', 'RIGHT') do
+		moved = moved + 1
+	end
+	redis.call('ZREM', KEYS[1], worker)
+end
+return moved`)
+
+	// requeueDeadScript moves dead jobs back to the ready list. ARGV holds
+	// pairs of job ID and the job data to store.
+	requeueDeadScript = redis.NewScript(`
+local moved = 0
+for i = 1, #ARGV, 2 do
+	if redis.call('LREM', KEYS[1], 1, ARGV[i]) > 0 then
+		redis.call('HSET', KEYS[3], ARGV[i], ARGV[i + 1])
+		redis.call('LPUSH', KEYS[2], ARGV[i])
+		moved = moved + 1
+	end
+end
+return moved`)
+)
+
+// Job is a unit of work in a Queue
+type Job struct {
+	ID         string          `json:"id"`
+	Payload    json.RawMessage `json:"payload"`
+	Attempts   int             `json:"attempts"`
+	MaxRetries int             `json:"max_retries"`
+	UniqueKey  string          `json:"unique_key,omitempty"`
+	LastError  string          `json:"last_error,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// Decode unmarshals the job payload into v
+func (j *Job) Decode(v interface{}) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// JobHandler processes a job. Returning an error retries the job with
+// backoff until MaxRetries, then moves it to the dead-letter list.
+type JobHandler func(ctx context.Context, job *Job) error
+
+// QueueOptions configures a Queue
+type QueueOptions struct {
+	Concurrency       int             // Jobs processed in parallel by Process
+	MaxRetries        int             // Default retries per job after the first attempt
+	BackoffBase       time.Duration   // Delay before the first retry, doubled for each retry
+	BackoffMax        time.Duration   // Upper bound on the retry delay
+	VisibilityTimeout time.Duration   // Jobs of a worker silent for this long are requeued
+	JobTimeout        time.Duration   // Per-job handler timeout, 0 means none
+	PollInterval      time.Duration   // How often delayed jobs are promoted and workers reaped
+	Block             time.Duration   // BLMOVE block time, also bounds shutdown latency
+	ShutdownTimeout   time.Duration   // How long in-flight jobs may run after shutdown starts
+	ErrorHandler      func(err error) // Called for background errors
+}
+
+// DefaultQueueOptions returns the default queue options
+func DefaultQueueOptions() QueueOptions {
+	return QueueOptions{
+		Concurrency:       1,
+		MaxRetries:        3,
+		BackoffBase:       time.Second,
+		BackoffMax:        time.Hour,
+		VisibilityTimeout: time.Minute,
+		PollInterval:      time.Second,
+		Block:             time.Second,
+		ShutdownTimeout:   30 * time.Second,
+	}
+}
+
+// QueueOption is a functional option for configuring a Queue
+type QueueOption func(*QueueOptions)
+
+// WithQueueConcurrency sets how many jobs Process runs in parallel
+func WithQueueConcurrency(n int) QueueOption {
+	return func(o *QueueOptions) {
+		o.Concurrency = n
+	}
+}
+
+// WithQueueMaxRetries sets the default number of retries per job
+func WithQueueMaxRetries(n int) QueueOption {
+	return func(o *QueueOptions) {
+		o.MaxRetries = n
+	}
+}
+
+// WithQueueBackoff sets the exponential retry backoff
+func WithQueueBackoff(base, max time.Duration) QueueOption {
+	return func(o *QueueOptions) {
+		o.BackoffBase = base
+		o.BackoffMax = max
+	}
+}
+
+// WithVisibilityTimeout sets how long a silent worker keeps its jobs
+func WithVisibilityTimeout(d time.Duration) QueueOption {
+	return func(o *QueueOptions) {
+		o.VisibilityTimeout = d
+	}
+}
+
+// WithJobTimeout sets a per-job handler timeout
+func WithJobTimeout(d time.Duration) QueueOption {
+	return func(o *QueueOptions) {
+		o.JobTimeout = d
+	}
+}
+
+// WithQueuePollInterval sets how often delayed jobs are promoted
+func WithQueuePollInterval(d time.Duration) QueueOption {
+	return func(o *QueueOptions) {
+		o.PollInterval = d
+	}
+}
+
+// WithQueueShutdownTimeout sets how long in-flight jobs may run after shutdown starts
+func WithQueueShutdownTimeout(d time.Duration) QueueOption {
+	return func(o *QueueOptions) {
+		o.ShutdownTimeout = d
+	}
+}
+
+// WithQueueErrorHandler sets the callback for background errors
+func WithQueueErrorHandler(fn func(err error)) QueueOption {
+	return func(o *QueueOptions) {
+		o.ErrorHandler = fn
+	}
+}
+
+// JobOption configures a single enqueued job
+type JobOption func(*Job, *time.Time)
+
+// WithDelay runs the job after d
+func WithDelay(d time.Duration) JobOption {
+	return func(j *Job, runAt *time.Time) {
+		*runAt = time.Now().Add(d)
+	}
+}
+
+// WithRunAt runs the job at t
+func WithRunAt(t time.Time) JobOption {
+	return func(j *Job, runAt *time.Time) {
+		*runAt = t
+	}
+}
+
+// WithJobMaxRetries overrides the queue's retry count for this job
+func WithJobMaxRetries(n int) JobOption {
+	return func(j *Job, runAt *time.Time) {
+		j.MaxRetries = n
+	}
+}
+
+// WithUniqueKey rejects the job while another job with the same key is queued,
+// delayed or running
+func WithUniqueKey(key string) JobOption {
+	return func(j *Job, runAt *time.Time) {
+		j.UniqueKey = key
+	}
+}
+
+// WithJobID sets the job ID instead of generating one
+func WithJobID(id string) JobOption {
+	return func(j *Job, runAt *time.Time) {
+		j.ID = id
+	}
+}
+
+// QueueStats holds the number of jobs in each state
+type QueueStats struct {
+	Pending    int64 `json:"pending"`
+	Delayed    int64 `json:"delayed"`
+	Processing int64 `json:"processing"`
+	Dead       int64 `json:"dead"`
+	Workers    int64 `json:"workers"`
+}
+
+// Queue is a reliable job queue. Workers move jobs atomically into their own
+// processing list with BLMOVE, so a crashed worker's jobs are requeued once its
+// heartbeat is older than VisibilityTimeout. Failed jobs are retried with
+// exponential backoff through a delayed set and end up in a dead-letter list.
+type Queue struct {
+	client *Client
+	name   string
+	opts   QueueOptions
+}
+
+// NewQueue creates a queue. All keys share the hash tag {name}, so a queue
+// lives on one cluster node.
+func (c *Client) NewQueue(name string, opts ...QueueOption) (*Queue, error) {
+	if name == "" {
+		return nil, ErrInvalidKey
+	}
+
+	options := DefaultQueueOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.Concurrency <= 0 {
+		return nil, fmt.Errorf("concurrency must be positive")
+	}
+	if options.MaxRetries < 0 {
+		return nil, fmt.Errorf("max retries must be non-negative")
+	}
+	if options.VisibilityTimeout <= 0 || options.PollInterval <= 0 || options.Block <= 0 {
+		return nil, fmt.Errorf("visibility timeout, poll interval and block must be positive")
+	}
+
+	return &Queue{client: c, name: name, opts: options}, nil
+}
+
+// Name returns the queue name
+func (q *Queue) Name() string {
+	return q.name
+}
+
+// Enqueue adds a job with a JSON-encoded payload. If a unique key is already
+// taken it returns the existing job's ID with ErrJobExists.
+func (q *Queue) Enqueue(ctx context.Context, payload interface{}, opts ...JobOption) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	job := &Job{
+		Payload:    data,
+		MaxRetries: q.opts.MaxRetries,
+		CreatedAt:  time.Now(),
+	}
+
+	var runAt time.Time
+	for _, opt := range opts {
+		opt(job, &runAt)
+	}
+
+	if job.ID == "" {
+		if job.ID, err = generateLockToken(); err != nil {
+			return nil, fmt.Errorf("failed to generate job id: %w", err)
+		}
+	}
+
+	encoded, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job: %w", err)
+	}
+
+	var runAtMs int64
+	if runAt.After(time.Now()) {
+		runAtMs = runAt.UnixMilli()
+	}
+
+	id, err := q.client.runScript(ctx, enqueueScript,
+		[]string{q.key("jobs"), q.key("pending"), q.key("delayed"), q.key("unique")},
+		job.ID, encoded, runAtMs, job.UniqueKey).Text()
+	if err != nil {
+		return nil, err
+	}
+
+	if id != job.ID {
+		job.ID = id
+		return job, ErrJobExists
+	}
+	return job, nil
+}
+
+// Stats returns the number of jobs in each state
+func (q *Queue) Stats(ctx context.Context) (QueueStats, error) {
+	var stats QueueStats
+
+	workers, err := q.client.ZRange(ctx, q.key("workers"), 0, -1)
+	if err != nil {
+		return stats, err
+	}
+
+	err = q.client.pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pending := pipe.LLen(ctx, q.key("pending"))
+		delayed := pipe.ZCard(ctx, q.key("delayed"))
+		dead := pipe.LLen(ctx, q.key("dead"))
+		processing := make([]*redis.IntCmd, len(workers))
+		for i, worker := range workers {
+			processing[i] = pipe.LLen(ctx, q.processingKey(worker))
+		}
+
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+
+		stats.Pending = pending.Val()
+		stats.Delayed = delayed.Val()
+		stats.Dead = dead.Val()
+		stats.Workers = int64(len(workers))
+		for _, cmd := range processing {
+			stats.Processing += cmd.Val()
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// DeadJobs returns up to limit jobs from the dead-letter list, newest first
+func (q *Queue) DeadJobs(ctx context.Context, limit int64) ([]*Job, error) {
+	ids, err := q.client.LRange(ctx, q.key("dead"), 0, limit-1)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	return q.loadJobs(ctx, ids)
+}
+
+// RequeueDead moves dead jobs back to the ready list with their attempts reset
+func (q *Queue) RequeueDead(ctx context.Context, ids ...string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	jobs, err := q.loadJobs(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	args := make([]interface{}, 0, len(jobs)*2)
+	for _, job := range jobs {
+		job.Attempts = 0
+		job.LastError = ""
+		encoded, err := json.Marshal(job)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode job: %w", err)
+		}
+		args = append(args, job.ID, encoded)
+	}
+	if len(args) == 0 {
+		return 0, nil
+	}
+
+	return q.client.runScript(ctx, requeueDeadScript,
+		[]string{q.key("dead"), q.key("pending"), q.key("jobs")}, args...).Int64()
+}
+
+// loadJobs reads jobs by ID, skipping missing or undecodable ones
+func (q *Queue) loadJobs(ctx context.Context, ids []string) ([]*Job, error) {
+	var values []interface{}
+	err := q.client.pipelined(ctx, func(pipe redis.Pipeliner) error {
+		cmd := pipe.HMGet(ctx, q.key("jobs"), ids...)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+		values = cmd.Val()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*Job, 0, len(values))
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		var job Job
+		if err := json.Unmarshal([]byte(s), &job); err == nil {
+			jobs = append(jobs, &job)
+		}
+	}
+	return jobs, nil
+}
+
+// Process runs handler for jobs with Concurrency goroutines until ctx is
+// cancelled. On shutdown it stops fetching, waits up to ShutdownTimeout for
+// running jobs and returns unfinished jobs to the ready list.
+func (q *Queue) Process(ctx context.Context, handler JobHandler) error {
+	if handler == nil {
+		return fmt.Errorf("job handler cannot be nil")
+	}
+
+	suffix, err := generateLockToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate worker id: %w", err)
+	}
+	worker := defaultConsumerName() + "-" + suffix[:8]
+
+	// Register before fetching so the processing list is never unowned
+	if err := q.heartbeat(ctx, worker); err != nil {
+		return fmt.Errorf("failed to register worker: %w", err)
+	}
+
+	// Jobs keep running after ctx is cancelled until the shutdown timeout
+	jobCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+
+	var workers sync.WaitGroup
+	for i := 0; i < q.opts.Concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			q.work(ctx, jobCtx, worker, handler)
+		}()
+	}
+
+	maintenanceDone := make(chan struct{})
+	go func() {
+		defer close(maintenanceDone)
+		q.maintain(ctx, worker)
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+
+	<-ctx.Done()
+	if q.opts.ShutdownTimeout > 0 {
+		timer := time.NewTimer(q.opts.ShutdownTimeout)
+		defer timer.Stop()
+		select {
+		case <-done:
+		case <-timer.C:
+			cancelJobs()
+			<-done
+		}
+	} else {
+		<-done
+	}
+	<-maintenanceDone
+
+	// Return jobs interrupted by the shutdown to the ready list
+	cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if _, err := q.reap(cleanupCtx, worker); err != nil {
+		q.reportError(fmt.Errorf("failed to requeue jobs of %s: %w", worker, err))
+	}
+
+	return nil
+}
+
+// work fetches and runs jobs one at a time until ctx is cancelled
+func (q *Queue) work(ctx, jobCtx context.Context, worker string, handler JobHandler) {
+	processing := q.processingKey(worker)
+
+	for ctx.Err() == nil {
+		id, err := q.client.blMove(ctx, q.key("pending"), processing, q.opts.Block)
+		if err != nil {
+			if IsNil(err) || ctx.Err() != nil {
+				continue
+			}
+			q.reportError(fmt.Errorf("failed to fetch job: %w", err))
+			if err == ErrClientClosed {
+				return
+			}
+			sleepContext(ctx, time.Second)
+			continue
+		}
+
+		q.run(jobCtx, processing, id, handler)
+	}
+}
+
+// run executes one job and records the outcome
+func (q *Queue) run(ctx context.Context, processing, id string, handler JobHandler) {
+	data, err := q.client.HGet(ctx, q.key("jobs"), id)
+	if err != nil {
+		if IsNil(err) {
+			// Deleted while queued
+			if _, err := q.client.LRem(ctx, processing, 1, id); err != nil {
+				q.reportError(fmt.Errorf("failed to drop deleted job %s: %w", id, err))
+			}
+			return
+		}
+		q.reportError(fmt.Errorf("failed to load job %s: %w", id, err))
+		q.requeue(ctx, processing, id)
+		return
+	}
+
+	var job Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		q.reportError(fmt.Errorf("failed to decode job %s: %w", id, err))
+		q.bury(ctx, processing, &job, id, err)
+		return
+	}
+	job.Attempts++
+
+	handlerCtx := ctx
+	if q.opts.JobTimeout > 0 {
+		var cancel context.CancelFunc
+		handlerCtx, cancel = context.WithTimeout(ctx, q.opts.JobTimeout)
+		defer cancel()
+	}
+
+	err = q.safeHandle(handlerCtx, &job, handler)
+	if err == nil {
+		if _, err := q.client.runScript(ctx, completeScript,
+			[]string{q.key("jobs"), processing, q.key("unique")}, job.ID, job.UniqueKey).Result(); err != nil {
+			q.reportError(fmt.Errorf("failed to complete job %s: %w", job.ID, err))
+		}
+		return
+	}
+
+	// Interrupted by shutdown: leave it in the processing list to be requeued
+	if ctx.Err() != nil {
+		return
+	}
+
+	job.LastError = err.Error()
+	if job.Attempts > job.MaxRetries {
+		q.bury(ctx, processing, &job, id, err)
+		return
+	}
+
+	encoded, _ := json.Marshal(job)
+	runAt := time.Now().Add(q.backoff(job.Attempts))
+	if _, err := q.client.runScript(ctx, retryScript,
+		[]string{q.key("jobs"), processing, q.key("delayed")}, job.ID, encoded, runAt.UnixMilli()).Result(); err != nil {
+		q.reportError(fmt.Errorf("failed to schedule retry of job %s: %w", job.ID, err))
+	}
+}
+
+// requeue moves a job that could not be loaded back to the end of the
+// pending list. If that fails too, it stays in the processing list and is
+// requeued with the worker's other jobs on shutdown or by another worker.
+func (q *Queue) requeue(ctx context.Context, processing, id string) {
+	if ctx.Err() != nil {
+		return
+	}
+	err := q.client.txPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LRem(ctx, processing, 1, id)
+		pipe.LPush(ctx, q.key("pending"), id)
+		return nil
+	})
+	if err != nil {
+		q.reportError(fmt.Errorf("failed to requeue job %s: %w", id, err))
+	}
+}
+
+// bury moves a job to the dead-letter list
+func (q *Queue) bury(ctx context.Context, processing string, job *Job, id string, cause error) {
+	job.ID = id
+	job.LastError = cause.Error()
+	encoded, _ := json.Marshal(job)
+
+	if _, err := q.client.runScript(ctx, buryScript,
+		[]string{q.key("jobs"), processing, q.key("dead"), q.key("unique")}, id, encoded, job.UniqueKey).Result(); err != nil {
+		q.reportError(fmt.Errorf("failed to dead-letter job %s: %w", id, err))
+	}
+}
+
+// safeHandle runs the handler, turning a panic into an error
+func (q *Queue) safeHandle(ctx context.Context, job *Job, handler JobHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job handler panic: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// maintain heartbeats, promotes due delayed jobs and requeues jobs of dead workers
+func (q *Queue) maintain(ctx context.Context, worker string) {
+	interval := q.opts.PollInterval
+	if beat := q.opts.VisibilityTimeout / 3; beat < interval {
+		interval = beat
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := q.heartbeat(ctx, worker); err != nil && ctx.Err() == nil {
+			q.reportError(fmt.Errorf("failed to heartbeat: %w", err))
+		}
+		if _, err := q.promote(ctx); err != nil && ctx.Err() == nil {
+			q.reportError(fmt.Errorf("failed to promote delayed jobs: %w", err))
+		}
+		if _, err := q.reap(ctx, ""); err != nil && ctx.Err() == nil {
+			q.reportError(fmt.Errorf("failed to requeue abandoned jobs: %w", err))
+		}
+	}
+}
+
+// heartbeat records that the worker is alive
+func (q *Queue) heartbeat(ctx context.Context, worker string) error {
+	_, err := q.client.ZAdd(ctx, q.key("workers"), redis.Z{Score: float64(time.Now().UnixMilli()), Member: worker})
+	return err
+}
+
+// promote moves due delayed jobs to the ready list
+func (q *Queue) promote(ctx context.Context) (int64, error) {
+	return q.client.runScript(ctx, promoteScript,
+		[]string{q.key("delayed"), q.key("pending")}, time.Now().UnixMilli(), 1000).Int64()
+}
+
+// reap requeues the jobs of workers silent for VisibilityTimeout, or of one worker
+func (q *Queue) reap(ctx context.Context, worker string) (int64, error) {
+	cutoff := time.Now().Add(-q.opts.VisibilityTimeout).UnixMilli()
+	return q.client.runScript(ctx, reapScript,
+		[]string{q.key("workers"), q.key("pending")}, cutoff, q.client.PrefixKey(q.processingKey("")), worker).Int64()
+}
+
+// backoff returns the retry delay after the given attempt, with jitter
+func (q *Queue) backoff(attempt int) time.Duration {
+	delay := q.opts.BackoffBase
+	for i := 1; i < attempt && delay < q.opts.BackoffMax; i++ {
+		delay *= 2
+	}
+	if q.opts.BackoffMax > 0 && delay > q.opts.BackoffMax {
+		delay = q.opts.BackoffMax
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	// Equal jitter: between half and the full delay
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// key returns a queue key; the hash tag keeps all of them in one slot
+func (q *Queue) key(suffix string) string {
+	return "queue:{" + q.name + "}:" + suffix
+}
+
+// processingKey returns the processing list of a worker
+func (q *Queue) processingKey(worker string) string {
+	return q.key("processing:") + worker
+}
+
+// reportError passes a background error to the error handler
+func (q *Queue) reportError(err error) {
+	if q.opts.ErrorHandler != nil {
+		q.opts.ErrorHandler(err)
+	}
+}
+
+// blMove runs BLMOVE from the right of src to the left of dst
+func (c *Client) blMove(ctx context.Context, src, dst string, timeout time.Duration) (string, error) {
+	var id string
+	err := c.blocking(func(client redis.UniversalClient) (err error) {
+		id, err = client.BLMove(ctx, src, dst, "RIGHT", "LEFT", timeout).Result()
+		return err
+	})
+	return id, err
+}
+
+// pipelined runs fn with a pipeline while holding the client lock
+func (c *Client) pipelined(ctx context.Context, fn func(redis.Pipeliner) error) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return ErrClientClosed
+	}
+
+	return fn(c.client.Pipeline())
+}
@@ -0,0 +1,175 @@
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TestQueue_ClosedClient tests queue operations on a closed client
+func TestQueue_ClosedClient(t *testing.T) {
+	client := &Client{config: DefaultConfig(), closed: true}
+	ctx := context.Background()
+
+	q, err := client.NewQueue("jobs")
+	if err != nil {
+		t.Fatalf("NewQueue() error = %v", err)
+	}
+
+	if _, err := q.Enqueue(ctx, map[string]string{"to": "a@example.com"}); err != ErrClientClosed {
+		t.Errorf("Enqueue() error = %v, want ErrClientClosed", err)
+	}
+	if _, err := q.Stats(ctx); err != ErrClientClosed {
+		t.Errorf("Stats() error = %v, want ErrClientClosed", err)
+	}
+	if _, err := q.RequeueDead(ctx, "id"); err != ErrClientClosed {
+		t.Errorf("RequeueDead() error = %v, want ErrClientClosed", err)
+	}
+	if err := q.Process(ctx, func(ctx context.Context, job *Job) error { return nil }); err == nil {
+		t.Error("Process() should fail on a closed client")
+	}
+}
+
+// TestQueue_InvalidOptions tests queue option validation
+func TestQueue_InvalidOptions(t *testing.T) {
+	client := &Client{config: DefaultConfig()}
+
+	tests := []struct {
+		name string
+		opts []QueueOption
+	}{
+		{"zero concurrency", []QueueOption{WithQueueConcurrency(0)}},
+		{"negative retries", []QueueOption{WithQueueMaxRetries(-1)}},
+		{"zero visibility timeout", []QueueOption{WithVisibilityTimeout(0)}},
+		{"zero poll interval", []QueueOption{WithQueuePollInterval(0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.NewQueue("jobs", tt.opts...); err == nil {
+				t.Error("NewQueue() should fail")
+			}
+		})
+	}
+
+	if _, err := client.NewQueue(""); err != ErrInvalidKey {
+		t.Errorf("NewQueue() error = %v, want ErrInvalidKey", err)
+	}
+
+	q, _ := client.NewQueue("jobs")
+	if err := q.Process(context.Background(), nil); err == nil {
+		t.Error("Process() should reject a nil handler")
+	}
+}
+
+// TestQueue_Backoff tests exponential backoff bounds
+func TestQueue_Backoff(t *testing.T) {
+	client := &Client{config: DefaultConfig()}
+	q, _ := client.NewQueue("jobs", WithQueueBackoff(100*time.Millisecond, time.Second))
+
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{10, time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			d := q.backoff(tt.attempt)
+			if d < tt.max/2 || d > tt.max {
+				t.Errorf("backoff(%d) = %v, want between %v and %v", tt.attempt, d, tt.max/2, tt.max)
+			}
+		}
+	}
+}
+
+// TestQueue_Keys tests that all queue keys share one hash slot
+func TestQueue_Keys(t *testing.T) {
+	client := &Client{config: DefaultConfig()}
+	q, _ := client.NewQueue("emails")
+
+	keys := []string{q.key("pending"), q.key("delayed"), q.key("dead"), q.key("jobs"), q.processingKey("worker-1")}
+	if !SameSlot(keys...) {
+		t.Errorf("queue keys should share a slot: %v", keys)
+	}
+	if q.processingKey("w") != "queue:{emails}:processing:w" {
+		t.Errorf("processingKey() = %q", q.processingKey("w"))
+	}
+
+	job := &Job{Payload: []byte(`{"to":"a@example.com"}`)}
+	var payload struct{ To string }
+	if err := job.Decode(&payload); err != nil || payload.To != "a@example.com" {
+		t.Errorf("Decode() = %+v, %v", payload, err)
+	}
+}
+
+// TestQueue_RunUnloadableJob tests that jobs that cannot be loaded leave the processing list
+func TestQueue_RunUnloadableJob(t *testing.T) {
+	lists := make(map[string][]string)
+	var hgetErr error
+	client := newReplyClient(t, func(cmd redis.Cmder) (bool, error) {
+		args := cmd.Args()
+		switch cmd.Name() {
+		case "hget":
+			return true, hgetErr
+		case "lrem":
+			key, value := args[1].(string), args[3].(string)
+			for i, v := range lists[key] {
+				if v == value {
+					lists[key] = append(lists[key][:i], lists[key][i+1:]...)
+					break
+				}
+			}
+			return true, nil
+		case "lpush":
+			key := args[1].(string)
+			lists[key] = append([]string{args[2].(string)}, lists[key]...)
+			return true, nil
+		case "multi", "exec":
+			return true, nil
+		}
+		return false, nil
+	})
+
+	var reported []error
+	q, err := client.NewQueue("emails", WithQueueErrorHandler(func(err error) { reported = append(reported, err) }))
+	if err != nil {
+		t.Fatalf("NewQueue() error = %v", err)
+	}
+	ctx := context.Background()
+	processing := q.processingKey("w")
+	handler := func(ctx context.Context, job *Job) error {
+		t.Error("handler should not run")
+		return nil
+	}
+
+	// Deleted while queued: dropped from the processing list
+	hgetErr = redis.Nil
+	lists[processing] = []string{"deleted"}
+	q.run(ctx, processing, "deleted", handler)
+	if len(lists[processing]) != 0 || len(reported) != 0 {
+		t.Errorf("after deleted job processing = %v, reported = %v", lists[processing], reported)
+	}
+
+	// Load error: reported and moved back to pending
+	hgetErr = errors.New("LOADING Redis is loading the dataset in memory")
+	lists[processing] = []string{"busy"}
+	q.run(ctx, processing, "busy", handler)
+	if len(lists[processing]) != 0 {
+		t.Errorf("processing = %v, want empty", lists[processing])
+	}
+	if pending := lists[q.key("pending")]; len(pending) != 1 || pending[0] != "busy" {
+		t.Errorf("pending = %v, want [busy]", pending)
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "failed to load job busy") {
+		t.Errorf("reported = %v", reported)
+	}
+}
@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+
+	redisclient "github.com/isimtekin/go-packages/redis-client"
+)
+
+// gcraScript implements the generic cell rate algorithm. The key stores the
+// theoretical arrival time (TAT) in microseconds of server time.
+var gcraScript = redisclient.NewScript("ratelimit_gcra", `
+local burst = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local period = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local emission = period / rate
+local increment = emission * cost
+local burst_offset = emission * burst
+
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if not tat or tat < now then
+	tat = now
+end
+
+local new_tat = tat + increment
+local diff = now - (new_tat - burst_offset)
+
+if diff < 0 then
+	local retry_after = -diff
+	if increment > burst_offset then
+		retry_after = -1
+	end
+	local remaining = math.floor((now - (tat - burst_offset)) / emission)
+	return {0, remaining, retry_after, tat - now}
+end
+
+local reset_after = new_tat - now
+if reset_after > 0 then
+	redis.call('SET', KEYS[1], string.format('%.0f', new_tat), 'PX', math.ceil(reset_after / 1000))
+end
+return {1, math.floor(diff / emission), 0, reset_after}`)
+
+// GCRA is a generic cell rate algorithm limiter. Requests are spaced evenly at
+// Rate per Period, and up to Burst requests may be made at once. It stores a
+// single timestamp per key.
+type GCRA struct {
+	client *redisclient.Client
+	prefix string
+}
+
+// NewGCRA creates a GCRA limiter. Keys are prefixed with "ratelimit:gcra:"
+// unless WithPrefix is given.
+func NewGCRA(client *redisclient.Client, opts ...Option) *GCRA {
+	o := applyOptions("ratelimit:gcra:", opts)
+	return &GCRA{client: client, prefix: o.prefix}
+}
+
+// Allow checks and records a single request for key
+func (g *GCRA) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	return g.AllowN(ctx, key, limit, 1)
+}
+
+// AllowN checks and records n requests for key. Denied requests are not recorded.
+func (g *GCRA) AllowN(ctx context.Context, key string, limit Limit, n int64) (*Result, error) {
+	if key == "" {
+		return nil, redisclient.ErrInvalidKey
+	}
+	limit, err := limit.validate()
+	if err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		return nil, fmt.Errorf("n must be positive")
+	}
+
+	reply, err := gcraScript.Run(ctx, g.client, []string{g.prefix + key},
+		limit.Burst, limit.Rate, limit.Period.Microseconds(), n).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	return newResult(reply, limit.Burst), nil
+}
+
+// Reset clears the limiter state for key
+func (g *GCRA) Reset(ctx context.Context, key string) error {
+	if key == "" {
+		return redisclient.ErrInvalidKey
+	}
+	_, err := g.client.Del(ctx, g.prefix+key)
+	return err
+}
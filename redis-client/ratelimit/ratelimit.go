@@ -0,0 +1,151 @@
+// Package ratelimit provides distributed rate limiters on top of redisclient.
+//
+// Every limiter runs as a single Lua script, so concurrent callers never race,
+// and uses the Redis server clock, so client clock skew does not matter.
+//   - GCRA: smooth rate limiting with bursts, one key per limited entity
+//   - SlidingWindow: exact limits over a rolling window, one entry per request
+//   - Semaphore: limits concurrent work with leases that expire if not released
+package ratelimit
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrInvalidLimit is returned when a limit has a non-positive rate or period
+	ErrInvalidLimit = errors.New("invalid rate limit")
+
+	// ErrLeaseLost is returned when refreshing or releasing a lease that expired
+	ErrLeaseLost = errors.New("semaphore lease lost")
+)
+
+// Limit is a number of requests allowed per period
+type Limit struct {
+	Rate   int64         // Requests per period
+	Period time.Duration // Length of the period
+	Burst  int64         // Requests allowed at once (GCRA only), defaults to Rate
+}
+
+// PerSecond returns a limit of rate requests per second
+func PerSecond(rate int64) Limit {
+	return Limit{Rate: rate, Period: time.Second}
+}
+
+// PerMinute returns a limit of rate requests per minute
+func PerMinute(rate int64) Limit {
+	return Limit{Rate: rate, Period: time.Minute}
+}
+
+// PerHour returns a limit of rate requests per hour
+func PerHour(rate int64) Limit {
+	return Limit{Rate: rate, Period: time.Hour}
+}
+
+// WithBurst returns a copy of the limit with the given burst
+func (l Limit) WithBurst(burst int64) Limit {
+	l.Burst = burst
+	return l
+}
+
+// validate checks the limit and fills in the default burst
+func (l Limit) validate() (Limit, error) {
+	if l.Rate <= 0 || l.Period <= 0 {
+		return l, ErrInvalidLimit
+	}
+	if l.Burst <= 0 {
+		l.Burst = l.Rate
+	}
+	return l, nil
+}
+
+// Result is the outcome of a rate limit check
+type Result struct {
+	Allowed    bool          // Whether the request may proceed
+	Limit      int64         // Maximum requests (GCRA burst, window rate, semaphore size)
+	Remaining  int64         // Requests still allowed right now
+	RetryAfter time.Duration // Wait before retrying a denied request, -1 if it can never succeed
+	ResetAfter time.Duration // Time until the limiter is back to full capacity
+	Reset      time.Time     // ResetAfter as an absolute time
+}
+
+// SetHeaders writes RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset
+// headers, and Retry-After for denied requests
+func (r *Result) SetHeaders(h http.Header) {
+	h.Set("RateLimit-Limit", strconv.FormatInt(r.Limit, 10))
+	h.Set("RateLimit-Remaining", strconv.FormatInt(r.Remaining, 10))
+	h.Set("RateLimit-Reset", strconv.FormatInt(ceilSeconds(r.ResetAfter), 10))
+	if !r.Allowed && r.RetryAfter >= 0 {
+		h.Set("Retry-After", strconv.FormatInt(ceilSeconds(r.RetryAfter), 10))
+	}
+}
+
+// Option configures a limiter
+type Option func(*options)
+
+type options struct {
+	prefix string
+}
+
+// WithPrefix sets the prefix of the limiter's Redis keys
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// applyOptions applies opts over the limiter's default prefix
+func applyOptions(prefix string, opts []Option) options {
+	o := options{prefix: prefix}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// newResult builds a result from a script reply of
+// {allowed, remaining, retry_after_us, reset_after_us}
+func newResult(reply []int64, limit int64) *Result {
+	r := &Result{
+		Allowed:    reply[0] == 1,
+		Limit:      limit,
+		Remaining:  reply[1],
+		RetryAfter: micros(reply[2]),
+		ResetAfter: micros(reply[3]),
+	}
+	if r.Remaining < 0 {
+		r.Remaining = 0
+	}
+	if reply[2] < 0 {
+		r.RetryAfter = -1
+	}
+	r.Reset = time.Now().Add(r.ResetAfter)
+	return r
+}
+
+// micros converts microseconds to a duration
+func micros(us int64) time.Duration {
+	if us <= 0 {
+		return 0
+	}
+	return time.Duration(us) * time.Microsecond
+}
+
+// ceilSeconds rounds a duration up to whole seconds
+func ceilSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
+
+// newToken returns a random identifier
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
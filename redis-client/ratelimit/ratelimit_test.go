@@ -0,0 +1,142 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	redisclient "github.com/isimtekin/go-packages/redis-client"
+)
+
+// closedClient returns a client that has been closed
+func closedClient(t *testing.T) *redisclient.Client {
+	client, err := redisclient.NewWithOptions(redisclient.WithAddr("127.0.0.1:1"))
+	if err != nil {
+		t.Fatalf("NewWithOptions() error = %v", err)
+	}
+	client.Close()
+	return client
+}
+
+// TestLimit tests limit helpers and validation
+func TestLimit(t *testing.T) {
+	l, err := PerMinute(60).validate()
+	if err != nil || l.Rate != 60 || l.Period != time.Minute || l.Burst != 60 {
+		t.Errorf("PerMinute(60).validate() = %+v, %v", l, err)
+	}
+
+	l, _ = PerSecond(10).WithBurst(3).validate()
+	if l.Burst != 3 {
+		t.Errorf("Burst = %d, want 3", l.Burst)
+	}
+
+	if PerHour(5).Period != time.Hour {
+		t.Error("PerHour() period should be one hour")
+	}
+
+	for _, invalid := range []Limit{{}, {Rate: 1}, {Period: time.Second}, {Rate: -1, Period: time.Second}} {
+		if _, err := invalid.validate(); err != ErrInvalidLimit {
+			t.Errorf("validate(%+v) error = %v, want ErrInvalidLimit", invalid, err)
+		}
+	}
+}
+
+// TestResult tests script reply conversion and HTTP headers
+func TestResult(t *testing.T) {
+	r := newResult([]int64{1, 4, 0, 1500000}, 5)
+	if !r.Allowed || r.Remaining != 4 || r.RetryAfter != 0 || r.ResetAfter != 1500*time.Millisecond {
+		t.Errorf("newResult() = %+v", r)
+	}
+
+	h := http.Header{}
+	r.SetHeaders(h)
+	if h.Get("RateLimit-Limit") != "5" || h.Get("RateLimit-Remaining") != "4" || h.Get("RateLimit-Reset") != "2" {
+		t.Errorf("SetHeaders() = %v", h)
+	}
+	if h.Get("Retry-After") != "" {
+		t.Error("Retry-After should only be set for denied requests")
+	}
+
+	denied := newResult([]int64{0, -1, 250000, 900000}, 5)
+	if denied.Allowed || denied.Remaining != 0 || denied.RetryAfter != 250*time.Millisecond {
+		t.Errorf("newResult() = %+v", denied)
+	}
+	h = http.Header{}
+	denied.SetHeaders(h)
+	if h.Get("Retry-After") != "1" {
+		t.Errorf("Retry-After = %q, want 1", h.Get("Retry-After"))
+	}
+
+	never := newResult([]int64{0, 0, -1, 0}, 5)
+	if never.RetryAfter != -1 {
+		t.Errorf("RetryAfter = %v, want -1", never.RetryAfter)
+	}
+	h = http.Header{}
+	never.SetHeaders(h)
+	if h.Get("Retry-After") != "" {
+		t.Error("Retry-After should not be set when the request can never succeed")
+	}
+}
+
+// TestLimiters_InvalidInput tests validation before Redis is contacted
+func TestLimiters_InvalidInput(t *testing.T) {
+	client := closedClient(t)
+	ctx := context.Background()
+
+	gcra := NewGCRA(client)
+	if _, err := gcra.Allow(ctx, "", PerSecond(1)); err != redisclient.ErrInvalidKey {
+		t.Errorf("GCRA.Allow() error = %v, want ErrInvalidKey", err)
+	}
+	if _, err := gcra.Allow(ctx, "user", Limit{}); err != ErrInvalidLimit {
+		t.Errorf("GCRA.Allow() error = %v, want ErrInvalidLimit", err)
+	}
+	if _, err := gcra.AllowN(ctx, "user", PerSecond(1), 0); err == nil {
+		t.Error("GCRA.AllowN() should reject n <= 0")
+	}
+
+	window := NewSlidingWindow(client, WithPrefix("rl:"))
+	if window.prefix != "rl:" {
+		t.Errorf("prefix = %q, want rl:", window.prefix)
+	}
+	if _, err := window.Allow(ctx, "", PerSecond(1)); err != redisclient.ErrInvalidKey {
+		t.Errorf("SlidingWindow.Allow() error = %v, want ErrInvalidKey", err)
+	}
+	if _, err := window.Allow(ctx, "user", Limit{Rate: 1}); err != ErrInvalidLimit {
+		t.Errorf("SlidingWindow.Allow() error = %v, want ErrInvalidLimit", err)
+	}
+
+	if _, err := NewSemaphore(client, "", 1, time.Second); err != redisclient.ErrInvalidKey {
+		t.Errorf("NewSemaphore() error = %v, want ErrInvalidKey", err)
+	}
+	if _, err := NewSemaphore(client, "jobs", 0, time.Second); err != ErrInvalidLimit {
+		t.Errorf("NewSemaphore() error = %v, want ErrInvalidLimit", err)
+	}
+	if _, err := NewSemaphore(client, "jobs", 1, 0); err == nil {
+		t.Error("NewSemaphore() should reject a zero lease")
+	}
+}
+
+// TestLimiters_ClosedClient tests limiters on a closed client
+func TestLimiters_ClosedClient(t *testing.T) {
+	client := closedClient(t)
+	ctx := context.Background()
+
+	if _, err := NewGCRA(client).Allow(ctx, "user", PerSecond(1)); err != redisclient.ErrClientClosed {
+		t.Errorf("GCRA.Allow() error = %v, want ErrClientClosed", err)
+	}
+	if _, err := NewSlidingWindow(client).Allow(ctx, "user", PerSecond(1)); err != redisclient.ErrClientClosed {
+		t.Errorf("SlidingWindow.Allow() error = %v, want ErrClientClosed", err)
+	}
+
+	sem, err := NewSemaphore(client, "jobs", 2, time.Second)
+	if err != nil {
+		t.Fatalf("NewSemaphore() error = %v", err)
+	}
+	if _, _, err := sem.TryAcquire(ctx); err != redisclient.ErrClientClosed {
+		t.Errorf("TryAcquire() error = %v, want ErrClientClosed", err)
+	}
+	if _, err := sem.Acquire(ctx, time.Millisecond); err != redisclient.ErrClientClosed {
+		t.Errorf("Acquire() error = %v, want ErrClientClosed", err)
+	}
+}
@@ -0,0 +1,182 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	redisclient "github.com/isimtekin/go-packages/redis-client"
+)
+
+var (
+	// acquireScript drops expired leases and adds one if a slot is free. Leases
+	// are sorted set members scored by their expiry in server microseconds.
+	acquireScript = redisclient.NewScript("ratelimit_sem_acquire", `
+local limit = tonumber(ARGV[1])
+local lease = tonumber(ARGV[2])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', string.format('%.0f', now))
+local count = redis.call('ZCARD', KEYS[1])
+
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], string.format('%.0f', now + lease), ARGV[3])
+	count = count + 1
+	allowed = 1
+end
+
+-- Keep the set as long as its longest lease
+local last = redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')
+redis.call('PEXPIRE', KEYS[1], math.ceil((tonumber(last[2]) - now) / 1000))
+
+local retry_after = 0
+if allowed == 0 then
+	local first = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+	retry_after = tonumber(first[2]) - now
+end
+
+return {allowed, limit - count, retry_after, tonumber(last[2]) - now}`)
+
+	// refreshScript extends a lease that has not expired
+	refreshScript = redisclient.NewScript("ratelimit_sem_refresh", `
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local expiry = tonumber(redis.call('ZSCORE', KEYS[1], ARGV[2]))
+if not expiry or expiry <= now then
+	redis.call('ZREM', KEYS[1], ARGV[2])
+	return 0
+end
+
+redis.call('ZADD', KEYS[1], string.format('%.0f', now + tonumber(ARGV[1])), ARGV[2])
+local last = redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')
+redis.call('PEXPIRE', KEYS[1], math.ceil((tonumber(last[2]) - now) / 1000))
+return 1`)
+
+	// heldScript counts unexpired leases
+	heldScript = redisclient.NewScript("ratelimit_sem_held", `
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+return redis.call('ZCOUNT', KEYS[1], '(' .. string.format('%.0f', now), '+inf')`)
+)
+
+// Semaphore limits how many holders may run at once across processes. Each
+// holder gets a lease that expires after the lease duration unless refreshed,
+// so a crashed holder frees its slot.
+type Semaphore struct {
+	client *redisclient.Client
+	key    string
+	limit  int64
+	lease  time.Duration
+}
+
+// Lease is a held semaphore slot
+type Lease struct {
+	sem *Semaphore
+	id  string
+}
+
+// NewSemaphore creates a semaphore with limit slots. The key is name prefixed
+// with "ratelimit:sem:" unless WithPrefix is given.
+func NewSemaphore(client *redisclient.Client, name string, limit int64, lease time.Duration, opts ...Option) (*Semaphore, error) {
+	if name == "" {
+		return nil, redisclient.ErrInvalidKey
+	}
+	if limit <= 0 {
+		return nil, ErrInvalidLimit
+	}
+	if lease <= 0 {
+		return nil, fmt.Errorf("lease duration must be positive")
+	}
+
+	o := applyOptions("ratelimit:sem:", opts)
+	return &Semaphore{client: client, key: o.prefix + name, limit: limit, lease: lease}, nil
+}
+
+// TryAcquire takes a slot if one is free. When the semaphore is full it returns
+// a nil lease, and the result's RetryAfter says when the next lease expires.
+func (s *Semaphore) TryAcquire(ctx context.Context) (*Lease, *Result, error) {
+	id, err := newToken()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate lease id: %w", err)
+	}
+
+	reply, err := acquireScript.Run(ctx, s.client, []string{s.key},
+		s.limit, s.lease.Microseconds(), id).Int64Slice()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := newResult(reply, s.limit)
+	if !result.Allowed {
+		return nil, result, nil
+	}
+	return &Lease{sem: s, id: id}, result, nil
+}
+
+// Acquire waits for a free slot, polling every retryInterval, until ctx is done
+func (s *Semaphore) Acquire(ctx context.Context, retryInterval time.Duration) (*Lease, error) {
+	if retryInterval <= 0 {
+		retryInterval = 100 * time.Millisecond
+	}
+
+	for {
+		lease, result, err := s.TryAcquire(ctx)
+		if err != nil || lease != nil {
+			return lease, err
+		}
+
+		wait := retryInterval
+		if result.RetryAfter > 0 && result.RetryAfter < wait {
+			wait = result.RetryAfter
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Held returns the number of unexpired leases
+func (s *Semaphore) Held(ctx context.Context) (int64, error) {
+	return heldScript.Run(ctx, s.client, []string{s.key}).Int64()
+}
+
+// ID returns the lease identifier
+func (l *Lease) ID() string {
+	return l.id
+}
+
+// Refresh extends the lease by the semaphore's lease duration. It returns
+// ErrLeaseLost if the lease already expired.
+func (l *Lease) Refresh(ctx context.Context) error {
+	ok, err := refreshScript.Run(ctx, l.sem.client, []string{l.sem.key},
+		l.sem.lease.Microseconds(), l.id).Int64()
+	if err != nil {
+		return err
+	}
+	if ok == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Release frees the slot. It returns ErrLeaseLost if the lease already expired
+// and was removed.
+func (l *Lease) Release(ctx context.Context) error {
+	removed, err := l.sem.client.ZRem(ctx, l.sem.key, l.id)
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+
+	redisclient "github.com/isimtekin/go-packages/redis-client"
+)
+
+// windowScript implements a sliding window log. Each allowed request is a
+// sorted set member scored by its server time in microseconds.
+var windowScript = redisclient.NewScript("ratelimit_window", `
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', string.format('%.0f', now - window))
+local count = redis.call('ZCARD', KEYS[1])
+
+local allowed = 0
+local retry_after = 0
+if count + cost <= limit then
+	for i = 1, cost do
+		redis.call('ZADD', KEYS[1], string.format('%.0f', now), ARGV[4] .. ':' .. i)
+	end
+	redis.call('PEXPIRE', KEYS[1], math.ceil(window / 1000))
+	count = count + cost
+	allowed = 1
+elseif cost > limit then
+	retry_after = -1
+else
+	-- Wait until enough of the oldest requests leave the window
+	local entry = redis.call('ZRANGE', KEYS[1], count + cost - limit - 1, count + cost - limit - 1, 'WITHSCORES')
+	retry_after = tonumber(entry[2]) + window - now
+end
+
+local reset_after = 0
+local newest = redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')
+if newest[2] then
+	reset_after = tonumber(newest[2]) + window - now
+end
+
+return {allowed, limit - count, retry_after, reset_after}`)
+
+// SlidingWindow limits requests to Rate per Period over a rolling window. It
+// is exact but stores one entry per allowed request, so it suits low rates.
+type SlidingWindow struct {
+	client *redisclient.Client
+	prefix string
+}
+
+// NewSlidingWindow creates a sliding window log limiter. Keys are prefixed
+// with "ratelimit:window:" unless WithPrefix is given.
+func NewSlidingWindow(client *redisclient.Client, opts ...Option) *SlidingWindow {
+	o := applyOptions("ratelimit:window:", opts)
+	return &SlidingWindow{client: client, prefix: o.prefix}
+}
+
+// Allow checks and records a single request for key
+func (w *SlidingWindow) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	return w.AllowN(ctx, key, limit, 1)
+}
+
+// AllowN checks and records n requests for key. Denied requests are not recorded.
+func (w *SlidingWindow) AllowN(ctx context.Context, key string, limit Limit, n int64) (*Result, error) {
+	if key == "" {
+		return nil, redisclient.ErrInvalidKey
+	}
+	if limit.Rate <= 0 || limit.Period <= 0 {
+		return nil, ErrInvalidLimit
+	}
+	if n <= 0 {
+		return nil, fmt.Errorf("n must be positive")
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate request id: %w", err)
+	}
+
+	reply, err := windowScript.Run(ctx, w.client, []string{w.prefix + key},
+		limit.Period.Microseconds(), limit.Rate, n, token).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	return newResult(reply, limit.Rate), nil
+}
+
+// Reset clears the limiter state for key
+func (w *SlidingWindow) Reset(ctx context.Context, key string) error {
+	if key == "" {
+		return redisclient.ErrInvalidKey
+	}
+	_, err := w.client.Del(ctx, w.prefix+key)
+	return err
+}
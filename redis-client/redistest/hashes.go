@@ -0,0 +1,297 @@
+package redistest
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ====================
+// Hash Operations
+// ====================
+
+// HSet sets fields in the hash stored at key. Values are field-value pairs,
+// a []string, []interface{} or a map, like go-redis.
+func (f *Fake) HSet(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	args, err := pairs(values)
+	if err != nil {
+		return 0, err
+	}
+	e, err := f.create(key, kindHash)
+	if err != nil {
+		return 0, err
+	}
+
+	var added int64
+	for i := 0; i < len(args); i += 2 {
+		if _, ok := e.hash[args[i]]; !ok {
+			added++
+		}
+		e.hash[args[i]] = args[i+1]
+	}
+	return added, nil
+}
+
+// HGet gets the value of a hash field
+func (f *Fake) HGet(ctx context.Context, key, field string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return "", err
+	}
+
+	e, err := f.lookupKind(key, kindHash)
+	if err != nil {
+		return "", err
+	}
+	if e == nil {
+		return "", redis.Nil
+	}
+	value, ok := e.hash[field]
+	if !ok {
+		return "", redis.Nil
+	}
+	return value, nil
+}
+
+// HGetAll gets all fields and values of a hash
+func (f *Fake) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return nil, err
+	}
+
+	e, err := f.lookupKind(key, kindHash)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string)
+	if e != nil {
+		for field, value := range e.hash {
+			result[field] = value
+		}
+	}
+	return result, nil
+}
+
+// HDel deletes hash fields
+func (f *Fake) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	e, err := f.lookupKind(key, kindHash)
+	if err != nil || e == nil {
+		return 0, err
+	}
+
+	var removed int64
+	for _, field := range fields {
+		if _, ok := e.hash[field]; ok {
+			delete(e.hash, field)
+			removed++
+		}
+	}
+	f.dropEmpty(key, e)
+	return removed, nil
+}
+
+// HExists checks if a hash field exists
+func (f *Fake) HExists(ctx context.Context, key, field string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return false, err
+	}
+
+	e, err := f.lookupKind(key, kindHash)
+	if err != nil || e == nil {
+		return false, err
+	}
+	_, ok := e.hash[field]
+	return ok, nil
+}
+
+// HIncrBy increments a hash field by the given amount
+func (f *Fake) HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	e, err := f.create(key, kindHash)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	if value, ok := e.hash[field]; ok {
+		if n, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return 0, errHashInt
+		}
+	}
+	n += incr
+	e.hash[field] = strconv.FormatInt(n, 10)
+	return n, nil
+}
+
+// HKeys gets all field names of a hash, sorted
+func (f *Fake) HKeys(ctx context.Context, key string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return nil, err
+	}
+
+	e, err := f.lookupKind(key, kindHash)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []string{}
+	if e != nil {
+		for field := range e.hash {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// HLen gets the number of fields in a hash
+func (f *Fake) HLen(ctx context.Context, key string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	e, err := f.lookupKind(key, kindHash)
+	if err != nil || e == nil {
+		return 0, err
+	}
+	return int64(len(e.hash)), nil
+}
+
+// HMGet returns the values of hash fields. Missing fields are nil.
+func (f *Fake) HMGet(ctx context.Context, key string, fields ...string) ([]interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return nil, err
+	}
+
+	e, err := f.lookupKind(key, kindHash)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, len(fields))
+	if e != nil {
+		for i, field := range fields {
+			if value, ok := e.hash[field]; ok {
+				values[i] = value
+			}
+		}
+	}
+	return values, nil
+}
+
+// HSetNX sets a hash field only if it does not exist
+func (f *Fake) HSetNX(ctx context.Context, key, field string, value interface{}) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return false, err
+	}
+
+	s, err := formatArg(value)
+	if err != nil {
+		return false, err
+	}
+	e, err := f.create(key, kindHash)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := e.hash[field]; ok {
+		return false, nil
+	}
+	e.hash[field] = s
+	return true, nil
+}
+
+// HVals returns all values in a hash, ordered by field name
+func (f *Fake) HVals(ctx context.Context, key string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return nil, err
+	}
+
+	e, err := f.lookupKind(key, kindHash)
+	if err != nil {
+		return nil, err
+	}
+
+	values := []string{}
+	if e != nil {
+		fields := make([]string, 0, len(e.hash))
+		for field := range e.hash {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			values = append(values, e.hash[field])
+		}
+	}
+	return values, nil
+}
+
+// HIncrByFloat increments the float value of a hash field by the given amount
+func (f *Fake) HIncrByFloat(ctx context.Context, key, field string, incr float64) (float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	e, err := f.create(key, kindHash)
+	if err != nil {
+		return 0, err
+	}
+
+	var n float64
+	if value, ok := e.hash[field]; ok {
+		if n, err = strconv.ParseFloat(value, 64); err != nil {
+			return 0, errHashFloat
+		}
+	}
+	n += incr
+	e.hash[field] = strconv.FormatFloat(n, 'f', -1, 64)
+	return n, nil
+}
@@ -0,0 +1,263 @@
+package redistest
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ====================
+// List Operations
+// ====================
+
+// LPush prepends values to a list, so the last value ends up first
+func (f *Fake) LPush(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	return f.push(key, values, true)
+}
+
+// RPush appends values to a list
+func (f *Fake) RPush(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	return f.push(key, values, false)
+}
+
+// LPop removes and returns the first element of a list
+func (f *Fake) LPop(ctx context.Context, key string) (string, error) {
+	return f.pop(key, true)
+}
+
+// RPop removes and returns the last element of a list
+func (f *Fake) RPop(ctx context.Context, key string) (string, error) {
+	return f.pop(key, false)
+}
+
+// LRange returns list elements from start to stop, inclusive. Negative
+// indexes count from the end.
+func (f *Fake) LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return nil, err
+	}
+
+	e, err := f.lookupKind(key, kindList)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []string{}
+	if e == nil {
+		return result, nil
+	}
+	if from, to, ok := rangeIndexes(start, stop, len(e.list)); ok {
+		result = append(result, e.list[from:to]...)
+	}
+	return result, nil
+}
+
+// LLen gets the length of a list
+func (f *Fake) LLen(ctx context.Context, key string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	e, err := f.lookupKind(key, kindList)
+	if err != nil || e == nil {
+		return 0, err
+	}
+	return int64(len(e.list)), nil
+}
+
+// push adds values to the head or tail of a list
+func (f *Fake) push(key string, values []interface{}, head bool) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	args, err := formatArgs(flattenArgs(values))
+	if err != nil {
+		return 0, err
+	}
+	if len(args) == 0 {
+		return 0, errArgs
+	}
+	e, err := f.create(key, kindList)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, arg := range args {
+		if head {
+			e.list = append([]string{arg}, e.list...)
+		} else {
+			e.list = append(e.list, arg)
+		}
+	}
+	return int64(len(e.list)), nil
+}
+
+// pop removes an element from the head or tail of a list
+func (f *Fake) pop(key string, head bool) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return "", err
+	}
+
+	e, err := f.lookupKind(key, kindList)
+	if err != nil {
+		return "", err
+	}
+	if e == nil {
+		return "", redis.Nil
+	}
+
+	var value string
+	if head {
+		value, e.list = e.list[0], e.list[1:]
+	} else {
+		value, e.list = e.list[len(e.list)-1], e.list[:len(e.list)-1]
+	}
+	f.dropEmpty(key, e)
+	return value, nil
+}
+
+// LTrim trims a list to the elements from start to stop, inclusive
+func (f *Fake) LTrim(ctx context.Context, key string, start, stop int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return err
+	}
+
+	e, err := f.lookupKind(key, kindList)
+	if err != nil || e == nil {
+		return err
+	}
+
+	if from, to, ok := rangeIndexes(start, stop, len(e.list)); ok {
+		e.list = append([]string(nil), e.list[from:to]...)
+	} else {
+		e.list = nil
+	}
+	f.dropEmpty(key, e)
+	return nil
+}
+
+// LRem removes count occurrences of value: from the head if count is positive,
+// from the tail if negative, and all of them if 0
+func (f *Fake) LRem(ctx context.Context, key string, count int64, value interface{}) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	s, err := formatArg(value)
+	if err != nil {
+		return 0, err
+	}
+	e, err := f.lookupKind(key, kindList)
+	if err != nil || e == nil {
+		return 0, err
+	}
+
+	limit := count
+	if limit < 0 {
+		limit = -limit
+	}
+	remove := make(map[int]bool)
+	for i := range e.list {
+		idx := i
+		if count < 0 {
+			idx = len(e.list) - 1 - i
+		}
+		if e.list[idx] == s {
+			remove[idx] = true
+			if limit > 0 && int64(len(remove)) == limit {
+				break
+			}
+		}
+	}
+
+	kept := e.list[:0:0]
+	for i, item := range e.list {
+		if !remove[i] {
+			kept = append(kept, item)
+		}
+	}
+	e.list = kept
+	f.dropEmpty(key, e)
+	return int64(len(remove)), nil
+}
+
+// LIndex returns the element at index. Negative indexes count from the end.
+func (f *Fake) LIndex(ctx context.Context, key string, index int64) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return "", err
+	}
+
+	e, err := f.lookupKind(key, kindList)
+	if err != nil {
+		return "", err
+	}
+	if e == nil {
+		return "", redis.Nil
+	}
+	i, ok := listIndex(index, len(e.list))
+	if !ok {
+		return "", redis.Nil
+	}
+	return e.list[i], nil
+}
+
+// LSet sets the element at index
+func (f *Fake) LSet(ctx context.Context, key string, index int64, value interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return err
+	}
+
+	s, err := formatArg(value)
+	if err != nil {
+		return err
+	}
+	e, err := f.lookupKind(key, kindList)
+	if err != nil {
+		return err
+	}
+	if e == nil {
+		return errNoSuchKey
+	}
+	i, ok := listIndex(index, len(e.list))
+	if !ok {
+		return errIndexRange
+	}
+	e.list[i] = s
+	return nil
+}
+
+// listIndex resolves a possibly negative list index
+func listIndex(index int64, length int) (int, bool) {
+	if index < 0 {
+		index += int64(length)
+	}
+	if index < 0 || index >= int64(length) {
+		return 0, false
+	}
+	return int(index), true
+}
@@ -0,0 +1,173 @@
+package redistest
+
+import (
+	"context"
+	"sync"
+
+	redisclient "github.com/isimtekin/go-packages/redis-client"
+	"github.com/redis/go-redis/v9"
+)
+
+// ====================
+// Pub/Sub Operations
+// ====================
+
+// Subscription receives messages published to its channels and patterns.
+// Messages are queued without limit, so publishers never block.
+type Subscription struct {
+	fake     *Fake
+	channels map[string]struct{}
+	patterns map[string]struct{}
+
+	mu     sync.Mutex
+	queue  []*redis.Message
+	notify chan struct{}
+	ch     chan *redis.Message
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Subscribe subscribes to channels. Call Close on the subscription when done.
+func (f *Fake) Subscribe(ctx context.Context, channels ...string) *Subscription {
+	return f.subscribe(channels, nil)
+}
+
+// PSubscribe subscribes to channel patterns
+func (f *Fake) PSubscribe(ctx context.Context, patterns ...string) *Subscription {
+	return f.subscribe(nil, patterns)
+}
+
+// Publish posts a message to a channel and returns the number of deliveries
+func (f *Fake) Publish(ctx context.Context, channel string, message interface{}) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(); err != nil {
+		return 0, err
+	}
+
+	payload, err := formatArg(message)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	for sub := range f.subs {
+		if _, ok := sub.channels[channel]; ok {
+			sub.deliver(&redis.Message{Channel: channel, Payload: payload})
+			n++
+		}
+		for pattern := range sub.patterns {
+			if matchGlob(pattern, channel) {
+				sub.deliver(&redis.Message{Channel: channel, Pattern: pattern, Payload: payload})
+				n++
+			}
+		}
+	}
+	return n, nil
+}
+
+// subscribe registers a subscription. A closed fake returns a closed one.
+func (f *Fake) subscribe(channels, patterns []string) *Subscription {
+	sub := &Subscription{
+		fake:     f,
+		channels: make(map[string]struct{}),
+		patterns: make(map[string]struct{}),
+		notify:   make(chan struct{}, 1),
+		ch:       make(chan *redis.Message),
+		done:     make(chan struct{}),
+	}
+	for _, channel := range channels {
+		sub.channels[channel] = struct{}{}
+	}
+	for _, pattern := range patterns {
+		sub.patterns[pattern] = struct{}{}
+	}
+	go sub.forward()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		sub.close()
+		return sub
+	}
+	f.subs[sub] = struct{}{}
+	return sub
+}
+
+// Channel returns the channel of received messages. It is closed when the
+// subscription or the fake is closed.
+func (s *Subscription) Channel() <-chan *redis.Message {
+	return s.ch
+}
+
+// ReceiveMessage waits for the next message. It returns
+// redisclient.ErrClientClosed once the subscription is closed.
+func (s *Subscription) ReceiveMessage(ctx context.Context) (*redis.Message, error) {
+	select {
+	case msg, ok := <-s.ch:
+		if !ok {
+			return nil, redisclient.ErrClientClosed
+		}
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close unsubscribes and closes the message channel
+func (s *Subscription) Close() error {
+	s.fake.mu.Lock()
+	delete(s.fake.subs, s)
+	s.fake.mu.Unlock()
+
+	s.close()
+	return nil
+}
+
+// close stops forwarding messages
+func (s *Subscription) close() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+}
+
+// deliver queues a message
+func (s *Subscription) deliver(msg *redis.Message) {
+	s.mu.Lock()
+	s.queue = append(s.queue, msg)
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// forward moves queued messages to the channel in order
+func (s *Subscription) forward() {
+	defer close(s.ch)
+
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.mu.Unlock()
+			select {
+			case <-s.notify:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+		msg := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+
+		select {
+		case s.ch <- msg:
+		case <-s.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package redistest
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestFake_PubSub tests channel and pattern subscriptions
+func TestFake_PubSub(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	f := New()
+
+	events := f.Subscribe(ctx, "events")
+	defer events.Close()
+	users := f.PSubscribe(ctx, "user:*")
+	defer users.Close()
+
+	if n, _ := f.Publish(ctx, "events", "first"); n != 1 {
+		t.Errorf("Publish() = %d, want 1", n)
+	}
+	f.Publish(ctx, "events", 2)
+	f.Publish(ctx, "user:1", "login")
+	if n, _ := f.Publish(ctx, "nobody", "x"); n != 0 {
+		t.Errorf("Publish() without subscribers = %d, want 0", n)
+	}
+
+	for _, want := range []string{"first", "2"} {
+		msg, err := events.ReceiveMessage(ctx)
+		if err != nil || msg.Channel != "events" || msg.Payload != want {
+			t.Errorf("ReceiveMessage() = %+v, %v, want %q", msg, err, want)
+		}
+	}
+
+	msg := <-users.Channel()
+	if msg.Channel != "user:1" || msg.Pattern != "user:*" || msg.Payload != "login" {
+		t.Errorf("pattern message = %+v", msg)
+	}
+
+	users.Close()
+	if _, ok := <-users.Channel(); ok {
+		t.Error("Channel() should be closed after Close")
+	}
+	if n, _ := f.Publish(ctx, "user:2", "x"); n != 0 {
+		t.Errorf("Publish() after Close = %d, want 0", n)
+	}
+
+	f.Close()
+	if _, err := events.ReceiveMessage(ctx); err == nil {
+		t.Error("ReceiveMessage() should fail after the fake is closed")
+	}
+}
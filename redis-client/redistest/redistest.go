@@ -0,0 +1,602 @@
+// Package redistest provides an in-memory implementation of
+// redisclient.Cmdable for unit tests.
+//
+// The fake keeps all data in process, needs no server and is safe for
+// concurrent use. It implements strings, hashes, lists, sets, sorted sets,
+// key expiry and pub/sub with the same replies and errors as Client: missing
+// keys return redis.Nil, type mismatches return a WRONGTYPE error and a
+// closed fake returns redisclient.ErrClientClosed.
+//
+// Time does not pass on its own. Keys expire when the fake's clock is moved
+// with Advance or SetTime:
+//
+//	fake := redistest.New()
+//	svc := NewSessionStore(fake) // accepts a redisclient.Cmdable
+//
+//	svc.Login(ctx, "alice")      // SET session:alice ... EX 1800
+//	fake.Advance(31 * time.Minute)
+//	_, err := fake.Get(ctx, "session:alice") // redis.Nil
+package redistest
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	redisclient "github.com/isimtekin/go-packages/redis-client"
+)
+
+// serverError is an error reply, matching the errors go-redis returns for
+// Redis error replies
+type serverError string
+
+func (e serverError) Error() string { return string(e) }
+
+// RedisError marks the error as a Redis reply, see redis.Error
+func (serverError) RedisError() {}
+
+var (
+	errWrongType  = serverError("WRONGTYPE Operation against a key holding the wrong kind of value")
+	errNotInt     = serverError("ERR value is not an integer or out of range")
+	errHashInt    = serverError("ERR hash value is not an integer")
+	errNotFloat   = serverError("ERR value is not a valid float")
+	errHashFloat  = serverError("ERR hash value is not a float")
+	errNoSuchKey  = serverError("ERR no such key")
+	errIndexRange = serverError("ERR index out of range")
+	errMinMax     = serverError("ERR min or max is not a float")
+	errArgs       = serverError("ERR wrong number of arguments")
+)
+
+// kind is the type of a stored value
+type kind int
+
+const (
+	kindString kind = iota
+	kindHash
+	kindList
+	kindSet
+	kindZSet
+)
+
+// entry is a stored key
+type entry struct {
+	kind     kind
+	str      string
+	hash     map[string]string
+	list     []string
+	set      map[string]struct{}
+	zset     map[string]float64
+	expireAt time.Time // zero means no expiry
+}
+
+// Fake is an in-memory Redis for unit tests. Create it with New.
+type Fake struct {
+	mu     sync.Mutex
+	now    time.Time
+	data   map[string]*entry
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+var _ redisclient.Cmdable = (*Fake)(nil)
+
+// Option configures a Fake
+type Option func(*Fake)
+
+// WithTime sets the initial time of the fake's clock (default time.Now())
+func WithTime(t time.Time) Option {
+	return func(f *Fake) {
+		f.now = t
+	}
+}
+
+// New creates an empty fake
+func New(opts ...Option) *Fake {
+	f := &Fake{
+		now:  time.Now(),
+		data: make(map[string]*entry),
+		subs: make(map[*Subscription]struct{}),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// ====================
+// Clock and State
+// ====================
+
+// Now returns the fake's current time
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Advance moves the clock forward by d, expiring keys whose TTL ran out
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+// SetTime sets the clock to t
+func (f *Fake) SetTime(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = t
+}
+
+// FlushAll removes all keys
+func (f *Fake) FlushAll() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data = make(map[string]*entry)
+}
+
+// Close closes the fake and all subscriptions. Later calls return
+// redisclient.ErrClientClosed.
+func (f *Fake) Close() error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return redisclient.ErrAlreadyClosed
+	}
+	f.closed = true
+	subs := f.subs
+	f.subs = make(map[*Subscription]struct{})
+	f.mu.Unlock()
+
+	for sub := range subs {
+		sub.close()
+	}
+	return nil
+}
+
+// Ping returns nil unless the fake is closed
+func (f *Fake) Ping(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.check()
+}
+
+// check returns ErrClientClosed for a closed fake. Callers hold f.mu.
+func (f *Fake) check() error {
+	if f.closed {
+		return redisclient.ErrClientClosed
+	}
+	return nil
+}
+
+// checkKey validates the fake and a key argument. Callers hold f.mu.
+func (f *Fake) checkKey(key string) error {
+	if err := f.check(); err != nil {
+		return err
+	}
+	if key == "" {
+		return redisclient.ErrInvalidKey
+	}
+	return nil
+}
+
+// lookup returns a live key, deleting it if it expired. Callers hold f.mu.
+func (f *Fake) lookup(key string) *entry {
+	e, ok := f.data[key]
+	if !ok {
+		return nil
+	}
+	if !e.expireAt.IsZero() && !f.now.Before(e.expireAt) {
+		delete(f.data, key)
+		return nil
+	}
+	return e
+}
+
+// lookupKind returns a live key of the given kind, nil if it does not exist,
+// or a WRONGTYPE error. Callers hold f.mu.
+func (f *Fake) lookupKind(key string, k kind) (*entry, error) {
+	e := f.lookup(key)
+	if e == nil {
+		return nil, nil
+	}
+	if e.kind != k {
+		return nil, errWrongType
+	}
+	return e, nil
+}
+
+// create returns a live key of the given kind, creating it if needed. Callers
+// hold f.mu.
+func (f *Fake) create(key string, k kind) (*entry, error) {
+	e, err := f.lookupKind(key, k)
+	if err != nil || e != nil {
+		return e, err
+	}
+
+	e = &entry{kind: k}
+	switch k {
+	case kindHash:
+		e.hash = make(map[string]string)
+	case kindSet:
+		e.set = make(map[string]struct{})
+	case kindZSet:
+		e.zset = make(map[string]float64)
+	}
+	f.data[key] = e
+	return e, nil
+}
+
+// dropEmpty deletes a collection key that has no elements left, as Redis
+// does. Callers hold f.mu.
+func (f *Fake) dropEmpty(key string, e *entry) {
+	var n int
+	switch e.kind {
+	case kindString:
+		return
+	case kindHash:
+		n = len(e.hash)
+	case kindList:
+		n = len(e.list)
+	case kindSet:
+		n = len(e.set)
+	case kindZSet:
+		n = len(e.zset)
+	}
+	if n == 0 {
+		delete(f.data, key)
+	}
+}
+
+// ====================
+// Key Operations
+// ====================
+
+// Del deletes one or more keys
+func (f *Fake) Del(ctx context.Context, keys ...string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(); err != nil {
+		return 0, err
+	}
+
+	var n int64
+	for _, key := range keys {
+		if f.lookup(key) != nil {
+			delete(f.data, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Exists returns how many of the keys exist
+func (f *Fake) Exists(ctx context.Context, keys ...string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(); err != nil {
+		return 0, err
+	}
+
+	var n int64
+	for _, key := range keys {
+		if f.lookup(key) != nil {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Expire sets a key's time to live. A TTL of zero or less deletes the key.
+func (f *Fake) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return false, err
+	}
+
+	e := f.lookup(key)
+	if e == nil {
+		return false, nil
+	}
+	if ttl <= 0 {
+		delete(f.data, key)
+		return true, nil
+	}
+	// go-redis sends whole seconds and rounds sub-second TTLs up to one
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+	e.expireAt = f.now.Add(ttl.Truncate(time.Second))
+	return true, nil
+}
+
+// TTL returns the time to live of a key in whole seconds, -1 if it has no
+// expiry and -2 if it does not exist, like go-redis
+func (f *Fake) TTL(ctx context.Context, key string) (time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	e := f.lookup(key)
+	switch {
+	case e == nil:
+		return -2, nil
+	case e.expireAt.IsZero():
+		return -1, nil
+	}
+	return e.expireAt.Sub(f.now).Round(time.Second), nil
+}
+
+// Persist removes the expiry of a key
+func (f *Fake) Persist(ctx context.Context, key string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return false, err
+	}
+
+	e := f.lookup(key)
+	if e == nil || e.expireAt.IsZero() {
+		return false, nil
+	}
+	e.expireAt = time.Time{}
+	return true, nil
+}
+
+// Keys returns the keys matching a glob pattern, sorted
+func (f *Fake) Keys(ctx context.Context, pattern string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(); err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	for key := range f.data {
+		if f.lookup(key) != nil && matchGlob(pattern, key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Unlink deletes keys like Del
+func (f *Fake) Unlink(ctx context.Context, keys ...string) (int64, error) {
+	return f.Del(ctx, keys...)
+}
+
+// Type returns the type of the value stored at key, or "none"
+func (f *Fake) Type(ctx context.Context, key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return "", err
+	}
+
+	e := f.lookup(key)
+	if e == nil {
+		return "none", nil
+	}
+	return [...]string{"string", "hash", "list", "set", "zset"}[e.kind], nil
+}
+
+// Rename renames a key, keeping its TTL and overwriting newKey
+func (f *Fake) Rename(ctx context.Context, key, newKey string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return err
+	}
+	if newKey == "" {
+		return redisclient.ErrInvalidKey
+	}
+
+	e := f.lookup(key)
+	if e == nil {
+		return errNoSuchKey
+	}
+	delete(f.data, key)
+	f.data[newKey] = e
+	return nil
+}
+
+// ExpireAt sets a key to expire at the given time, with second precision
+func (f *Fake) ExpireAt(ctx context.Context, key string, tm time.Time) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return false, err
+	}
+
+	e := f.lookup(key)
+	if e == nil {
+		return false, nil
+	}
+	e.expireAt = time.Unix(tm.Unix(), 0)
+	if !f.now.Before(e.expireAt) {
+		delete(f.data, key)
+	}
+	return true, nil
+}
+
+// PTTL returns the time to live of a key with millisecond precision, -1 if it
+// has no expiry and -2 if it does not exist
+func (f *Fake) PTTL(ctx context.Context, key string) (time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	e := f.lookup(key)
+	switch {
+	case e == nil:
+		return -2, nil
+	case e.expireAt.IsZero():
+		return -1, nil
+	}
+	return e.expireAt.Sub(f.now).Truncate(time.Millisecond), nil
+}
+
+// ====================
+// Helpers
+// ====================
+
+// formatArg converts a command argument to the string Redis would store,
+// following the go-redis argument encoding
+func formatArg(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case int:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int8:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int16:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 64), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case time.Duration:
+		return strconv.FormatInt(v.Nanoseconds(), 10), nil
+	case encoding.BinaryMarshaler:
+		b, err := v.MarshalBinary()
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+
+	// Pointers to basic types are dereferenced, nil pointers are zero values
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return formatArg(reflect.Zero(rv.Type().Elem()).Interface())
+		}
+		return formatArg(rv.Elem().Interface())
+	}
+	return "", fmt.Errorf("redis: can't marshal %T (implement encoding.BinaryMarshaler)", v)
+}
+
+// flattenArgs expands a single slice or map argument the way go-redis does
+func flattenArgs(values []interface{}) []interface{} {
+	if len(values) != 1 {
+		return values
+	}
+
+	switch v := values[0].(type) {
+	case []string:
+		args := make([]interface{}, len(v))
+		for i, s := range v {
+			args[i] = s
+		}
+		return args
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		args := make([]interface{}, 0, len(v)*2)
+		for k, val := range v {
+			args = append(args, k, val)
+		}
+		return args
+	case map[string]string:
+		args := make([]interface{}, 0, len(v)*2)
+		for k, val := range v {
+			args = append(args, k, val)
+		}
+		return args
+	}
+	return values
+}
+
+// formatArgs formats all arguments
+func formatArgs(values []interface{}) ([]string, error) {
+	args := make([]string, len(values))
+	for i, v := range values {
+		s, err := formatArg(v)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = s
+	}
+	return args, nil
+}
+
+// pairs formats flattened field-value arguments
+func pairs(values []interface{}) ([]string, error) {
+	args, err := formatArgs(flattenArgs(values))
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 || len(args)%2 != 0 {
+		return nil, errArgs
+	}
+	return args, nil
+}
+
+// rangeIndexes converts Redis start/stop indexes, which may be negative, to a
+// half-open slice range. ok is false for an empty range.
+func rangeIndexes(start, stop int64, length int) (from, to int, ok bool) {
+	n := int64(length)
+	if start < 0 {
+		start += n
+	}
+	if stop < 0 {
+		stop += n
+	}
+	if start < 0 {
+		start = 0
+	}
+	if stop >= n {
+		stop = n - 1
+	}
+	if start > stop || start >= n {
+		return 0, 0, false
+	}
+	return int(start), int(stop) + 1, true
+}
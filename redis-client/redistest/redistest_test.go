@@ -0,0 +1,319 @@
+package redistest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	redisclient "github.com/isimtekin/go-packages/redis-client"
+	"github.com/redis/go-redis/v9"
+)
+
+// TestFake_Strings tests string commands
+func TestFake_Strings(t *testing.T) {
+	ctx := context.Background()
+	f := New()
+
+	if _, err := f.Get(ctx, "missing"); !redisclient.IsNil(err) {
+		t.Errorf("Get(missing) error = %v, want redis.Nil", err)
+	}
+
+	f.Set(ctx, "a", 42, 0)
+	if v, _ := f.Get(ctx, "a"); v != "42" {
+		t.Errorf("Get(a) = %q, want 42", v)
+	}
+	if ok, _ := f.SetNX(ctx, "a", "x", 0); ok {
+		t.Error("SetNX on existing key should fail")
+	}
+	if old, _ := f.GetSet(ctx, "a", 1.5); old != "42" {
+		t.Errorf("GetSet() = %q, want 42", old)
+	}
+	if _, err := f.Incr(ctx, "a"); err == nil || err.Error() != errNotInt.Error() {
+		t.Errorf("Incr(float) error = %v", err)
+	}
+
+	if n, _ := f.IncrBy(ctx, "n", 5); n != 5 {
+		t.Errorf("IncrBy() = %d, want 5", n)
+	}
+	if n, _ := f.DecrBy(ctx, "n", 7); n != -2 {
+		t.Errorf("DecrBy() = %d, want -2", n)
+	}
+
+	f.MSet(ctx, "x", "1", "y", true)
+	values, _ := f.MGet(ctx, "x", "y", "z")
+	if !reflect.DeepEqual(values, []interface{}{"1", "1", nil}) {
+		t.Errorf("MGet() = %v", values)
+	}
+
+	if err := f.SetEX(ctx, "a", "v", 0); err != redisclient.ErrInvalidTTL {
+		t.Errorf("SetEX(0) error = %v, want ErrInvalidTTL", err)
+	}
+	if err := f.Set(ctx, "", "v", 0); err != redisclient.ErrInvalidKey {
+		t.Errorf("Set(\"\") error = %v, want ErrInvalidKey", err)
+	}
+	if err := f.Set(ctx, "a", struct{}{}, 0); err == nil {
+		t.Error("Set() should reject values go-redis cannot encode")
+	}
+}
+
+// TestFake_Expiry tests TTLs against the controllable clock
+func TestFake_Expiry(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := New(WithTime(start))
+
+	f.Set(ctx, "session", "alice", 30*time.Minute)
+	f.Set(ctx, "forever", "x", 0)
+	f.Set(ctx, "untimed", "y")
+
+	if ttl, _ := f.TTL(ctx, "session"); ttl != 30*time.Minute {
+		t.Errorf("TTL() = %v, want 30m", ttl)
+	}
+	if ttl, _ := f.TTL(ctx, "forever"); ttl != -1 {
+		t.Errorf("TTL(no expiry) = %v, want -1", ttl)
+	}
+	if ttl, _ := f.TTL(ctx, "untimed"); ttl != -1 {
+		t.Errorf("TTL(no ttl argument) = %v, want -1", ttl)
+	}
+	if ttl, _ := f.TTL(ctx, "missing"); ttl != -2 {
+		t.Errorf("TTL(missing) = %v, want -2", ttl)
+	}
+
+	f.Advance(29 * time.Minute)
+	if _, err := f.Get(ctx, "session"); err != nil {
+		t.Errorf("Get() before expiry error = %v", err)
+	}
+	f.Set(ctx, "session", "bob", redis.KeepTTL)
+
+	f.Advance(time.Minute)
+	if _, err := f.Get(ctx, "session"); !redisclient.IsNil(err) {
+		t.Errorf("Get() after expiry error = %v, want redis.Nil", err)
+	}
+	if n, _ := f.Exists(ctx, "session", "forever"); n != 1 {
+		t.Errorf("Exists() = %d, want 1", n)
+	}
+
+	f.Expire(ctx, "forever", 10*time.Second)
+	if ok, _ := f.Persist(ctx, "forever"); !ok {
+		t.Error("Persist() = false, want true")
+	}
+	f.SetTime(start.Add(24 * time.Hour))
+	if _, err := f.Get(ctx, "forever"); err != nil {
+		t.Errorf("Get() after Persist error = %v", err)
+	}
+	if !f.Now().Equal(start.Add(24 * time.Hour)) {
+		t.Errorf("Now() = %v", f.Now())
+	}
+}
+
+// TestFake_Collections tests hash, list, set and sorted set commands
+func TestFake_Collections(t *testing.T) {
+	ctx := context.Background()
+	f := New()
+
+	f.HSet(ctx, "h", "a", 1, "b", "x")
+	f.HSet(ctx, "h", map[string]interface{}{"c": 3})
+	if n, _ := f.HIncrBy(ctx, "h", "a", 4); n != 5 {
+		t.Errorf("HIncrBy() = %d, want 5", n)
+	}
+	if _, err := f.HIncrBy(ctx, "h", "b", 1); err == nil {
+		t.Error("HIncrBy() on a non-integer field should fail")
+	}
+	if keys, _ := f.HKeys(ctx, "h"); !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Errorf("HKeys() = %v", keys)
+	}
+	if _, err := f.HGet(ctx, "h", "missing"); !redisclient.IsNil(err) {
+		t.Errorf("HGet(missing) error = %v, want redis.Nil", err)
+	}
+	f.HDel(ctx, "h", "a", "b", "c")
+	if n, _ := f.Exists(ctx, "h"); n != 0 {
+		t.Error("empty hash should be deleted")
+	}
+
+	f.RPush(ctx, "l", "b", "c")
+	f.LPush(ctx, "l", []string{"a", "z"})
+	if items, _ := f.LRange(ctx, "l", 0, -1); !reflect.DeepEqual(items, []string{"z", "a", "b", "c"}) {
+		t.Errorf("LRange() = %v", items)
+	}
+	if items, _ := f.LRange(ctx, "l", -2, 10); !reflect.DeepEqual(items, []string{"b", "c"}) {
+		t.Errorf("LRange(-2, 10) = %v", items)
+	}
+	if v, _ := f.RPop(ctx, "l"); v != "c" {
+		t.Errorf("RPop() = %q, want c", v)
+	}
+	if v, _ := f.LPop(ctx, "l"); v != "z" {
+		t.Errorf("LPop() = %q, want z", v)
+	}
+
+	f.SAdd(ctx, "s", "b", "a", "b")
+	if n, _ := f.SCard(ctx, "s"); n != 2 {
+		t.Errorf("SCard() = %d, want 2", n)
+	}
+	if ok, _ := f.SIsMember(ctx, "s", "a"); !ok {
+		t.Error("SIsMember(a) = false")
+	}
+	if members, _ := f.SMembers(ctx, "s"); !reflect.DeepEqual(members, []string{"a", "b"}) {
+		t.Errorf("SMembers() = %v", members)
+	}
+
+	f.ZAdd(ctx, "z", redis.Z{Score: 2, Member: "b"}, redis.Z{Score: 1, Member: "c"}, redis.Z{Score: 2, Member: "a"})
+	if members, _ := f.ZRange(ctx, "z", 0, -1); !reflect.DeepEqual(members, []string{"c", "a", "b"}) {
+		t.Errorf("ZRange() = %v", members)
+	}
+	if zs, _ := f.ZRangeWithScores(ctx, "z", -1, -1); len(zs) != 1 || zs[0].Member != "b" || zs[0].Score != 2 {
+		t.Errorf("ZRangeWithScores(-1, -1) = %v", zs)
+	}
+	if score, _ := f.ZScore(ctx, "z", "c"); score != 1 {
+		t.Errorf("ZScore() = %v, want 1", score)
+	}
+	if n, _ := f.ZRem(ctx, "z", "a", "missing"); n != 1 {
+		t.Errorf("ZRem() = %d, want 1", n)
+	}
+
+	if _, err := f.LPush(ctx, "s", "x"); !errors.Is(err, errWrongType) {
+		t.Errorf("LPush on a set error = %v, want WRONGTYPE", err)
+	}
+	var redisErr redis.Error
+	if _, err := f.Get(ctx, "z"); !errors.As(err, &redisErr) {
+		t.Errorf("Get on a sorted set error = %v, want a redis.Error", err)
+	}
+}
+
+// TestFake_Keys tests pattern matching and deletion
+func TestFake_Keys(t *testing.T) {
+	ctx := context.Background()
+	f := New()
+	f.MSet(ctx, "user:1", "a", "user:2", "b", "user:10", "c", "order:1", "d")
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"*", []string{"order:1", "user:1", "user:10", "user:2"}},
+		{"user:?", []string{"user:1", "user:2"}},
+		{"user:[^2]*", []string{"user:1", "user:10"}},
+		{"[a-p]*", []string{"order:1"}},
+		{"user\\:1", []string{"user:1"}},
+	}
+	for _, tt := range tests {
+		if keys, _ := f.Keys(ctx, tt.pattern); !reflect.DeepEqual(keys, tt.want) {
+			t.Errorf("Keys(%q) = %v, want %v", tt.pattern, keys, tt.want)
+		}
+	}
+
+	if n, _ := f.Del(ctx, "user:1", "user:2", "missing"); n != 2 {
+		t.Errorf("Del() = %d, want 2", n)
+	}
+	f.FlushAll()
+	if keys, _ := f.Keys(ctx, "*"); len(keys) != 0 {
+		t.Errorf("Keys() after FlushAll = %v", keys)
+	}
+}
+
+// TestFake_Closed tests that a closed fake fails like a closed client
+func TestFake_Closed(t *testing.T) {
+	ctx := context.Background()
+	f := New()
+	f.Close()
+
+	if err := f.Ping(ctx); err != redisclient.ErrClientClosed {
+		t.Errorf("Ping() error = %v, want ErrClientClosed", err)
+	}
+	if _, err := f.Get(ctx, "a"); err != redisclient.ErrClientClosed {
+		t.Errorf("Get() error = %v, want ErrClientClosed", err)
+	}
+	if err := f.Close(); err != redisclient.ErrAlreadyClosed {
+		t.Errorf("Close() error = %v, want ErrAlreadyClosed", err)
+	}
+}
+
+// TestFake_ExtendedCommands tests the extended string, key, list, set and
+// sorted set commands
+func TestFake_ExtendedCommands(t *testing.T) {
+	ctx := context.Background()
+	f := New()
+
+	f.Set(ctx, "s", "hello", 0)
+	if v, _ := f.GetEx(ctx, "s", 1500*time.Millisecond); v != "hello" {
+		t.Errorf("GetEx() = %q", v)
+	}
+	if ttl, _ := f.PTTL(ctx, "s"); ttl != 1500*time.Millisecond {
+		t.Errorf("PTTL() = %v, want 1.5s", ttl)
+	}
+	if n, _ := f.Append(ctx, "s", "!"); n != 6 {
+		t.Errorf("Append() = %d, want 6", n)
+	}
+	if v, _ := f.GetDel(ctx, "s"); v != "hello!" {
+		t.Errorf("GetDel() = %q", v)
+	}
+	if typ, _ := f.Type(ctx, "s"); typ != "none" {
+		t.Errorf("Type() after GetDel = %q, want none", typ)
+	}
+	if v, _ := f.IncrByFloat(ctx, "f", 0.5); v != 0.5 {
+		t.Errorf("IncrByFloat() = %v", v)
+	}
+	if err := f.Rename(ctx, "missing", "x"); err == nil {
+		t.Error("Rename() of a missing key should fail")
+	}
+
+	f.RPush(ctx, "l", "a", "b", "a", "c", "a")
+	if n, _ := f.LRem(ctx, "l", -2, "a"); n != 2 {
+		t.Errorf("LRem() = %d, want 2", n)
+	}
+	if items, _ := f.LRange(ctx, "l", 0, -1); !reflect.DeepEqual(items, []string{"a", "b", "c"}) {
+		t.Errorf("LRange() after LRem = %v", items)
+	}
+	if _, err := f.LIndex(ctx, "l", 5); !redisclient.IsNil(err) {
+		t.Errorf("LIndex() out of range error = %v, want redis.Nil", err)
+	}
+	f.LTrim(ctx, "l", 1, -1)
+	if v, _ := f.LIndex(ctx, "l", 0); v != "b" {
+		t.Errorf("LIndex(0) after LTrim = %q, want b", v)
+	}
+
+	f.SAdd(ctx, "s1", "a", "b", "c")
+	f.SAdd(ctx, "s2", "b", "c", "d")
+	if m, _ := f.SInter(ctx, "s1", "s2"); !reflect.DeepEqual(m, []string{"b", "c"}) {
+		t.Errorf("SInter() = %v", m)
+	}
+	if m, _ := f.SUnion(ctx, "s1", "s2", "missing"); !reflect.DeepEqual(m, []string{"a", "b", "c", "d"}) {
+		t.Errorf("SUnion() = %v", m)
+	}
+	if m, _ := f.SDiff(ctx, "s1", "s2"); !reflect.DeepEqual(m, []string{"a"}) {
+		t.Errorf("SDiff() = %v", m)
+	}
+	if _, err := f.SPop(ctx, "missing"); !redisclient.IsNil(err) {
+		t.Errorf("SPop() on a missing set error = %v, want redis.Nil", err)
+	}
+
+	f.ZAdd(ctx, "z", redis.Z{Score: 1, Member: "a"}, redis.Z{Score: 2, Member: "b"}, redis.Z{Score: 3, Member: "c"})
+	if score, _ := f.ZIncrBy(ctx, "z", 5, "a"); score != 6 {
+		t.Errorf("ZIncrBy() = %v, want 6", score)
+	}
+	if m, _ := f.ZRevRange(ctx, "z", 0, 0); !reflect.DeepEqual(m, []string{"a"}) {
+		t.Errorf("ZRevRange() = %v", m)
+	}
+	if m, _ := f.ZRangeByScore(ctx, "z", &redis.ZRangeBy{Min: "(2", Max: "+inf"}); !reflect.DeepEqual(m, []string{"c", "a"}) {
+		t.Errorf("ZRangeByScore() = %v", m)
+	}
+	if m, _ := f.ZRevRangeByScore(ctx, "z", &redis.ZRangeBy{Min: "-inf", Max: "+inf", Offset: 1, Count: 1}); !reflect.DeepEqual(m, []string{"c"}) {
+		t.Errorf("ZRevRangeByScore() with limit = %v", m)
+	}
+	if rank, _ := f.ZRevRank(ctx, "z", "b"); rank != 2 {
+		t.Errorf("ZRevRank() = %d, want 2", rank)
+	}
+	if _, err := f.ZRank(ctx, "z", "missing"); !redisclient.IsNil(err) {
+		t.Errorf("ZRank() of a missing member error = %v, want redis.Nil", err)
+	}
+	if n, _ := f.ZRemRangeByScore(ctx, "z", "-inf", "(3"); n != 1 {
+		t.Errorf("ZRemRangeByScore() = %d, want 1", n)
+	}
+	if n, _ := f.ZCount(ctx, "z", "-inf", "+inf"); n != 2 {
+		t.Errorf("ZCount() = %d, want 2", n)
+	}
+	if _, err := f.ZCount(ctx, "z", "low", "high"); err == nil {
+		t.Error("ZCount() should reject invalid bounds")
+	}
+}
@@ -0,0 +1,682 @@
+package redistest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	redisclient "github.com/isimtekin/go-packages/redis-client"
+	"github.com/redis/go-redis/v9"
+)
+
+// ====================
+// Set Operations
+// ====================
+
+// SAdd adds members to a set
+func (f *Fake) SAdd(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	args, err := formatArgs(flattenArgs(members))
+	if err != nil {
+		return 0, err
+	}
+	if len(args) == 0 {
+		return 0, errArgs
+	}
+	e, err := f.create(key, kindSet)
+	if err != nil {
+		return 0, err
+	}
+
+	var added int64
+	for _, member := range args {
+		if _, ok := e.set[member]; !ok {
+			e.set[member] = struct{}{}
+			added++
+		}
+	}
+	return added, nil
+}
+
+// SMembers returns all members of a set, sorted
+func (f *Fake) SMembers(ctx context.Context, key string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return nil, err
+	}
+
+	e, err := f.lookupKind(key, kindSet)
+	if err != nil {
+		return nil, err
+	}
+
+	members := []string{}
+	if e != nil {
+		for member := range e.set {
+			members = append(members, member)
+		}
+	}
+	sort.Strings(members)
+	return members, nil
+}
+
+// SIsMember checks if a member is in a set
+func (f *Fake) SIsMember(ctx context.Context, key string, member interface{}) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return false, err
+	}
+
+	m, err := formatArg(member)
+	if err != nil {
+		return false, err
+	}
+	e, err := f.lookupKind(key, kindSet)
+	if err != nil || e == nil {
+		return false, err
+	}
+	_, ok := e.set[m]
+	return ok, nil
+}
+
+// SRem removes members from a set
+func (f *Fake) SRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	args, err := formatArgs(flattenArgs(members))
+	if err != nil {
+		return 0, err
+	}
+	e, err := f.lookupKind(key, kindSet)
+	if err != nil || e == nil {
+		return 0, err
+	}
+
+	var removed int64
+	for _, member := range args {
+		if _, ok := e.set[member]; ok {
+			delete(e.set, member)
+			removed++
+		}
+	}
+	f.dropEmpty(key, e)
+	return removed, nil
+}
+
+// SCard gets the number of members in a set
+func (f *Fake) SCard(ctx context.Context, key string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	e, err := f.lookupKind(key, kindSet)
+	if err != nil || e == nil {
+		return 0, err
+	}
+	return int64(len(e.set)), nil
+}
+
+// ====================
+// Sorted Set Operations
+// ====================
+
+// ZAdd adds members to a sorted set or updates their scores
+func (f *Fake) ZAdd(ctx context.Context, key string, members ...redis.Z) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	if len(members) == 0 {
+		return 0, errArgs
+	}
+	names := make([]string, len(members))
+	for i, z := range members {
+		name, err := formatArg(z.Member)
+		if err != nil {
+			return 0, err
+		}
+		names[i] = name
+	}
+	e, err := f.create(key, kindZSet)
+	if err != nil {
+		return 0, err
+	}
+
+	var added int64
+	for i, z := range members {
+		if _, ok := e.zset[names[i]]; !ok {
+			added++
+		}
+		e.zset[names[i]] = z.Score
+	}
+	return added, nil
+}
+
+// ZRange returns members by rank from start to stop, inclusive, ordered by
+// score and then member
+func (f *Fake) ZRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return nil, err
+	}
+
+	zs, err := f.zrange(key, start, stop)
+	return members(zs), err
+}
+
+// ZRangeWithScores returns members with scores by rank from start to stop
+func (f *Fake) ZRangeWithScores(ctx context.Context, key string, start, stop int64) ([]redis.Z, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return nil, err
+	}
+
+	return f.zrange(key, start, stop)
+}
+
+// ZRem removes members from a sorted set
+func (f *Fake) ZRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	args, err := formatArgs(flattenArgs(members))
+	if err != nil {
+		return 0, err
+	}
+	e, err := f.lookupKind(key, kindZSet)
+	if err != nil || e == nil {
+		return 0, err
+	}
+
+	var removed int64
+	for _, member := range args {
+		if _, ok := e.zset[member]; ok {
+			delete(e.zset, member)
+			removed++
+		}
+	}
+	f.dropEmpty(key, e)
+	return removed, nil
+}
+
+// ZCard gets the number of members in a sorted set
+func (f *Fake) ZCard(ctx context.Context, key string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	e, err := f.lookupKind(key, kindZSet)
+	if err != nil || e == nil {
+		return 0, err
+	}
+	return int64(len(e.zset)), nil
+}
+
+// ZScore gets the score of a member
+func (f *Fake) ZScore(ctx context.Context, key, member string) (float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	e, err := f.lookupKind(key, kindZSet)
+	if err != nil {
+		return 0, err
+	}
+	if e == nil {
+		return 0, redis.Nil
+	}
+	score, ok := e.zset[member]
+	if !ok {
+		return 0, redis.Nil
+	}
+	return score, nil
+}
+
+// ZIncrBy increments the score of a member, adding it if needed
+func (f *Fake) ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	e, err := f.create(key, kindZSet)
+	if err != nil {
+		return 0, err
+	}
+	e.zset[member] += increment
+	return e.zset[member], nil
+}
+
+// ZRevRange returns members by rank from highest to lowest score
+func (f *Fake) ZRevRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	zs, err := f.ZRevRangeWithScores(ctx, key, start, stop)
+	return members(zs), err
+}
+
+// ZRevRangeWithScores returns members and scores by rank from highest to
+// lowest score
+func (f *Fake) ZRevRangeWithScores(ctx context.Context, key string, start, stop int64) ([]redis.Z, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return nil, err
+	}
+
+	e, err := f.lookupKind(key, kindZSet)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []redis.Z{}
+	if e == nil {
+		return result, nil
+	}
+	sorted := reverseZ(sortedZ(e.zset))
+	if from, to, ok := rangeIndexes(start, stop, len(sorted)); ok {
+		result = append(result, sorted[from:to]...)
+	}
+	return result, nil
+}
+
+// ZRangeByScore returns members with scores between opt.Min and opt.Max
+func (f *Fake) ZRangeByScore(ctx context.Context, key string, opt *redis.ZRangeBy) ([]string, error) {
+	zs, err := f.zrangeByScore(key, opt, false)
+	return members(zs), err
+}
+
+// ZRangeByScoreWithScores returns members and scores between opt.Min and opt.Max
+func (f *Fake) ZRangeByScoreWithScores(ctx context.Context, key string, opt *redis.ZRangeBy) ([]redis.Z, error) {
+	return f.zrangeByScore(key, opt, false)
+}
+
+// ZRevRangeByScore returns members with scores between opt.Max and opt.Min,
+// from highest to lowest
+func (f *Fake) ZRevRangeByScore(ctx context.Context, key string, opt *redis.ZRangeBy) ([]string, error) {
+	zs, err := f.zrangeByScore(key, opt, true)
+	return members(zs), err
+}
+
+// ZRank returns the rank of a member, lowest score first
+func (f *Fake) ZRank(ctx context.Context, key, member string) (int64, error) {
+	return f.zrank(key, member, false)
+}
+
+// ZRevRank returns the rank of a member, highest score first
+func (f *Fake) ZRevRank(ctx context.Context, key, member string) (int64, error) {
+	return f.zrank(key, member, true)
+}
+
+// ZCount counts members with scores between min and max
+func (f *Fake) ZCount(ctx context.Context, key, min, max string) (int64, error) {
+	zs, err := f.zrangeByScore(key, &redis.ZRangeBy{Min: min, Max: max}, false)
+	return int64(len(zs)), err
+}
+
+// ZRemRangeByScore removes members with scores between min and max
+func (f *Fake) ZRemRangeByScore(ctx context.Context, key, min, max string) (int64, error) {
+	zs, err := f.zrangeByScore(key, &redis.ZRangeBy{Min: min, Max: max}, false)
+	if err != nil {
+		return 0, err
+	}
+	return f.ZRem(ctx, key, memberArgs(zs)...)
+}
+
+// ZRemRangeByRank removes members by rank from start to stop, inclusive
+func (f *Fake) ZRemRangeByRank(ctx context.Context, key string, start, stop int64) (int64, error) {
+	zs, err := f.ZRangeWithScores(ctx, key, start, stop)
+	if err != nil {
+		return 0, err
+	}
+	return f.ZRem(ctx, key, memberArgs(zs)...)
+}
+
+// zrangeByScore returns members in a score range, applying opt's LIMIT like
+// go-redis does when Offset or Count is set
+func (f *Fake) zrangeByScore(key string, opt *redis.ZRangeBy, rev bool) ([]redis.Z, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return nil, err
+	}
+	if opt == nil {
+		return nil, fmt.Errorf("range options are required")
+	}
+
+	min, minExcl, err := parseScoreBound(opt.Min)
+	if err != nil {
+		return nil, err
+	}
+	max, maxExcl, err := parseScoreBound(opt.Max)
+	if err != nil {
+		return nil, err
+	}
+	e, err := f.lookupKind(key, kindZSet)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []redis.Z{}
+	if e == nil {
+		return result, nil
+	}
+	sorted := sortedZ(e.zset)
+	if rev {
+		sorted = reverseZ(sorted)
+	}
+	for _, z := range sorted {
+		if z.Score < min || (minExcl && z.Score == min) || z.Score > max || (maxExcl && z.Score == max) {
+			continue
+		}
+		result = append(result, z)
+	}
+
+	if opt.Offset != 0 || opt.Count != 0 {
+		if opt.Offset < 0 || opt.Offset >= int64(len(result)) {
+			return []redis.Z{}, nil
+		}
+		result = result[opt.Offset:]
+		if opt.Count >= 0 && opt.Count < int64(len(result)) {
+			result = result[:opt.Count]
+		}
+	}
+	return result, nil
+}
+
+// zrank returns the rank of a member
+func (f *Fake) zrank(key, member string, rev bool) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	e, err := f.lookupKind(key, kindZSet)
+	if err != nil {
+		return 0, err
+	}
+	if e == nil {
+		return 0, redis.Nil
+	}
+	sorted := sortedZ(e.zset)
+	if rev {
+		sorted = reverseZ(sorted)
+	}
+	for i, z := range sorted {
+		if z.Member == member {
+			return int64(i), nil
+		}
+	}
+	return 0, redis.Nil
+}
+
+// parseScoreBound parses a score range bound such as "1.5", "(1.5", "-inf"
+// or "+inf"
+func parseScoreBound(bound string) (float64, bool, error) {
+	exclusive := strings.HasPrefix(bound, "(")
+	bound = strings.TrimPrefix(bound, "(")
+
+	switch bound {
+	case "-inf":
+		return math.Inf(-1), exclusive, nil
+	case "+inf", "inf":
+		return math.Inf(1), exclusive, nil
+	}
+	score, err := strconv.ParseFloat(bound, 64)
+	if err != nil {
+		return 0, false, errMinMax
+	}
+	return score, exclusive, nil
+}
+
+// members returns the member names of sorted set entries
+func members(zs []redis.Z) []string {
+	if zs == nil {
+		return nil
+	}
+	names := make([]string, len(zs))
+	for i, z := range zs {
+		names[i] = z.Member.(string)
+	}
+	return names
+}
+
+// memberArgs returns the members of sorted set entries as arguments
+func memberArgs(zs []redis.Z) []interface{} {
+	args := make([]interface{}, len(zs))
+	for i, z := range zs {
+		args[i] = z.Member
+	}
+	return args
+}
+
+// reverseZ reverses sorted set entries in place
+func reverseZ(zs []redis.Z) []redis.Z {
+	for i, j := 0, len(zs)-1; i < j; i, j = i+1, j-1 {
+		zs[i], zs[j] = zs[j], zs[i]
+	}
+	return zs
+}
+
+// zrange returns a rank range of a sorted set. Callers hold f.mu.
+func (f *Fake) zrange(key string, start, stop int64) ([]redis.Z, error) {
+	e, err := f.lookupKind(key, kindZSet)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []redis.Z{}
+	if e == nil {
+		return result, nil
+	}
+	sorted := sortedZ(e.zset)
+	if from, to, ok := rangeIndexes(start, stop, len(sorted)); ok {
+		result = append(result, sorted[from:to]...)
+	}
+	return result, nil
+}
+
+// sortedZ orders sorted set members by score and then member
+func sortedZ(zset map[string]float64) []redis.Z {
+	zs := make([]redis.Z, 0, len(zset))
+	for member, score := range zset {
+		zs = append(zs, redis.Z{Score: score, Member: member})
+	}
+	sort.Slice(zs, func(i, j int) bool {
+		if zs[i].Score != zs[j].Score {
+			return zs[i].Score < zs[j].Score
+		}
+		return zs[i].Member.(string) < zs[j].Member.(string)
+	})
+	return zs
+}
+
+// SInter returns the members present in all of the given sets, sorted
+func (f *Fake) SInter(ctx context.Context, keys ...string) ([]string, error) {
+	return f.combine(keys, setInter)
+}
+
+// SUnion returns the members present in any of the given sets, sorted
+func (f *Fake) SUnion(ctx context.Context, keys ...string) ([]string, error) {
+	return f.combine(keys, setUnion)
+}
+
+// SDiff returns the members of the first set that are in none of the others, sorted
+func (f *Fake) SDiff(ctx context.Context, keys ...string) ([]string, error) {
+	return f.combine(keys, setDiff)
+}
+
+// SPop removes and returns a random member
+func (f *Fake) SPop(ctx context.Context, key string) (string, error) {
+	return f.randomMember(key, true)
+}
+
+// SRandMember returns a random member without removing it
+func (f *Fake) SRandMember(ctx context.Context, key string) (string, error) {
+	return f.randomMember(key, false)
+}
+
+// SMIsMember checks membership of several members at once
+func (f *Fake) SMIsMember(ctx context.Context, key string, members ...interface{}) ([]bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return nil, err
+	}
+
+	args, err := formatArgs(flattenArgs(members))
+	if err != nil {
+		return nil, err
+	}
+	e, err := f.lookupKind(key, kindSet)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]bool, len(args))
+	if e != nil {
+		for i, member := range args {
+			_, result[i] = e.set[member]
+		}
+	}
+	return result, nil
+}
+
+// setOp is a set combination
+type setOp int
+
+const (
+	setInter setOp = iota
+	setUnion
+	setDiff
+)
+
+// combine applies a set operation to the given sets. Missing keys are empty sets.
+func (f *Fake) combine(keys []string, op setOp) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, redisclient.ErrInvalidKey
+	}
+
+	sets := make([]map[string]struct{}, len(keys))
+	for i, key := range keys {
+		e, err := f.lookupKind(key, kindSet)
+		if err != nil {
+			return nil, err
+		}
+		if e != nil {
+			sets[i] = e.set
+		}
+	}
+
+	result := make(map[string]struct{})
+	for member := range sets[0] {
+		result[member] = struct{}{}
+	}
+	for _, set := range sets[1:] {
+		switch op {
+		case setInter:
+			for member := range result {
+				if _, ok := set[member]; !ok {
+					delete(result, member)
+				}
+			}
+		case setUnion:
+			for member := range set {
+				result[member] = struct{}{}
+			}
+		case setDiff:
+			for member := range set {
+				delete(result, member)
+			}
+		}
+	}
+
+	members := make([]string, 0, len(result))
+	for member := range result {
+		members = append(members, member)
+	}
+	sort.Strings(members)
+	return members, nil
+}
+
+// randomMember returns and optionally removes a random set member
+func (f *Fake) randomMember(key string, remove bool) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return "", err
+	}
+
+	e, err := f.lookupKind(key, kindSet)
+	if err != nil {
+		return "", err
+	}
+	if e == nil {
+		return "", redis.Nil
+	}
+
+	n := rand.Intn(len(e.set))
+	for member := range e.set {
+		if n > 0 {
+			n--
+			continue
+		}
+		if remove {
+			delete(e.set, member)
+			f.dropEmpty(key, e)
+		}
+		return member, nil
+	}
+	return "", redis.Nil
+}
@@ -0,0 +1,310 @@
+package redistest
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	redisclient "github.com/isimtekin/go-packages/redis-client"
+	"github.com/redis/go-redis/v9"
+)
+
+// ====================
+// String Operations
+// ====================
+
+// Get retrieves the value of a key
+func (f *Fake) Get(ctx context.Context, key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return "", err
+	}
+
+	e, err := f.lookupKind(key, kindString)
+	if err != nil {
+		return "", err
+	}
+	if e == nil {
+		return "", redis.Nil
+	}
+	return e.str, nil
+}
+
+// Set sets the value of a key. A positive TTL expires the key, redis.KeepTTL
+// keeps the current expiry and anything else removes it.
+func (f *Fake) Set(ctx context.Context, key string, value interface{}, ttl ...time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return err
+	}
+
+	var duration time.Duration
+	if len(ttl) > 0 {
+		duration = ttl[0]
+	}
+	return f.set(key, value, duration)
+}
+
+// SetNX sets the value of a key only if it does not exist
+func (f *Fake) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return false, err
+	}
+
+	if f.lookup(key) != nil {
+		return false, nil
+	}
+	if err := f.set(key, value, ttl); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetEX sets the value and expiration of a key
+func (f *Fake) SetEX(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return err
+	}
+
+	if ttl <= 0 {
+		return redisclient.ErrInvalidTTL
+	}
+
+	return f.set(key, value, ttl)
+}
+
+// GetSet sets a new value and returns the old value
+func (f *Fake) GetSet(ctx context.Context, key string, value interface{}) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return "", err
+	}
+
+	old, err := f.lookupKind(key, kindString)
+	if err != nil {
+		return "", err
+	}
+	if err := f.set(key, value, 0); err != nil {
+		return "", err
+	}
+	if old == nil {
+		return "", redis.Nil
+	}
+	return old.str, nil
+}
+
+// MGet retrieves values of multiple keys. Missing keys and keys that are not
+// strings are nil.
+func (f *Fake) MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(); err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		if e, err := f.lookupKind(key, kindString); err == nil && e != nil {
+			values[i] = e.str
+		}
+	}
+	return values, nil
+}
+
+// MSet sets multiple key-value pairs
+func (f *Fake) MSet(ctx context.Context, values ...interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check(); err != nil {
+		return err
+	}
+
+	args, err := pairs(values)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(args); i += 2 {
+		f.data[args[i]] = &entry{kind: kindString, str: args[i+1]}
+	}
+	return nil
+}
+
+// Incr increments the integer value of a key by one
+func (f *Fake) Incr(ctx context.Context, key string) (int64, error) {
+	return f.IncrBy(ctx, key, 1)
+}
+
+// IncrBy increments the integer value of a key by the given amount
+func (f *Fake) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	e, err := f.lookupKind(key, kindString)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	if e == nil {
+		e = &entry{kind: kindString}
+		f.data[key] = e
+	} else if n, err = strconv.ParseInt(e.str, 10, 64); err != nil {
+		return 0, errNotInt
+	}
+	n += value
+	e.str = strconv.FormatInt(n, 10)
+	return n, nil
+}
+
+// Decr decrements the integer value of a key by one
+func (f *Fake) Decr(ctx context.Context, key string) (int64, error) {
+	return f.IncrBy(ctx, key, -1)
+}
+
+// DecrBy decrements the integer value of a key by the given amount
+func (f *Fake) DecrBy(ctx context.Context, key string, value int64) (int64, error) {
+	return f.IncrBy(ctx, key, -value)
+}
+
+// set stores a string value. Callers hold f.mu.
+func (f *Fake) set(key string, value interface{}, ttl time.Duration) error {
+	s, err := formatArg(value)
+	if err != nil {
+		return err
+	}
+
+	e := &entry{kind: kindString, str: s}
+	switch {
+	case ttl > 0:
+		e.expireAt = f.now.Add(ttl.Truncate(time.Millisecond))
+	case ttl == redis.KeepTTL:
+		if old := f.lookup(key); old != nil {
+			e.expireAt = old.expireAt
+		}
+	}
+	f.data[key] = e
+	return nil
+}
+
+// GetDel gets the value of a key and deletes it
+func (f *Fake) GetDel(ctx context.Context, key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return "", err
+	}
+
+	e, err := f.lookupKind(key, kindString)
+	if err != nil {
+		return "", err
+	}
+	if e == nil {
+		return "", redis.Nil
+	}
+	delete(f.data, key)
+	return e.str, nil
+}
+
+// GetEx gets the value of a key and sets its TTL. A ttl of 0 leaves the
+// expiration unchanged.
+func (f *Fake) GetEx(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return "", err
+	}
+
+	if ttl < 0 {
+		return "", redisclient.ErrInvalidTTL
+	}
+	e, err := f.lookupKind(key, kindString)
+	if err != nil {
+		return "", err
+	}
+	if e == nil {
+		return "", redis.Nil
+	}
+	if ttl > 0 {
+		e.expireAt = f.now.Add(ttl.Truncate(time.Millisecond))
+	}
+	return e.str, nil
+}
+
+// IncrByFloat increments the float value of a key by the given amount
+func (f *Fake) IncrByFloat(ctx context.Context, key string, value float64) (float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	e, err := f.lookupKind(key, kindString)
+	if err != nil {
+		return 0, err
+	}
+
+	var n float64
+	if e == nil {
+		e = &entry{kind: kindString}
+		f.data[key] = e
+	} else if n, err = strconv.ParseFloat(e.str, 64); err != nil {
+		return 0, errNotFloat
+	}
+	n += value
+	e.str = strconv.FormatFloat(n, 'f', -1, 64)
+	return n, nil
+}
+
+// Append appends a value to a string and returns its new length
+func (f *Fake) Append(ctx context.Context, key, value string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	e, err := f.create(key, kindString)
+	if err != nil {
+		return 0, err
+	}
+	e.str += value
+	return int64(len(e.str)), nil
+}
+
+// StrLen returns the length of a string value
+func (f *Fake) StrLen(ctx context.Context, key string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	e, err := f.lookupKind(key, kindString)
+	if err != nil || e == nil {
+		return 0, err
+	}
+	return int64(len(e.str)), nil
+}
@@ -0,0 +1,401 @@
+package redisclient
+
+import (
+	"context"
+	"iter"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ====================
+// Scan Operations
+// ====================
+
+// ScanOptions configures a cursor-based scan
+type ScanOptions struct {
+	Match string // Glob-style pattern (MATCH), empty matches everything
+	Count int64  // Work hint per call (COUNT), also the batch size of bulk helpers
+	Type  string // Key type filter (TYPE), SCAN only: "string", "hash", "list", "set", "zset", "stream"
+}
+
+// DefaultScanOptions returns the default scan options
+func DefaultScanOptions() ScanOptions {
+	return ScanOptions{
+		Count: 100,
+	}
+}
+
+// ScanOption is a functional option for configuring a scan
+type ScanOption func(*ScanOptions)
+
+// WithScanMatch sets the MATCH pattern
+func WithScanMatch(pattern string) ScanOption {
+	return func(o *ScanOptions) {
+		o.Match = pattern
+	}
+}
+
+// WithScanCount sets the COUNT hint
+func WithScanCount(count int64) ScanOption {
+	return func(o *ScanOptions) {
+		o.Count = count
+	}
+}
+
+// WithScanType restricts SCAN to keys of one type
+func WithScanType(keyType string) ScanOption {
+	return func(o *ScanOptions) {
+		o.Type = keyType
+	}
+}
+
+// FieldValue is a hash field returned by HScanIter
+type FieldValue struct {
+	Field string
+	Value string
+}
+
+// scanPage fetches one page of a scan and returns the next cursor
+type scanPage[T any] func(ctx context.Context, cursor uint64) ([]T, uint64, error)
+
+// ScanIterator walks a keyspace or collection with a cursor, one page per
+// round trip, without blocking Redis the way KEYS or HGETALL can. Items may be
+// returned more than once if the data changes during the scan.
+type ScanIterator[T any] struct {
+	ctx     context.Context
+	sources func(ctx context.Context) ([]scanPage[T], error)
+	err     error
+}
+
+// All returns a sequence of every item. Check Err after the loop.
+func (it *ScanIterator[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for batch := range it.Batches() {
+			for _, item := range batch {
+				if !yield(item) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Batches returns a sequence of pages as returned by Redis. Check Err after the loop.
+func (it *ScanIterator[T]) Batches() iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		it.err = nil
+
+		sources, err := it.sources(it.ctx)
+		if err != nil {
+			it.err = err
+			return
+		}
+
+		for _, fetch := range sources {
+			var cursor uint64
+			for {
+				if err := it.ctx.Err(); err != nil {
+					it.err = err
+					return
+				}
+
+				items, next, err := fetch(it.ctx, cursor)
+				if err != nil {
+					it.err = err
+					return
+				}
+
+				if len(items) > 0 && !yield(items) {
+					return
+				}
+
+				if next == 0 {
+					break
+				}
+				cursor = next
+			}
+		}
+	}
+}
+
+// singleSource scans one cursor
+func singleSource[T any](fetch scanPage[T]) func(ctx context.Context) ([]scanPage[T], error) {
+	return func(ctx context.Context) ([]scanPage[T], error) {
+		return []scanPage[T]{fetch}, nil
+	}
+}
+
+// ForEachBatch calls fn for every page and stops at the first error
+func (it *ScanIterator[T]) ForEachBatch(fn func(batch []T) error) error {
+	for batch := range it.Batches() {
+		if err := fn(batch); err != nil {
+			return err
+		}
+	}
+	return it.err
+}
+
+// Collect returns every item. Use it only for scans known to be small.
+func (it *ScanIterator[T]) Collect() ([]T, error) {
+	var items []T
+	for item := range it.All() {
+		items = append(items, item)
+	}
+	return items, it.err
+}
+
+// Err returns the error that stopped the last iteration, if any
+func (it *ScanIterator[T]) Err() error {
+	return it.err
+}
+
+// ScanIter iterates over keys with SCAN. In cluster mode every master is
+// scanned in turn. With a key prefix only keys under it are returned, without
+// the prefix.
+func (c *Client) ScanIter(ctx context.Context, opts ...ScanOption) *ScanIterator[string] {
+	o := scanOptions(opts)
+
+	page := func(node redis.Cmdable) scanPage[string] {
+		return func(ctx context.Context, cursor uint64) ([]string, uint64, error) {
+			c.mu.RLock()
+			defer c.mu.RUnlock()
+
+			if c.closed {
+				return nil, 0, ErrClientClosed
+			}
+
+			if c.config.KeyPrefix == "" {
+				if o.Type != "" {
+					return node.ScanType(ctx, cursor, o.Match, o.Count, o.Type).Result()
+				}
+				return node.Scan(ctx, cursor, o.Match, o.Count).Result()
+			}
+
+			// Cluster nodes have no prefix hook, so the prefix is handled here
+			var cmd *redis.ScanCmd
+			if o.Type != "" {
+				cmd = node.ScanType(withRawKeys(ctx), cursor, c.prefixPattern(o.Match), o.Count, o.Type)
+			} else {
+				cmd = node.Scan(withRawKeys(ctx), cursor, c.prefixPattern(o.Match), o.Count)
+			}
+			keys, next, err := cmd.Result()
+			for i, key := range keys {
+				keys[i] = c.StripPrefix(key)
+			}
+			return keys, next, err
+		}
+	}
+
+	return &ScanIterator[string]{
+		ctx: ctx,
+		sources: func(ctx context.Context) ([]scanPage[string], error) {
+			nodes, err := c.scanNodes(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			pages := make([]scanPage[string], len(nodes))
+			for i, node := range nodes {
+				pages[i] = page(node)
+			}
+			return pages, nil
+		},
+	}
+}
+
+// scanNodes returns the nodes holding keys: every master in cluster mode,
+// otherwise the client itself
+func (c *Client) scanNodes(ctx context.Context) ([]redis.Cmdable, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	cluster, ok := c.client.(*redis.ClusterClient)
+	if !ok {
+		return []redis.Cmdable{c.client}, nil
+	}
+
+	var (
+		mu    sync.Mutex
+		nodes []redis.Cmdable
+	)
+	err := cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+		mu.Lock()
+		nodes = append(nodes, node)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// HScanIter iterates over the fields of a hash with HSCAN
+func (c *Client) HScanIter(ctx context.Context, key string, opts ...ScanOption) *ScanIterator[FieldValue] {
+	o := scanOptions(opts)
+
+	return &ScanIterator[FieldValue]{
+		ctx: ctx,
+		sources: singleSource(func(ctx context.Context, cursor uint64) ([]FieldValue, uint64, error) {
+			raw, next, err := c.scanCollection(ctx, key, cursor, func(cmd redis.Cmdable) *redis.ScanCmd {
+				return cmd.HScan(ctx, key, cursor, o.Match, o.Count)
+			})
+			if err != nil {
+				return nil, 0, err
+			}
+
+			fields := make([]FieldValue, 0, len(raw)/2)
+			for i := 0; i+1 < len(raw); i += 2 {
+				fields = append(fields, FieldValue{Field: raw[i], Value: raw[i+1]})
+			}
+			return fields, next, nil
+		}),
+	}
+}
+
+// SScanIter iterates over the members of a set with SSCAN
+func (c *Client) SScanIter(ctx context.Context, key string, opts ...ScanOption) *ScanIterator[string] {
+	o := scanOptions(opts)
+
+	return &ScanIterator[string]{
+		ctx: ctx,
+		sources: singleSource(func(ctx context.Context, cursor uint64) ([]string, uint64, error) {
+			return c.scanCollection(ctx, key, cursor, func(cmd redis.Cmdable) *redis.ScanCmd {
+				return cmd.SScan(ctx, key, cursor, o.Match, o.Count)
+			})
+		}),
+	}
+}
+
+// ZScanIter iterates over the members and scores of a sorted set with ZSCAN
+func (c *Client) ZScanIter(ctx context.Context, key string, opts ...ScanOption) *ScanIterator[redis.Z] {
+	o := scanOptions(opts)
+
+	return &ScanIterator[redis.Z]{
+		ctx: ctx,
+		sources: singleSource(func(ctx context.Context, cursor uint64) ([]redis.Z, uint64, error) {
+			raw, next, err := c.scanCollection(ctx, key, cursor, func(cmd redis.Cmdable) *redis.ScanCmd {
+				return cmd.ZScan(ctx, key, cursor, o.Match, o.Count)
+			})
+			if err != nil {
+				return nil, 0, err
+			}
+
+			members := make([]redis.Z, 0, len(raw)/2)
+			for i := 0; i+1 < len(raw); i += 2 {
+				score, err := strconv.ParseFloat(raw[i+1], 64)
+				if err != nil {
+					return nil, 0, err
+				}
+				members = append(members, redis.Z{Member: raw[i], Score: score})
+			}
+			return members, next, nil
+		}),
+	}
+}
+
+// scanCollection runs one HSCAN, SSCAN or ZSCAN call
+func (c *Client) scanCollection(ctx context.Context, key string, cursor uint64, scan func(redis.Cmdable) *redis.ScanCmd) ([]string, uint64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, 0, ErrClientClosed
+	}
+
+	if key == "" {
+		return nil, 0, ErrInvalidKey
+	}
+
+	return scan(c.client).Result()
+}
+
+// DeleteByPattern deletes every key matching pattern using SCAN and pipelined
+// UNLINK, one pipeline per page. It returns the number of keys deleted.
+func (c *Client) DeleteByPattern(ctx context.Context, pattern string, opts ...ScanOption) (int64, error) {
+	if pattern == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.forEachKeyBatch(ctx, pattern, opts, func(pipe redis.Pipeliner, key string) redis.Cmder {
+		return pipe.Unlink(ctx, key)
+	})
+}
+
+// ExpireByPattern sets a TTL on every key matching pattern using SCAN and
+// pipelined EXPIRE. It returns the number of keys updated.
+func (c *Client) ExpireByPattern(ctx context.Context, pattern string, ttl time.Duration, opts ...ScanOption) (int64, error) {
+	if pattern == "" {
+		return 0, ErrInvalidKey
+	}
+	if ttl <= 0 {
+		return 0, ErrInvalidTTL
+	}
+
+	return c.forEachKeyBatch(ctx, pattern, opts, func(pipe redis.Pipeliner, key string) redis.Cmder {
+		return pipe.Expire(ctx, key, ttl)
+	})
+}
+
+// forEachKeyBatch scans keys matching pattern and runs one pipelined command
+// per key, page by page. Single-key commands keep it safe for Redis Cluster.
+func (c *Client) forEachKeyBatch(ctx context.Context, pattern string, opts []ScanOption, command func(redis.Pipeliner, string) redis.Cmder) (int64, error) {
+	opts = append(opts, WithScanMatch(pattern))
+
+	var total int64
+	err := c.ScanIter(ctx, opts...).ForEachBatch(func(keys []string) error {
+		n, err := c.pipelineKeys(ctx, keys, command)
+		total += n
+		return err
+	})
+	return total, err
+}
+
+// pipelineKeys runs a command for each key in one pipeline and counts the keys affected
+func (c *Client) pipelineKeys(ctx context.Context, keys []string, command func(redis.Pipeliner, string) redis.Cmder) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	pipe := c.client.Pipeline()
+	cmds := make([]redis.Cmder, len(keys))
+	for i, key := range keys {
+		cmds[i] = command(pipe, key)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && !IsNil(err) {
+		return 0, err
+	}
+
+	var total int64
+	for _, cmd := range cmds {
+		switch cmd := cmd.(type) {
+		case *redis.IntCmd:
+			total += cmd.Val()
+		case *redis.BoolCmd:
+			if cmd.Val() {
+				total++
+			}
+		}
+	}
+	return total, nil
+}
+
+// scanOptions applies scan options to the defaults
+func scanOptions(opts []ScanOption) ScanOptions {
+	o := DefaultScanOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,108 @@
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestScanIterator tests paging, early exit and error reporting
+func TestScanIterator(t *testing.T) {
+	pages := map[uint64][]string{0: {"a", "b"}, 7: {}, 9: {"c"}}
+	next := map[uint64]uint64{0: 7, 7: 9, 9: 0}
+
+	it := &ScanIterator[string]{
+		ctx: context.Background(),
+		sources: singleSource(func(ctx context.Context, cursor uint64) ([]string, uint64, error) {
+			return pages[cursor], next[cursor], nil
+		}),
+	}
+
+	items, err := it.Collect()
+	if err != nil || len(items) != 3 || items[2] != "c" {
+		t.Errorf("Collect() = %v, %v", items, err)
+	}
+
+	var batches int
+	if err := it.ForEachBatch(func(batch []string) error { batches++; return nil }); err != nil {
+		t.Errorf("ForEachBatch() error = %v", err)
+	}
+	if batches != 2 {
+		t.Errorf("batches = %d, want 2 (empty pages are skipped)", batches)
+	}
+
+	for item := range it.All() {
+		if item != "a" {
+			t.Errorf("first item = %q, want a", item)
+		}
+		break
+	}
+
+	stop := errors.New("stop")
+	if err := it.ForEachBatch(func(batch []string) error { return stop }); err != stop {
+		t.Errorf("ForEachBatch() error = %v, want callback error", err)
+	}
+
+	failing := &ScanIterator[string]{
+		ctx: context.Background(),
+		sources: singleSource(func(ctx context.Context, cursor uint64) ([]string, uint64, error) {
+			return nil, 0, ErrClientClosed
+		}),
+	}
+	for range failing.All() {
+		t.Error("no items expected")
+	}
+	if failing.Err() != ErrClientClosed {
+		t.Errorf("Err() = %v, want ErrClientClosed", failing.Err())
+	}
+}
+
+// TestScan_ClosedClient tests scans on a closed client
+func TestScan_ClosedClient(t *testing.T) {
+	client := &Client{config: DefaultConfig(), closed: true}
+	ctx := context.Background()
+
+	if _, err := client.ScanIter(ctx, WithScanMatch("user:*")).Collect(); err != ErrClientClosed {
+		t.Errorf("ScanIter() error = %v, want ErrClientClosed", err)
+	}
+	if _, err := client.HScanIter(ctx, "user:1").Collect(); err != ErrClientClosed {
+		t.Errorf("HScanIter() error = %v, want ErrClientClosed", err)
+	}
+	if _, err := client.DeleteByPattern(ctx, "user:*"); err != ErrClientClosed {
+		t.Errorf("DeleteByPattern() error = %v, want ErrClientClosed", err)
+	}
+}
+
+// TestScan_InvalidInput tests validation of keys, patterns and TTLs
+func TestScan_InvalidInput(t *testing.T) {
+	client := &Client{config: DefaultConfig()}
+	ctx := context.Background()
+
+	if _, err := client.SScanIter(ctx, "").Collect(); err != ErrInvalidKey {
+		t.Errorf("SScanIter() error = %v, want ErrInvalidKey", err)
+	}
+	if _, err := client.ZScanIter(ctx, "").Collect(); err != ErrInvalidKey {
+		t.Errorf("ZScanIter() error = %v, want ErrInvalidKey", err)
+	}
+	if _, err := client.DeleteByPattern(ctx, ""); err != ErrInvalidKey {
+		t.Errorf("DeleteByPattern() error = %v, want ErrInvalidKey", err)
+	}
+	if _, err := client.ExpireByPattern(ctx, "user:*", 0); err != ErrInvalidTTL {
+		t.Errorf("ExpireByPattern() error = %v, want ErrInvalidTTL", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := client.ExpireByPattern(cancelled, "user:*", time.Minute); err != context.Canceled {
+		t.Errorf("ExpireByPattern() with cancelled context error = %v, want context.Canceled", err)
+	}
+}
+
+// TestScanOptions tests option defaults
+func TestScanOptions(t *testing.T) {
+	o := scanOptions([]ScanOption{WithScanMatch("a:*"), WithScanType("hash")})
+	if o.Match != "a:*" || o.Type != "hash" || o.Count != 100 {
+		t.Errorf("scanOptions() = %+v", o)
+	}
+}
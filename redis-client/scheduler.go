@@ -0,0 +1,356 @@
+package redisclient
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ====================
+// Distributed Scheduler
+// ====================
+
+// claimRunScript records a run only if the last run time is unchanged, so a
+// slot runs once even if two replicas briefly both believe they lead
+// KEYS[1] = runs hash, ARGV[1] = job, ARGV[2] = expected last run ("" if none), ARGV[3] = new run
+var claimRunScript = redis.NewScript(`
+local last = redis.call("HGET", KEYS[1], ARGV[1])
+if (last == false and ARGV[2] == "") or last == ARGV[2] then
+	redis.call("HSET", KEYS[1], ARGV[1], ARGV[3])
+	return 1
+end
+return 0
+`)
+
+// ScheduledFunc is a scheduled job. Its context is cancelled when the
+// scheduler stops or this replica loses leadership.
+type ScheduledFunc func(ctx context.Context) error
+
+// SchedulerOptions configures a Scheduler
+type SchedulerOptions struct {
+	TickInterval time.Duration    // How often due jobs are checked
+	Location     *time.Location   // Time zone for cron expressions (default: time.Local)
+	Election     []ElectionOption // Options for the scheduler's leader elector
+	ErrorHandler func(err error)  // Called for job and bookkeeping errors
+}
+
+// DefaultSchedulerOptions returns the default scheduler options
+func DefaultSchedulerOptions() SchedulerOptions {
+	return SchedulerOptions{
+		TickInterval: time.Second,
+	}
+}
+
+// SchedulerOption is a functional option for configuring a Scheduler
+type SchedulerOption func(*SchedulerOptions)
+
+// WithSchedulerTick sets how often due jobs are checked
+func WithSchedulerTick(d time.Duration) SchedulerOption {
+	return func(o *SchedulerOptions) {
+		o.TickInterval = d
+	}
+}
+
+// WithSchedulerLocation sets the time zone for cron expressions
+func WithSchedulerLocation(loc *time.Location) SchedulerOption {
+	return func(o *SchedulerOptions) {
+		o.Location = loc
+	}
+}
+
+// WithSchedulerElection sets options for the scheduler's leader elector
+func WithSchedulerElection(opts ...ElectionOption) SchedulerOption {
+	return func(o *SchedulerOptions) {
+		o.Election = append(o.Election, opts...)
+	}
+}
+
+// WithSchedulerErrorHandler sets the callback for background errors
+func WithSchedulerErrorHandler(fn func(err error)) SchedulerOption {
+	return func(o *SchedulerOptions) {
+		o.ErrorHandler = fn
+	}
+}
+
+// scheduledJob is a registered job and its run state
+type scheduledJob struct {
+	name     string
+	schedule Schedule
+	fn       ScheduledFunc
+	running  bool
+}
+
+// Scheduler runs cron-like jobs on exactly one replica. Replicas elect a
+// leader, and only the leader runs due jobs. Last run times are stored in
+// Redis, so a new leader continues the schedule where the previous one
+// stopped; runs missed while no replica was leading are made up with a single
+// run.
+type Scheduler struct {
+	client  *Client
+	name    string
+	elector *LeaderElector
+	opts    SchedulerOptions
+
+	mu      sync.Mutex
+	jobs    map[string]*scheduledJob
+	running bool
+}
+
+// NewScheduler creates a scheduler. Replicas that share a name share jobs,
+// the leader lease "<name>:leader" and the run times in "<name>:runs".
+func (c *Client) NewScheduler(name string, opts ...SchedulerOption) (*Scheduler, error) {
+	if name == "" {
+		return nil, ErrInvalidKey
+	}
+
+	options := DefaultSchedulerOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.TickInterval <= 0 {
+		return nil, fmt.Errorf("tick interval must be positive")
+	}
+
+	elector, err := c.NewLeaderElector(name+":leader", options.Election...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Scheduler{
+		client:  c,
+		name:    name,
+		elector: elector,
+		opts:    options,
+		jobs:    make(map[string]*scheduledJob),
+	}, nil
+}
+
+// Elector returns the scheduler's leader elector
+func (s *Scheduler) Elector() *LeaderElector {
+	return s.elector
+}
+
+// Add registers a job with a schedule. Job names must be unique.
+func (s *Scheduler) Add(name string, schedule Schedule, fn ScheduledFunc) error {
+	if name == "" {
+		return fmt.Errorf("job name cannot be empty")
+	}
+	if schedule == nil {
+		return fmt.Errorf("schedule cannot be nil")
+	}
+	if fn == nil {
+		return fmt.Errorf("job function cannot be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[name]; ok {
+		return fmt.Errorf("job %q is already registered", name)
+	}
+	s.jobs[name] = &scheduledJob{name: name, schedule: schedule, fn: fn}
+	return nil
+}
+
+// Cron registers a job with a cron expression, see ParseCron
+func (s *Scheduler) Cron(name, spec string, fn ScheduledFunc) error {
+	schedule, err := ParseCron(spec, s.opts.Location)
+	if err != nil {
+		return err
+	}
+	return s.Add(name, schedule, fn)
+}
+
+// Every registers a job that runs at a fixed interval
+func (s *Scheduler) Every(name string, interval time.Duration, fn ScheduledFunc) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	return s.Add(name, Every(interval), fn)
+}
+
+// LastRun returns when a job last ran on any replica, or ErrNil if it never ran
+func (s *Scheduler) LastRun(ctx context.Context, job string) (time.Time, error) {
+	value, err := s.client.HGet(ctx, s.runsKey(), job)
+	if err != nil {
+		return time.Time{}, err
+	}
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid last run of %q: %w", job, err)
+	}
+	return time.UnixMilli(ms), nil
+}
+
+// Run takes part in the leader election and, while leading, runs due jobs
+// until ctx is cancelled. On return it waits for running jobs, which see their
+// context cancelled, and resigns.
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return fmt.Errorf("scheduler is already running")
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	electionDone := make(chan error, 1)
+	go func() {
+		electionDone <- s.elector.Run(ctx)
+	}()
+
+	var jobs sync.WaitGroup
+	defer jobs.Wait()
+
+	ticker := time.NewTicker(s.opts.TickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return <-electionDone
+		case err := <-electionDone:
+			return err
+		case <-ticker.C:
+		}
+
+		if s.elector.IsLeader() {
+			s.runDue(ctx, &jobs)
+		}
+	}
+}
+
+// runDue starts every job whose next run time has passed
+func (s *Scheduler) runDue(ctx context.Context, jobs *sync.WaitGroup) {
+	s.mu.Lock()
+	names := make([]string, 0, len(s.jobs))
+	for name, job := range s.jobs {
+		if !job.running {
+			names = append(names, name)
+		}
+	}
+	s.mu.Unlock()
+	if len(names) == 0 {
+		return
+	}
+	sort.Strings(names)
+
+	last, err := s.client.HMGet(ctx, s.runsKey(), names...)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.reportError(fmt.Errorf("failed to load last run times: %w", err))
+		}
+		return
+	}
+
+	now := time.Now()
+	for i, name := range names {
+		prev, _ := last[i].(string)
+		if !s.claim(ctx, name, prev, now) {
+			continue
+		}
+
+		s.mu.Lock()
+		job := s.jobs[name]
+		job.running = true
+		s.mu.Unlock()
+
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			s.execute(ctx, job)
+		}()
+	}
+}
+
+// claim records now as the job's last run if it is due and no other replica
+// recorded a run in the meantime
+func (s *Scheduler) claim(ctx context.Context, name, prev string, now time.Time) bool {
+	s.mu.Lock()
+	schedule := s.jobs[name].schedule
+	s.mu.Unlock()
+
+	if prev == "" {
+		// First sighting: start the schedule from now
+		if _, err := s.client.runScript(ctx, claimRunScript, []string{s.runsKey()}, name, "", now.UnixMilli()).Result(); err != nil && ctx.Err() == nil {
+			s.reportError(fmt.Errorf("failed to initialize job %q: %w", name, err))
+		}
+		return false
+	}
+
+	ms, err := strconv.ParseInt(prev, 10, 64)
+	if err != nil {
+		s.reportError(fmt.Errorf("invalid last run of %q: %w", name, err))
+		return false
+	}
+	next := schedule.Next(time.UnixMilli(ms))
+	if next.IsZero() || now.Before(next) {
+		return false
+	}
+
+	ok, err := s.client.runScript(ctx, claimRunScript, []string{s.runsKey()}, name, prev, now.UnixMilli()).Int()
+	if err != nil {
+		if ctx.Err() == nil {
+			s.reportError(fmt.Errorf("failed to record run of %q: %w", name, err))
+		}
+		return false
+	}
+	return ok == 1
+}
+
+// execute runs a job until it returns or leadership is lost
+func (s *Scheduler) execute(ctx context.Context, job *scheduledJob) {
+	defer func() {
+		s.mu.Lock()
+		job.running = false
+		s.mu.Unlock()
+	}()
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.elector.Done():
+			cancel()
+		case <-jobCtx.Done():
+		}
+	}()
+
+	if err := s.safeRun(jobCtx, job); err != nil {
+		s.reportError(fmt.Errorf("job %q failed: %w", job.name, err))
+	}
+}
+
+// safeRun runs the job, turning a panic into an error
+func (s *Scheduler) safeRun(ctx context.Context, job *scheduledJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panic: %v", r)
+		}
+	}()
+	return job.fn(ctx)
+}
+
+// runsKey returns the hash holding the last run times
+func (s *Scheduler) runsKey() string {
+	return s.name + ":runs"
+}
+
+// reportError passes a background error to the error handler
+func (s *Scheduler) reportError(err error) {
+	if s.opts.ErrorHandler != nil {
+		s.opts.ErrorHandler(err)
+	}
+}
@@ -0,0 +1,60 @@
+package redisclient
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestScheduler_Add tests job registration
+func TestScheduler_Add(t *testing.T) {
+	client := &Client{config: DefaultConfig()}
+	noop := func(ctx context.Context) error { return nil }
+
+	if _, err := client.NewScheduler(""); err != ErrInvalidKey {
+		t.Errorf("NewScheduler(\"\") error = %v, want %v", err, ErrInvalidKey)
+	}
+	if _, err := client.NewScheduler("jobs", WithSchedulerTick(0)); err == nil {
+		t.Error("NewScheduler() should reject a zero tick")
+	}
+
+	s, err := client.NewScheduler("jobs", WithSchedulerElection(WithElectionID("replica-1")))
+	if err != nil {
+		t.Fatalf("NewScheduler() error = %v", err)
+	}
+	if s.Elector().ID() != "replica-1" {
+		t.Errorf("Elector().ID() = %q, want replica-1", s.Elector().ID())
+	}
+
+	if err := s.Cron("report", "0 6 * * *", noop); err != nil {
+		t.Errorf("Cron() error = %v", err)
+	}
+	if err := s.Every("sync", time.Minute, noop); err != nil {
+		t.Errorf("Every() error = %v", err)
+	}
+	if err := s.Every("report", time.Minute, noop); err == nil {
+		t.Error("registering a duplicate job should fail")
+	}
+	if err := s.Cron("bad", "not a cron", noop); err == nil {
+		t.Error("Cron() should reject an invalid expression")
+	}
+	if err := s.Every("zero", 0, noop); err == nil {
+		t.Error("Every() should reject a zero interval")
+	}
+	if err := s.Add("nil", Every(time.Minute), nil); err == nil {
+		t.Error("Add() should reject a nil function")
+	}
+}
+
+// TestScheduler_RunClosedClient tests that Run stops on a closed client
+func TestScheduler_RunClosedClient(t *testing.T) {
+	client := &Client{config: DefaultConfig(), closed: true}
+	s, err := client.NewScheduler("jobs")
+	if err != nil {
+		t.Fatalf("NewScheduler() error = %v", err)
+	}
+
+	if err := s.Run(context.Background()); err != ErrClientClosed {
+		t.Errorf("Run() error = %v, want %v", err, ErrClientClosed)
+	}
+}
@@ -0,0 +1,273 @@
+package redisclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ====================
+// Lua Scripts
+// ====================
+
+// Script is a named Lua script. It runs with EVALSHA and falls back to EVAL
+// when the server does not have it cached (NOSCRIPT).
+type Script struct {
+	name   string
+	script *redis.Script
+	src    string
+}
+
+// NewScript creates a script from Lua source
+func NewScript(name, src string) *Script {
+	return &Script{name: name, script: redis.NewScript(src), src: src}
+}
+
+// LoadScriptsFS creates a script for every file in fsys matching pattern,
+// named after the file without its extension. It works with embed.FS:
+//
+//	//go:embed scripts/*.lua
+//	var scriptFS embed.FS
+//	scripts, err := redisclient.LoadScriptsFS(scriptFS, "scripts/*.lua")
+func LoadScriptsFS(fsys fs.FS, pattern string) ([]*Script, error) {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script pattern: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no scripts match %q", pattern)
+	}
+
+	scripts := make([]*Script, 0, len(files))
+	for _, file := range files {
+		src, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read script %s: %w", file, err)
+		}
+		name := strings.TrimSuffix(path.Base(file), path.Ext(file))
+		scripts = append(scripts, NewScript(name, string(src)))
+	}
+	return scripts, nil
+}
+
+// Name returns the script name
+func (s *Script) Name() string {
+	return s.name
+}
+
+// Hash returns the SHA1 hash used by EVALSHA
+func (s *Script) Hash() string {
+	return s.script.Hash()
+}
+
+// Source returns the Lua source
+func (s *Script) Source() string {
+	return s.src
+}
+
+// Run executes the script on c
+func (s *Script) Run(ctx context.Context, c *Client, keys []string, args ...interface{}) *ScriptResult {
+	return &ScriptResult{Cmd: c.runScript(ctx, s.script, keys, args...)}
+}
+
+// RunRO executes a read-only script with EVALSHA_RO (Redis 7+), which may be
+// served by replicas
+func (s *Script) RunRO(ctx context.Context, c *Client, keys []string, args ...interface{}) *ScriptResult {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		cmd := redis.NewCmd(ctx)
+		cmd.SetErr(ErrClientClosed)
+		return &ScriptResult{Cmd: cmd}
+	}
+
+	return &ScriptResult{Cmd: s.script.RunRO(ctx, c.client, keys, args...)}
+}
+
+// ScriptResult is the reply of a script. The embedded command provides typed
+// accessors such as Int64, Text, Bool, Float64, StringSlice and Int64Slice.
+type ScriptResult struct {
+	*redis.Cmd
+}
+
+// Decode unmarshals a JSON string reply (e.g. from cjson.encode) into v
+func (r *ScriptResult) Decode(v interface{}) error {
+	s, err := r.Text()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(s), v)
+}
+
+// ScriptResultAs converts a script reply to T. int64, int, string, bool,
+// float64, []string, []int64 and []interface{} are converted directly; any
+// other type is decoded from a JSON string reply.
+func ScriptResultAs[T any](r *ScriptResult) (T, error) {
+	var zero T
+	var v interface{}
+	var err error
+
+	switch any(zero).(type) {
+	case int64:
+		v, err = r.Int64()
+	case int:
+		v, err = r.Int()
+	case string:
+		v, err = r.Text()
+	case bool:
+		v, err = r.Bool()
+	case float64:
+		v, err = r.Float64()
+	case []string:
+		v, err = r.StringSlice()
+	case []int64:
+		v, err = r.Int64Slice()
+	case []interface{}:
+		v, err = r.Slice()
+	default:
+		var out T
+		if err := r.Decode(&out); err != nil {
+			return zero, err
+		}
+		return out, nil
+	}
+
+	if err != nil {
+		return zero, err
+	}
+	return v.(T), nil
+}
+
+// scriptRegistry holds the scripts preloaded on every new connection
+type scriptRegistry struct {
+	mu      sync.RWMutex
+	scripts map[string]*Script
+}
+
+// list returns the registered scripts sorted by name
+func (r *scriptRegistry) list() []*Script {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	scripts := make([]*Script, 0, len(r.scripts))
+	for _, s := range r.scripts {
+		scripts = append(scripts, s)
+	}
+	sort.Slice(scripts, func(i, j int) bool { return scripts[i].name < scripts[j].name })
+	return scripts
+}
+
+// RegisterScripts adds scripts to the client's registry and loads them on the
+// server. Registered scripts are loaded again on every new connection, so they
+// survive server restarts and failovers. A script replaces any registered
+// script with the same name. The scripts stay registered if loading fails.
+func (c *Client) RegisterScripts(ctx context.Context, scripts ...*Script) error {
+	c.mu.RLock()
+	closed := c.closed
+	c.mu.RUnlock()
+
+	if closed {
+		return ErrClientClosed
+	}
+
+	c.scripts.mu.Lock()
+	if c.scripts.scripts == nil {
+		c.scripts.scripts = make(map[string]*Script)
+	}
+	for _, s := range scripts {
+		if s == nil || s.name == "" {
+			c.scripts.mu.Unlock()
+			return fmt.Errorf("script must have a name")
+		}
+		c.scripts.scripts[s.name] = s
+	}
+	c.scripts.mu.Unlock()
+
+	return c.LoadScripts(ctx)
+}
+
+// Script returns a registered script by name
+func (c *Client) Script(name string) (*Script, bool) {
+	c.scripts.mu.RLock()
+	defer c.scripts.mu.RUnlock()
+
+	s, ok := c.scripts.scripts[name]
+	return s, ok
+}
+
+// RunScript executes a registered script by name
+func (c *Client) RunScript(ctx context.Context, name string, keys []string, args ...interface{}) *ScriptResult {
+	s, ok := c.Script(name)
+	if !ok {
+		cmd := redis.NewCmd(ctx)
+		cmd.SetErr(fmt.Errorf("script %q is not registered", name))
+		return &ScriptResult{Cmd: cmd}
+	}
+	return s.Run(ctx, c, keys, args...)
+}
+
+// LoadScripts loads all registered scripts with SCRIPT LOAD. In cluster mode
+// they are loaded on every master.
+func (c *Client) LoadScripts(ctx context.Context) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return ErrClientClosed
+	}
+
+	for _, s := range c.scripts.list() {
+		if err := c.client.ScriptLoad(ctx, s.src).Err(); err != nil {
+			return fmt.Errorf("failed to load script %s: %w", s.name, err)
+		}
+	}
+	return nil
+}
+
+// runScript runs a Lua script with EVALSHA, falling back to EVAL
+func (c *Client) runScript(ctx context.Context, script *redis.Script, keys []string, args ...interface{}) *redis.Cmd {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		cmd := redis.NewCmd(ctx)
+		cmd.SetErr(ErrClientClosed)
+		return cmd
+	}
+
+	return script.Run(ctx, c.client, keys, args...)
+}
+
+// preloadScripts loads missing registered scripts on a new connection. Errors
+// are ignored because Run falls back to EVAL.
+func (c *Client) preloadScripts(ctx context.Context, cn *redis.Conn) error {
+	scripts := c.scripts.list()
+	if len(scripts) == 0 {
+		return nil
+	}
+
+	hashes := make([]string, len(scripts))
+	for i, s := range scripts {
+		hashes[i] = s.Hash()
+	}
+
+	exists, err := cn.ScriptExists(ctx, hashes...).Result()
+	if err != nil {
+		return nil
+	}
+
+	for i, s := range scripts {
+		if i < len(exists) && !exists[i] {
+			cn.ScriptLoad(ctx, s.src)
+		}
+	}
+	return nil
+}
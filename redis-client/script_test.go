@@ -0,0 +1,103 @@
+package redisclient
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TestLoadScriptsFS tests loading scripts from a file system
+func TestLoadScriptsFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"scripts/incr.lua":  {Data: []byte("return redis.call('INCR', KEYS[1])")},
+		"scripts/reset.lua": {Data: []byte("return redis.call('DEL', KEYS[1])")},
+		"scripts/README.md": {Data: []byte("not a script")},
+	}
+
+	scripts, err := LoadScriptsFS(fsys, "scripts/*.lua")
+	if err != nil {
+		t.Fatalf("LoadScriptsFS() error = %v", err)
+	}
+	if len(scripts) != 2 || scripts[0].Name() != "incr" || scripts[1].Name() != "reset" {
+		t.Errorf("LoadScriptsFS() = %v", scripts)
+	}
+	if scripts[0].Source() != "return redis.call('INCR', KEYS[1])" || len(scripts[0].Hash()) != 40 {
+		t.Errorf("script = %q, %q", scripts[0].Source(), scripts[0].Hash())
+	}
+
+	if _, err := LoadScriptsFS(fsys, "missing/*.lua"); err == nil {
+		t.Error("LoadScriptsFS() should fail when nothing matches")
+	}
+}
+
+// TestScript_ClosedClient tests scripts on a closed client
+func TestScript_ClosedClient(t *testing.T) {
+	client := &Client{config: DefaultConfig(), closed: true}
+	ctx := context.Background()
+	script := NewScript("incr", "return redis.call('INCR', KEYS[1])")
+
+	if err := script.Run(ctx, client, []string{"counter"}).Err(); err != ErrClientClosed {
+		t.Errorf("Run() error = %v, want ErrClientClosed", err)
+	}
+	if err := script.RunRO(ctx, client, []string{"counter"}).Err(); err != ErrClientClosed {
+		t.Errorf("RunRO() error = %v, want ErrClientClosed", err)
+	}
+	if err := client.RegisterScripts(ctx, script); err != ErrClientClosed {
+		t.Errorf("RegisterScripts() error = %v, want ErrClientClosed", err)
+	}
+	if err := client.LoadScripts(ctx); err != ErrClientClosed {
+		t.Errorf("LoadScripts() error = %v, want ErrClientClosed", err)
+	}
+}
+
+// TestScript_Registry tests lookups of unregistered scripts
+func TestScript_Registry(t *testing.T) {
+	client := &Client{config: DefaultConfig()}
+	ctx := context.Background()
+
+	if _, ok := client.Script("incr"); ok {
+		t.Error("Script() should not find an unregistered script")
+	}
+	if err := client.RunScript(ctx, "incr", nil).Err(); err == nil {
+		t.Error("RunScript() should fail for an unregistered script")
+	}
+	if err := client.RegisterScripts(ctx, NewScript("", "return 1")); err == nil {
+		t.Error("RegisterScripts() should reject a script without a name")
+	}
+}
+
+// TestScriptResultAs tests typed decoding of script replies
+func TestScriptResultAs(t *testing.T) {
+	result := func(v interface{}) *ScriptResult {
+		cmd := redis.NewCmd(context.Background())
+		cmd.SetVal(v)
+		return &ScriptResult{Cmd: cmd}
+	}
+
+	if n, err := ScriptResultAs[int64](result(int64(42))); n != 42 || err != nil {
+		t.Errorf("ScriptResultAs[int64]() = %v, %v", n, err)
+	}
+	if s, err := ScriptResultAs[string](result("ok")); s != "ok" || err != nil {
+		t.Errorf("ScriptResultAs[string]() = %v, %v", s, err)
+	}
+	if b, err := ScriptResultAs[bool](result(int64(1))); !b || err != nil {
+		t.Errorf("ScriptResultAs[bool]() = %v, %v", b, err)
+	}
+	if s, err := ScriptResultAs[[]string](result([]interface{}{"a", "b"})); len(s) != 2 || err != nil {
+		t.Errorf("ScriptResultAs[[]string]() = %v, %v", s, err)
+	}
+
+	type limit struct {
+		Allowed   bool `json:"allowed"`
+		Remaining int  `json:"remaining"`
+	}
+	l, err := ScriptResultAs[limit](result(`{"allowed":true,"remaining":3}`))
+	if !l.Allowed || l.Remaining != 3 || err != nil {
+		t.Errorf("ScriptResultAs[limit]() = %+v, %v", l, err)
+	}
+	if _, err := ScriptResultAs[limit](result(int64(1))); err == nil {
+		t.Error("ScriptResultAs() should fail to decode a non-JSON reply")
+	}
+}
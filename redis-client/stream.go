@@ -0,0 +1,768 @@
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ====================
+// Stream Operations
+// ====================
+
+// XAdd appends an entry to a stream and returns its ID
+func (c *Client) XAdd(ctx context.Context, stream string, values map[string]interface{}) (string, error) {
+	return c.XAddWithArgs(ctx, &redis.XAddArgs{Stream: stream, Values: values})
+}
+
+// XAddWithArgs appends an entry with explicit ID or trimming options (MaxLen, MinID, Approx)
+func (c *Client) XAddWithArgs(ctx context.Context, args *redis.XAddArgs) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return "", ErrClientClosed
+	}
+
+	if args == nil || args.Stream == "" {
+		return "", ErrInvalidKey
+	}
+
+	return c.client.XAdd(ctx, args).Result()
+}
+
+// XRange returns the entries of a stream between two IDs ("-" and "+" for the full range)
+func (c *Client) XRange(ctx context.Context, stream, start, stop string) ([]redis.XMessage, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if stream == "" {
+		return nil, ErrInvalidKey
+	}
+
+	return c.client.XRange(ctx, stream, start, stop).Result()
+}
+
+// XRangeN returns at most count entries of a stream between two IDs
+func (c *Client) XRangeN(ctx context.Context, stream, start, stop string, count int64) ([]redis.XMessage, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if stream == "" {
+		return nil, ErrInvalidKey
+	}
+
+	return c.client.XRangeN(ctx, stream, start, stop, count).Result()
+}
+
+// XRevRange returns the entries of a stream between two IDs, newest first
+func (c *Client) XRevRange(ctx context.Context, stream, start, stop string) ([]redis.XMessage, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if stream == "" {
+		return nil, ErrInvalidKey
+	}
+
+	return c.client.XRevRange(ctx, stream, start, stop).Result()
+}
+
+// XLen returns the number of entries in a stream
+func (c *Client) XLen(ctx context.Context, stream string) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if stream == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.XLen(ctx, stream).Result()
+}
+
+// XDel removes entries from a stream
+func (c *Client) XDel(ctx context.Context, stream string, ids ...string) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if stream == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.XDel(ctx, stream, ids...).Result()
+}
+
+// XTrimMaxLen trims a stream to at most maxLen entries. With approx, Redis may
+// keep a few more entries, which is much cheaper.
+func (c *Client) XTrimMaxLen(ctx context.Context, stream string, maxLen int64, approx bool) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if stream == "" {
+		return 0, ErrInvalidKey
+	}
+
+	if approx {
+		return c.client.XTrimMaxLenApprox(ctx, stream, maxLen, 0).Result()
+	}
+	return c.client.XTrimMaxLen(ctx, stream, maxLen).Result()
+}
+
+// XTrimMinID removes stream entries with IDs lower than minID
+func (c *Client) XTrimMinID(ctx context.Context, stream, minID string, approx bool) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if stream == "" {
+		return 0, ErrInvalidKey
+	}
+
+	if approx {
+		return c.client.XTrimMinIDApprox(ctx, stream, minID, 0).Result()
+	}
+	return c.client.XTrimMinID(ctx, stream, minID).Result()
+}
+
+// XGroupCreate creates a consumer group, creating the stream if needed. An
+// existing group is not an error.
+func (c *Client) XGroupCreate(ctx context.Context, stream, group, start string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return ErrClientClosed
+	}
+
+	if stream == "" {
+		return ErrInvalidKey
+	}
+
+	err := c.client.XGroupCreateMkStream(ctx, stream, group, start).Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+// XAck acknowledges entries for a consumer group
+func (c *Client) XAck(ctx context.Context, stream, group string, ids ...string) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return 0, ErrClientClosed
+	}
+
+	if stream == "" {
+		return 0, ErrInvalidKey
+	}
+
+	return c.client.XAck(ctx, stream, group, ids...).Result()
+}
+
+// XPending returns a summary of the pending entries of a consumer group
+func (c *Client) XPending(ctx context.Context, stream, group string) (*redis.XPending, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	if stream == "" {
+		return nil, ErrInvalidKey
+	}
+
+	return c.client.XPending(ctx, stream, group).Result()
+}
+
+// ====================
+// Stream Consumer
+// ====================
+
+// Dead-letter entry fields added next to the original values
+const (
+	DeadLetterFieldStream     = "dlq_stream"
+	DeadLetterFieldID         = "dlq_id"
+	DeadLetterFieldGroup      = "dlq_group"
+	DeadLetterFieldConsumer   = "dlq_consumer"
+	DeadLetterFieldDeliveries = "dlq_deliveries"
+	DeadLetterFieldError      = "dlq_error"
+)
+
+// StreamHandler processes a stream entry. Returning nil acknowledges it;
+// returning an error leaves it pending so it is retried after ClaimMinIdle.
+type StreamHandler func(ctx context.Context, msg redis.XMessage) error
+
+// StreamConsumerOptions configures a StreamConsumer
+type StreamConsumerOptions struct {
+	Consumer         string          // Consumer name, unique per process (default: hostname-pid)
+	Concurrency      int             // Number of handler goroutines
+	BatchSize        int64           // Entries per XREADGROUP / XAUTOCLAIM call
+	Block            time.Duration   // XREADGROUP block time, also bounds shutdown latency
+	StartID          string          // Group start ID when the group is created ("$" for new entries only)
+	ClaimInterval    time.Duration   // How often to claim stuck entries, 0 disables claiming
+	ClaimMinIdle     time.Duration   // Idle time after which a pending entry is claimed
+	MaxDeliveries    int64           // Deliveries before an entry is dead-lettered, 0 retries forever
+	DeadLetterStream string          // Dead-letter stream (default: "{<stream>}:dlq", or "<stream>:dlq" if the stream has a hash tag)
+	ShutdownTimeout  time.Duration   // How long in-flight handlers may run after shutdown starts
+	ErrorHandler     func(err error) // Called for read, ack and dead-letter errors
+	HandlerTimeout   time.Duration   // Per-entry handler timeout, 0 means none
+}
+
+// DefaultStreamConsumerOptions returns the default stream consumer options
+func DefaultStreamConsumerOptions() StreamConsumerOptions {
+	return StreamConsumerOptions{
+		Concurrency:     1,
+		BatchSize:       10,
+		Block:           2 * time.Second,
+		StartID:         "$",
+		ClaimInterval:   30 * time.Second,
+		ClaimMinIdle:    time.Minute,
+		MaxDeliveries:   5,
+		ShutdownTimeout: 30 * time.Second,
+	}
+}
+
+// StreamConsumerOption is a functional option for configuring a StreamConsumer
+type StreamConsumerOption func(*StreamConsumerOptions)
+
+// WithConsumerName sets the consumer name within the group
+func WithConsumerName(name string) StreamConsumerOption {
+	return func(o *StreamConsumerOptions) {
+		o.Consumer = name
+	}
+}
+
+// WithStreamConcurrency sets the number of handler goroutines
+func WithStreamConcurrency(n int) StreamConsumerOption {
+	return func(o *StreamConsumerOptions) {
+		o.Concurrency = n
+	}
+}
+
+// WithStreamBatchSize sets the number of entries fetched per call
+func WithStreamBatchSize(n int64) StreamConsumerOption {
+	return func(o *StreamConsumerOptions) {
+		o.BatchSize = n
+	}
+}
+
+// WithStreamBlock sets how long a read blocks waiting for new entries
+func WithStreamBlock(d time.Duration) StreamConsumerOption {
+	return func(o *StreamConsumerOptions) {
+		o.Block = d
+	}
+}
+
+// WithStreamStartID sets where a newly created group starts reading ("0" for the whole stream)
+func WithStreamStartID(id string) StreamConsumerOption {
+	return func(o *StreamConsumerOptions) {
+		o.StartID = id
+	}
+}
+
+// WithStreamClaim sets how often stuck entries are claimed and how long they
+// must be idle first
+func WithStreamClaim(interval, minIdle time.Duration) StreamConsumerOption {
+	return func(o *StreamConsumerOptions) {
+		o.ClaimInterval = interval
+		o.ClaimMinIdle = minIdle
+	}
+}
+
+// WithMaxDeliveries sets the deliveries after which an entry is dead-lettered
+func WithMaxDeliveries(n int64) StreamConsumerOption {
+	return func(o *StreamConsumerOptions) {
+		o.MaxDeliveries = n
+	}
+}
+
+// WithDeadLetterStream sets the dead-letter stream
+func WithDeadLetterStream(stream string) StreamConsumerOption {
+	return func(o *StreamConsumerOptions) {
+		o.DeadLetterStream = stream
+	}
+}
+
+// WithShutdownTimeout sets how long in-flight handlers may run after shutdown starts
+func WithShutdownTimeout(d time.Duration) StreamConsumerOption {
+	return func(o *StreamConsumerOptions) {
+		o.ShutdownTimeout = d
+	}
+}
+
+// WithHandlerTimeout sets a per-entry handler timeout
+func WithHandlerTimeout(d time.Duration) StreamConsumerOption {
+	return func(o *StreamConsumerOptions) {
+		o.HandlerTimeout = d
+	}
+}
+
+// WithStreamErrorHandler sets the callback for background errors
+func WithStreamErrorHandler(fn func(err error)) StreamConsumerOption {
+	return func(o *StreamConsumerOptions) {
+		o.ErrorHandler = fn
+	}
+}
+
+// StreamConsumer processes a stream as a member of a consumer group. Entries
+// are acknowledged when the handler succeeds; entries left pending by failed
+// handlers or dead consumers are claimed with XAUTOCLAIM and retried until
+// MaxDeliveries, then moved to the dead-letter stream.
+type StreamConsumer struct {
+	client  *Client
+	stream  string
+	group   string
+	handler StreamHandler
+	opts    StreamConsumerOptions
+
+	mu      sync.Mutex
+	running bool
+}
+
+// streamDelivery is an entry handed to a worker with its delivery count
+type streamDelivery struct {
+	msg        redis.XMessage
+	deliveries int64
+}
+
+// NewStreamConsumer creates a consumer for a stream and group. Call Run to start it.
+func (c *Client) NewStreamConsumer(stream, group string, handler StreamHandler, opts ...StreamConsumerOption) (*StreamConsumer, error) {
+	if stream == "" {
+		return nil, ErrInvalidKey
+	}
+	if group == "" {
+		return nil, fmt.Errorf("consumer group cannot be empty")
+	}
+	if handler == nil {
+		return nil, fmt.Errorf("stream handler cannot be nil")
+	}
+
+	options := DefaultStreamConsumerOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.Concurrency <= 0 {
+		return nil, fmt.Errorf("concurrency must be positive")
+	}
+	if options.BatchSize <= 0 {
+		return nil, fmt.Errorf("batch size must be positive")
+	}
+	if options.Consumer == "" {
+		options.Consumer = defaultConsumerName()
+	}
+	if options.DeadLetterStream == "" {
+		options.DeadLetterStream = defaultDeadLetterStream(stream)
+	}
+
+	return &StreamConsumer{
+		client:  c,
+		stream:  stream,
+		group:   group,
+		handler: handler,
+		opts:    options,
+	}, nil
+}
+
+// Stream returns the stream name
+func (sc *StreamConsumer) Stream() string {
+	return sc.stream
+}
+
+// Group returns the consumer group name
+func (sc *StreamConsumer) Group() string {
+	return sc.group
+}
+
+// Consumer returns the consumer name
+func (sc *StreamConsumer) Consumer() string {
+	return sc.opts.Consumer
+}
+
+// Run creates the group if needed and processes entries until ctx is
+// cancelled. On shutdown it stops reading, waits up to ShutdownTimeout for
+// in-flight handlers and returns nil. Unhandled fetched entries stay pending
+// and are claimed later.
+func (sc *StreamConsumer) Run(ctx context.Context) error {
+	sc.mu.Lock()
+	if sc.running {
+		sc.mu.Unlock()
+		return fmt.Errorf("stream consumer is already running")
+	}
+	sc.running = true
+	sc.mu.Unlock()
+
+	defer func() {
+		sc.mu.Lock()
+		sc.running = false
+		sc.mu.Unlock()
+	}()
+
+	if err := sc.client.XGroupCreate(ctx, sc.stream, sc.group, sc.opts.StartID); err != nil {
+		return fmt.Errorf("failed to create consumer group: %w", err)
+	}
+
+	// Handlers keep running after ctx is cancelled until the shutdown timeout
+	handlerCtx, cancelHandlers := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelHandlers()
+
+	deliveries := make(chan streamDelivery)
+
+	var workers sync.WaitGroup
+	for i := 0; i < sc.opts.Concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for d := range deliveries {
+				sc.process(handlerCtx, d)
+			}
+		}()
+	}
+
+	var fetchers sync.WaitGroup
+	fetchers.Add(1)
+	go func() {
+		defer fetchers.Done()
+		sc.readLoop(ctx, deliveries)
+	}()
+	if sc.opts.ClaimInterval > 0 {
+		fetchers.Add(1)
+		go func() {
+			defer fetchers.Done()
+			sc.claimLoop(ctx, deliveries)
+		}()
+	}
+
+	fetchers.Wait()
+	close(deliveries)
+
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+
+	if sc.opts.ShutdownTimeout > 0 {
+		timer := time.NewTimer(sc.opts.ShutdownTimeout)
+		defer timer.Stop()
+		select {
+		case <-done:
+		case <-timer.C:
+			cancelHandlers()
+			<-done
+		}
+	} else {
+		<-done
+	}
+
+	return nil
+}
+
+// readLoop fetches new entries for this consumer
+func (sc *StreamConsumer) readLoop(ctx context.Context, out chan<- streamDelivery) {
+	for ctx.Err() == nil {
+		streams, err := sc.client.readGroup(ctx, &redis.XReadGroupArgs{
+			Group:    sc.group,
+			Consumer: sc.opts.Consumer,
+			Streams:  []string{sc.stream, ">"},
+			Count:    sc.opts.BatchSize,
+			Block:    sc.opts.Block,
+		})
+		if err != nil {
+			if IsNil(err) || ctx.Err() != nil {
+				continue
+			}
+			sc.reportError(fmt.Errorf("failed to read stream %s: %w", sc.stream, err))
+			if err == ErrClientClosed {
+				return
+			}
+			sleepContext(ctx, time.Second)
+			continue
+		}
+
+		for _, s := range streams {
+			for _, msg := range s.Messages {
+				select {
+				case out <- streamDelivery{msg: msg, deliveries: 1}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}
+}
+
+// claimLoop periodically claims entries that stayed pending too long
+func (sc *StreamConsumer) claimLoop(ctx context.Context, out chan<- streamDelivery) {
+	ticker := time.NewTicker(sc.opts.ClaimInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := sc.claim(ctx, out); err != nil && ctx.Err() == nil {
+			sc.reportError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// claim runs one XAUTOCLAIM pass over the pending entries list
+func (sc *StreamConsumer) claim(ctx context.Context, out chan<- streamDelivery) error {
+	start := "0-0"
+	for ctx.Err() == nil {
+		msgs, next, err := sc.client.autoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   sc.stream,
+			Group:    sc.group,
+			Consumer: sc.opts.Consumer,
+			MinIdle:  sc.opts.ClaimMinIdle,
+			Start:    start,
+			Count:    sc.opts.BatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to claim pending entries: %w", err)
+		}
+
+		if len(msgs) > 0 {
+			counts, err := sc.deliveryCounts(ctx, msgs)
+			if err != nil {
+				return err
+			}
+
+			for _, msg := range msgs {
+				d := streamDelivery{msg: msg, deliveries: counts[msg.ID]}
+				if sc.opts.MaxDeliveries > 0 && d.deliveries > sc.opts.MaxDeliveries {
+					sc.deadLetter(ctx, d, errors.New("max deliveries exceeded"))
+					continue
+				}
+
+				select {
+				case out <- d:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		}
+
+		if next == "0-0" || next == "" {
+			return nil
+		}
+		start = next
+	}
+	return nil
+}
+
+// deliveryCounts returns how often each claimed entry has been delivered
+func (sc *StreamConsumer) deliveryCounts(ctx context.Context, msgs []redis.XMessage) (map[string]int64, error) {
+	pending, err := sc.client.pendingExt(ctx, &redis.XPendingExtArgs{
+		Stream:   sc.stream,
+		Group:    sc.group,
+		Start:    msgs[0].ID,
+		End:      msgs[len(msgs)-1].ID,
+		Count:    int64(len(msgs)),
+		Consumer: sc.opts.Consumer,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read delivery counts: %w", err)
+	}
+
+	counts := make(map[string]int64, len(pending))
+	for _, p := range pending {
+		counts[p.ID] = p.RetryCount
+	}
+	// A claimed entry was delivered at least once, even if XPENDING no longer lists it
+	for _, msg := range msgs {
+		if counts[msg.ID] < 1 {
+			counts[msg.ID] = 1
+		}
+	}
+	return counts, nil
+}
+
+// process runs the handler and acknowledges or dead-letters the entry
+func (sc *StreamConsumer) process(ctx context.Context, d streamDelivery) {
+	handlerCtx := ctx
+	if sc.opts.HandlerTimeout > 0 {
+		var cancel context.CancelFunc
+		handlerCtx, cancel = context.WithTimeout(ctx, sc.opts.HandlerTimeout)
+		defer cancel()
+	}
+
+	err := sc.safeHandle(handlerCtx, d.msg)
+	if err == nil {
+		if _, err := sc.client.XAck(ctx, sc.stream, sc.group, d.msg.ID); err != nil {
+			sc.reportError(fmt.Errorf("failed to ack %s: %w", d.msg.ID, err))
+		}
+		return
+	}
+
+	if sc.opts.MaxDeliveries > 0 && d.deliveries >= sc.opts.MaxDeliveries {
+		sc.deadLetter(ctx, d, err)
+	}
+	// Otherwise the entry stays pending and is claimed again after ClaimMinIdle
+}
+
+// safeHandle runs the handler, turning a panic into an error
+func (sc *StreamConsumer) safeHandle(ctx context.Context, msg redis.XMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("stream handler panic: %v", r)
+		}
+	}()
+	return sc.handler(ctx, msg)
+}
+
+// deadLetter copies an entry to the dead-letter stream and acknowledges it.
+// Both happen in one transaction unless the streams are in different cluster
+// slots; then the copy is made first, so a failed ack may leave a duplicate.
+func (sc *StreamConsumer) deadLetter(ctx context.Context, d streamDelivery, cause error) {
+	values := make(map[string]interface{}, len(d.msg.Values)+6)
+	for k, v := range d.msg.Values {
+		values[k] = v
+	}
+	values[DeadLetterFieldStream] = sc.stream
+	values[DeadLetterFieldID] = d.msg.ID
+	values[DeadLetterFieldGroup] = sc.group
+	values[DeadLetterFieldConsumer] = sc.opts.Consumer
+	values[DeadLetterFieldDeliveries] = d.deliveries
+	values[DeadLetterFieldError] = cause.Error()
+
+	var err error
+	if sc.client.sameSlot(sc.stream, sc.opts.DeadLetterStream) {
+		err = sc.client.txPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.XAdd(ctx, &redis.XAddArgs{Stream: sc.opts.DeadLetterStream, Values: values})
+			pipe.XAck(ctx, sc.stream, sc.group, d.msg.ID)
+			return nil
+		})
+	} else if _, err = sc.client.XAdd(ctx, sc.opts.DeadLetterStream, values); err == nil {
+		_, err = sc.client.XAck(ctx, sc.stream, sc.group, d.msg.ID)
+	}
+	if err != nil {
+		sc.reportError(fmt.Errorf("failed to dead-letter %s: %w", d.msg.ID, err))
+	}
+}
+
+// reportError passes a background error to the error handler
+func (sc *StreamConsumer) reportError(err error) {
+	if sc.opts.ErrorHandler != nil {
+		sc.opts.ErrorHandler(err)
+	}
+}
+
+// readGroup runs XREADGROUP
+func (c *Client) readGroup(ctx context.Context, args *redis.XReadGroupArgs) ([]redis.XStream, error) {
+	var streams []redis.XStream
+	err := c.blocking(func(client redis.UniversalClient) (err error) {
+		streams, err = client.XReadGroup(ctx, args).Result()
+		return err
+	})
+	return streams, err
+}
+
+// autoClaim runs XAUTOCLAIM
+func (c *Client) autoClaim(ctx context.Context, args *redis.XAutoClaimArgs) ([]redis.XMessage, string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, "", ErrClientClosed
+	}
+
+	return c.client.XAutoClaim(ctx, args).Result()
+}
+
+// pendingExt runs XPENDING with a range
+func (c *Client) pendingExt(ctx context.Context, args *redis.XPendingExtArgs) ([]redis.XPendingExt, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+
+	return c.client.XPendingExt(ctx, args).Result()
+}
+
+// txPipelined runs commands in a MULTI/EXEC block
+func (c *Client) txPipelined(ctx context.Context, fn func(redis.Pipeliner) error) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return ErrClientClosed
+	}
+
+	_, err := c.client.TxPipelined(ctx, fn)
+	return err
+}
+
+// defaultDeadLetterStream returns the dead-letter stream name for a stream,
+// hash-tagged so that both are in the same cluster slot
+func defaultDeadLetterStream(stream string) string {
+	if dlq := stream + ":dlq"; SameSlot(stream, dlq) {
+		return dlq
+	}
+	return HashTag(stream) + ":dlq"
+}
+
+// sameSlot reports whether keys can be used in one transaction: always in
+// standalone and sentinel mode, and in cluster mode when the prefixed keys
+// hash to the same slot
+func (c *Client) sameSlot(keys ...string) bool {
+	if c.config.Mode() != ModeCluster {
+		return true
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.config.KeyPrefix + key
+	}
+	return SameSlot(prefixed...)
+}
+
+// defaultConsumerName returns a consumer name unique to this process
+func defaultConsumerName() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "consumer"
+	}
+	return host + "-" + strconv.Itoa(os.Getpid())
+}
@@ -0,0 +1,229 @@
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TestStreams_ClosedClient tests stream commands on a closed client
+func TestStreams_ClosedClient(t *testing.T) {
+	client := &Client{config: DefaultConfig(), closed: true}
+	ctx := context.Background()
+
+	if _, err := client.XAdd(ctx, "events", map[string]interface{}{"a": 1}); err != ErrClientClosed {
+		t.Errorf("XAdd() error = %v, want ErrClientClosed", err)
+	}
+	if _, err := client.XRange(ctx, "events", "-", "+"); err != ErrClientClosed {
+		t.Errorf("XRange() error = %v, want ErrClientClosed", err)
+	}
+	if _, err := client.XTrimMaxLen(ctx, "events", 100, true); err != ErrClientClosed {
+		t.Errorf("XTrimMaxLen() error = %v, want ErrClientClosed", err)
+	}
+	if err := client.XGroupCreate(ctx, "events", "workers", "$"); err != ErrClientClosed {
+		t.Errorf("XGroupCreate() error = %v, want ErrClientClosed", err)
+	}
+}
+
+// TestStreams_InvalidKey tests stream commands with an empty stream name
+func TestStreams_InvalidKey(t *testing.T) {
+	client := &Client{config: DefaultConfig()}
+	ctx := context.Background()
+
+	if _, err := client.XAdd(ctx, "", nil); err != ErrInvalidKey {
+		t.Errorf("XAdd() error = %v, want ErrInvalidKey", err)
+	}
+	if _, err := client.XAddWithArgs(ctx, nil); err != ErrInvalidKey {
+		t.Errorf("XAddWithArgs(nil) error = %v, want ErrInvalidKey", err)
+	}
+	if _, err := client.XLen(ctx, ""); err != ErrInvalidKey {
+		t.Errorf("XLen() error = %v, want ErrInvalidKey", err)
+	}
+	if _, err := client.XTrimMinID(ctx, "", "0-1", false); err != ErrInvalidKey {
+		t.Errorf("XTrimMinID() error = %v, want ErrInvalidKey", err)
+	}
+}
+
+// TestNewStreamConsumer tests consumer defaults and validation
+func TestNewStreamConsumer(t *testing.T) {
+	client := &Client{config: DefaultConfig()}
+	handler := func(ctx context.Context, msg redis.XMessage) error { return nil }
+
+	sc, err := client.NewStreamConsumer("orders", "billing", handler)
+	if err != nil {
+		t.Fatalf("NewStreamConsumer() error = %v", err)
+	}
+	if sc.Consumer() == "" {
+		t.Error("default consumer name should not be empty")
+	}
+	if sc.opts.DeadLetterStream != "{orders}:dlq" {
+		t.Errorf("DeadLetterStream = %q, want {orders}:dlq", sc.opts.DeadLetterStream)
+	}
+	if sc.opts.MaxDeliveries != 5 || sc.opts.Concurrency != 1 {
+		t.Errorf("defaults = %+v", sc.opts)
+	}
+
+	sc, err = client.NewStreamConsumer("orders", "billing", handler,
+		WithConsumerName("worker-1"),
+		WithStreamConcurrency(8),
+		WithStreamClaim(time.Second, 5*time.Second),
+		WithDeadLetterStream("orders:failed"),
+	)
+	if err != nil {
+		t.Fatalf("NewStreamConsumer() with options error = %v", err)
+	}
+	if sc.Consumer() != "worker-1" || sc.opts.Concurrency != 8 || sc.opts.ClaimMinIdle != 5*time.Second {
+		t.Errorf("options = %+v", sc.opts)
+	}
+	if sc.opts.DeadLetterStream != "orders:failed" {
+		t.Errorf("DeadLetterStream = %q, want orders:failed", sc.opts.DeadLetterStream)
+	}
+
+	tests := []struct {
+		name    string
+		stream  string
+		group   string
+		handler StreamHandler
+		opts    []StreamConsumerOption
+	}{
+		{"empty stream", "", "billing", handler, nil},
+		{"empty group", "orders", "", handler, nil},
+		{"nil handler", "orders", "billing", nil, nil},
+		{"zero concurrency", "orders", "billing", handler, []StreamConsumerOption{WithStreamConcurrency(0)}},
+		{"zero batch", "orders", "billing", handler, []StreamConsumerOption{WithStreamBatchSize(0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.NewStreamConsumer(tt.stream, tt.group, tt.handler, tt.opts...); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+// TestStreamConsumer_ClosedClient tests that Run fails on a closed client
+func TestStreamConsumer_ClosedClient(t *testing.T) {
+	client := &Client{config: DefaultConfig(), closed: true}
+
+	sc, err := client.NewStreamConsumer("orders", "billing", func(ctx context.Context, msg redis.XMessage) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("NewStreamConsumer() error = %v", err)
+	}
+
+	if err := sc.Run(context.Background()); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Run() error = %v, want ErrClientClosed", err)
+	}
+}
+
+// TestStreamConsumer_Process tests ack, retry and dead-letter decisions
+func TestStreamConsumer_Process(t *testing.T) {
+	client := &Client{config: DefaultConfig(), closed: true}
+
+	var reported []error
+	sc, err := client.NewStreamConsumer("orders", "billing",
+		func(ctx context.Context, msg redis.XMessage) error {
+			switch msg.ID {
+			case "1-0":
+				return nil
+			case "3-0":
+				panic("bad entry")
+			default:
+				return errors.New("failed")
+			}
+		},
+		WithMaxDeliveries(3),
+		WithStreamErrorHandler(func(err error) { reported = append(reported, err) }),
+	)
+	if err != nil {
+		t.Fatalf("NewStreamConsumer() error = %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Success is acknowledged
+	sc.process(ctx, streamDelivery{msg: redis.XMessage{ID: "1-0"}, deliveries: 1})
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "failed to ack") {
+		t.Fatalf("after success reported = %v", reported)
+	}
+
+	// A failure below the limit stays pending
+	sc.process(ctx, streamDelivery{msg: redis.XMessage{ID: "2-0"}, deliveries: 2})
+	if len(reported) != 1 {
+		t.Fatalf("after retryable failure reported = %v", reported)
+	}
+
+	// A panic on the last delivery is dead-lettered
+	sc.process(ctx, streamDelivery{msg: redis.XMessage{ID: "3-0"}, deliveries: 3})
+	if len(reported) != 2 || !strings.Contains(reported[1].Error(), "failed to dead-letter") {
+		t.Fatalf("after final failure reported = %v", reported)
+	}
+}
+
+// TestDefaultDeadLetterStream tests that the default dead-letter stream shares the stream's slot
+func TestDefaultDeadLetterStream(t *testing.T) {
+	tests := []struct {
+		stream string
+		want   string
+	}{
+		{stream: "orders", want: "{orders}:dlq"},
+		{stream: "{tenant:1}:orders", want: "{tenant:1}:orders:dlq"},
+		{stream: "a{b", want: "{a{b}:dlq"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.stream, func(t *testing.T) {
+			got := defaultDeadLetterStream(tt.stream)
+			if got != tt.want {
+				t.Errorf("defaultDeadLetterStream() = %q, want %q", got, tt.want)
+			}
+			if !SameSlot(tt.stream, got) {
+				t.Errorf("%q and %q are in different slots", tt.stream, got)
+			}
+		})
+	}
+
+	cluster := &Client{config: DefaultConfig()}
+	cluster.config.ClusterAddrs = []string{"127.0.0.1:7000"}
+	if !cluster.sameSlot("orders", "{orders}:dlq") {
+		t.Error("sameSlot() should accept keys in one slot")
+	}
+	if cluster.sameSlot("orders", "orders:failed") {
+		t.Error("sameSlot() should reject keys in different cluster slots")
+	}
+	if standalone := (&Client{config: DefaultConfig()}); !standalone.sameSlot("orders", "orders:failed") {
+		t.Error("sameSlot() should accept any keys outside cluster mode")
+	}
+}
+
+// TestStreamConsumer_DeliveryCounts tests that claimed entries missing from XPENDING count as delivered
+func TestStreamConsumer_DeliveryCounts(t *testing.T) {
+	client := newReplyClient(t, func(cmd redis.Cmder) (bool, error) {
+		if c, ok := cmd.(*redis.XPendingExtCmd); ok {
+			c.SetVal([]redis.XPendingExt{{ID: "1-0", RetryCount: 4}})
+			return true, nil
+		}
+		return false, nil
+	})
+
+	sc, err := client.NewStreamConsumer("orders", "billing", func(ctx context.Context, msg redis.XMessage) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("NewStreamConsumer() error = %v", err)
+	}
+
+	counts, err := sc.deliveryCounts(context.Background(), []redis.XMessage{{ID: "1-0"}, {ID: "2-0"}})
+	if err != nil {
+		t.Fatalf("deliveryCounts() error = %v", err)
+	}
+	if counts["1-0"] != 4 || counts["2-0"] != 1 {
+		t.Errorf("deliveryCounts() = %v, want 1-0: 4, 2-0: 1", counts)
+	}
+}
@@ -0,0 +1,389 @@
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ====================
+// Typed Subscriber
+// ====================
+
+// Message is a decoded pub/sub message
+type Message[T any] struct {
+	Channel string // Channel the message was published to, without the key prefix
+	Pattern string // Matching pattern for pattern subscriptions, without the key prefix
+	Payload T      // Decoded payload
+}
+
+// MessageHandler processes a decoded pub/sub message. A returned error is
+// reported to the error handler; pub/sub has no redelivery.
+type MessageHandler[T any] func(ctx context.Context, msg Message[T]) error
+
+// SubscriberOptions configures a Subscriber
+type SubscriberOptions struct {
+	Codec             Codec           // Payload codec (default: JSONCodec)
+	Patterns          bool            // Subscribe with PSUBSCRIBE, channels are glob patterns
+	Concurrency       int             // Number of handler goroutines
+	BufferSize        int             // Messages queued for the handlers before receiving blocks
+	HandlerTimeout    time.Duration   // Per-message handler timeout, 0 means none
+	PingInterval      time.Duration   // Idle time after which the connection is checked with PING
+	ReconnectDelay    time.Duration   // Initial delay before resubscribing after a connection loss
+	MaxReconnectDelay time.Duration   // Upper bound for the reconnect backoff
+	ShutdownTimeout   time.Duration   // How long in-flight handlers may run after shutdown starts
+	ErrorHandler      func(err error) // Called for connection, decode and handler errors
+}
+
+// DefaultSubscriberOptions returns the default subscriber options
+func DefaultSubscriberOptions() SubscriberOptions {
+	return SubscriberOptions{
+		Codec:             JSONCodec{},
+		Concurrency:       1,
+		BufferSize:        100,
+		PingInterval:      30 * time.Second,
+		ReconnectDelay:    100 * time.Millisecond,
+		MaxReconnectDelay: 10 * time.Second,
+		ShutdownTimeout:   30 * time.Second,
+	}
+}
+
+// SubscriberOption is a functional option for configuring a Subscriber
+type SubscriberOption func(*SubscriberOptions)
+
+// WithSubscriberCodec sets the payload codec
+func WithSubscriberCodec(codec Codec) SubscriberOption {
+	return func(o *SubscriberOptions) {
+		o.Codec = codec
+	}
+}
+
+// WithSubscriberPatterns subscribes to glob patterns instead of channel names
+func WithSubscriberPatterns() SubscriberOption {
+	return func(o *SubscriberOptions) {
+		o.Patterns = true
+	}
+}
+
+// WithSubscriberConcurrency sets the number of handler goroutines and how many
+// messages may wait for them
+func WithSubscriberConcurrency(workers, buffer int) SubscriberOption {
+	return func(o *SubscriberOptions) {
+		o.Concurrency = workers
+		o.BufferSize = buffer
+	}
+}
+
+// WithSubscriberHandlerTimeout sets the per-message handler timeout
+func WithSubscriberHandlerTimeout(d time.Duration) SubscriberOption {
+	return func(o *SubscriberOptions) {
+		o.HandlerTimeout = d
+	}
+}
+
+// WithSubscriberReconnect sets the backoff used to resubscribe after a connection loss
+func WithSubscriberReconnect(delay, maxDelay time.Duration) SubscriberOption {
+	return func(o *SubscriberOptions) {
+		o.ReconnectDelay = delay
+		o.MaxReconnectDelay = maxDelay
+	}
+}
+
+// WithSubscriberPingInterval sets the idle time after which the connection is checked
+func WithSubscriberPingInterval(d time.Duration) SubscriberOption {
+	return func(o *SubscriberOptions) {
+		o.PingInterval = d
+	}
+}
+
+// WithSubscriberShutdownTimeout sets how long in-flight handlers may run after shutdown starts
+func WithSubscriberShutdownTimeout(d time.Duration) SubscriberOption {
+	return func(o *SubscriberOptions) {
+		o.ShutdownTimeout = d
+	}
+}
+
+// WithSubscriberErrorHandler sets the callback for background errors
+func WithSubscriberErrorHandler(fn func(err error)) SubscriberOption {
+	return func(o *SubscriberOptions) {
+		o.ErrorHandler = fn
+	}
+}
+
+// Subscriber receives pub/sub messages, decodes their payloads and dispatches
+// them to a handler through a bounded worker pool. When the connection is
+// lost it resubscribes with exponential backoff; messages published while
+// disconnected are lost, as with any pub/sub client.
+type Subscriber[T any] struct {
+	client   *Client
+	channels []string
+	handler  MessageHandler[T]
+	opts     SubscriberOptions
+
+	mu      sync.Mutex
+	running bool
+}
+
+// NewSubscriber creates a subscriber for the given channels, or patterns with
+// WithSubscriberPatterns. Call Run to start it.
+func NewSubscriber[T any](client *Client, channels []string, handler MessageHandler[T], opts ...SubscriberOption) (*Subscriber[T], error) {
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("at least one channel is required")
+	}
+	for _, channel := range channels {
+		if channel == "" {
+			return nil, fmt.Errorf("channel cannot be empty")
+		}
+	}
+	if handler == nil {
+		return nil, fmt.Errorf("message handler cannot be nil")
+	}
+
+	options := DefaultSubscriberOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.Codec == nil {
+		return nil, fmt.Errorf("codec cannot be nil")
+	}
+	if options.Concurrency <= 0 {
+		return nil, fmt.Errorf("concurrency must be positive")
+	}
+	if options.BufferSize < 0 {
+		return nil, fmt.Errorf("buffer size cannot be negative")
+	}
+	if options.ReconnectDelay <= 0 {
+		options.ReconnectDelay = DefaultSubscriberOptions().ReconnectDelay
+	}
+
+	return &Subscriber[T]{
+		client:   client,
+		channels: append([]string(nil), channels...),
+		handler:  handler,
+		opts:     options,
+	}, nil
+}
+
+// Channels returns the subscribed channels or patterns
+func (s *Subscriber[T]) Channels() []string {
+	return append([]string(nil), s.channels...)
+}
+
+// Run subscribes and handles messages until ctx is cancelled. If the first
+// subscription fails Run returns its error; later connection losses are
+// reported to the error handler and retried. On shutdown it stops receiving,
+// waits up to ShutdownTimeout for queued and in-flight handlers and returns nil.
+func (s *Subscriber[T]) Run(ctx context.Context) error {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return fmt.Errorf("subscriber is already running")
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	sub, err := s.subscribe(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Handlers keep running after ctx is cancelled until the shutdown timeout
+	handlerCtx, cancelHandlers := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelHandlers()
+
+	messages := make(chan *redis.Message, s.opts.BufferSize)
+
+	var workers sync.WaitGroup
+	for i := 0; i < s.opts.Concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for msg := range messages {
+				s.process(handlerCtx, msg)
+			}
+		}()
+	}
+
+	s.receiveLoop(ctx, sub, messages)
+	close(messages)
+
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+
+	if s.opts.ShutdownTimeout > 0 {
+		timer := time.NewTimer(s.opts.ShutdownTimeout)
+		defer timer.Stop()
+		select {
+		case <-done:
+		case <-timer.C:
+			cancelHandlers()
+			<-done
+		}
+	} else {
+		<-done
+	}
+
+	return nil
+}
+
+// subscribe opens a subscription and waits for the server to confirm it
+func (s *Subscriber[T]) subscribe(ctx context.Context) (*redis.PubSub, error) {
+	s.client.mu.RLock()
+	closed := s.client.closed
+	s.client.mu.RUnlock()
+	if closed {
+		return nil, ErrClientClosed
+	}
+
+	var sub *redis.PubSub
+	if s.opts.Patterns {
+		sub = s.client.PSubscribe(ctx, s.channels...)
+	} else {
+		sub = s.client.Subscribe(ctx, s.channels...)
+	}
+
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, fmt.Errorf("failed to subscribe to %v: %w", s.channels, err)
+	}
+	return sub, nil
+}
+
+// receiveLoop reads messages until ctx is cancelled, resubscribing after
+// connection errors
+func (s *Subscriber[T]) receiveLoop(ctx context.Context, sub *redis.PubSub, out chan<- *redis.Message) {
+	delay := s.opts.ReconnectDelay
+
+	for {
+		if sub == nil {
+			var err error
+			if sub, err = s.subscribe(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				s.reportError(err)
+				if err == ErrClientClosed {
+					return
+				}
+				sleepContext(ctx, delay)
+				delay = s.nextDelay(delay)
+				continue
+			}
+			delay = s.opts.ReconnectDelay
+		}
+
+		err := s.receive(ctx, sub, out)
+		sub.Close()
+		sub = nil
+		if ctx.Err() != nil {
+			return
+		}
+		s.reportError(fmt.Errorf("subscription to %v lost: %w", s.channels, err))
+	}
+}
+
+// receive forwards messages from one subscription until it fails or ctx is done
+func (s *Subscriber[T]) receive(ctx context.Context, sub *redis.PubSub, out chan<- *redis.Message) error {
+	// Closing the subscription unblocks a pending receive on shutdown
+	stop := context.AfterFunc(ctx, func() { sub.Close() })
+	defer stop()
+
+	pinged := false
+	for {
+		msg, err := sub.ReceiveTimeout(ctx, s.opts.PingInterval)
+		if err != nil {
+			var netErr net.Error
+			if !errors.As(err, &netErr) || !netErr.Timeout() {
+				return err
+			}
+			// Idle: make sure the connection is still alive
+			if pinged {
+				return fmt.Errorf("no reply to PING within %s", s.opts.PingInterval)
+			}
+			if err := sub.Ping(ctx); err != nil {
+				return err
+			}
+			pinged = true
+			continue
+		}
+		pinged = false
+
+		m, ok := msg.(*redis.Message)
+		if !ok {
+			continue
+		}
+
+		select {
+		case out <- m:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// process decodes a message and runs the handler
+func (s *Subscriber[T]) process(ctx context.Context, m *redis.Message) {
+	msg := Message[T]{
+		Channel: s.client.StripPrefix(m.Channel),
+	}
+	if m.Pattern != "" {
+		msg.Pattern = s.client.StripPrefix(m.Pattern)
+	}
+
+	if err := s.opts.Codec.Unmarshal([]byte(m.Payload), &msg.Payload); err != nil {
+		s.reportError(fmt.Errorf("failed to decode message on %s: %w", msg.Channel, err))
+		return
+	}
+
+	handlerCtx := ctx
+	if s.opts.HandlerTimeout > 0 {
+		var cancel context.CancelFunc
+		handlerCtx, cancel = context.WithTimeout(ctx, s.opts.HandlerTimeout)
+		defer cancel()
+	}
+
+	if err := s.safeHandle(handlerCtx, msg); err != nil {
+		s.reportError(fmt.Errorf("handler failed for message on %s: %w", msg.Channel, err))
+	}
+}
+
+// safeHandle runs the handler, turning a panic into an error
+func (s *Subscriber[T]) safeHandle(ctx context.Context, msg Message[T]) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("message handler panic: %v", r)
+		}
+	}()
+	return s.handler(ctx, msg)
+}
+
+// nextDelay doubles the reconnect delay up to MaxReconnectDelay
+func (s *Subscriber[T]) nextDelay(delay time.Duration) time.Duration {
+	delay *= 2
+	if s.opts.MaxReconnectDelay > 0 && delay > s.opts.MaxReconnectDelay {
+		delay = s.opts.MaxReconnectDelay
+	}
+	return delay
+}
+
+// reportError passes a background error to the error handler
+func (s *Subscriber[T]) reportError(err error) {
+	if s.opts.ErrorHandler != nil {
+		s.opts.ErrorHandler(err)
+	}
+}
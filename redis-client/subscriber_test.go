@@ -0,0 +1,123 @@
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TestNewSubscriber_Validation tests constructor validation
+func TestNewSubscriber_Validation(t *testing.T) {
+	client := &Client{config: DefaultConfig()}
+	handler := func(ctx context.Context, msg Message[string]) error { return nil }
+
+	tests := []struct {
+		name     string
+		channels []string
+		handler  MessageHandler[string]
+		opts     []SubscriberOption
+	}{
+		{"no channels", nil, handler, nil},
+		{"empty channel", []string{"a", ""}, handler, nil},
+		{"nil handler", []string{"a"}, nil, nil},
+		{"nil codec", []string{"a"}, handler, []SubscriberOption{WithSubscriberCodec(nil)}},
+		{"zero concurrency", []string{"a"}, handler, []SubscriberOption{WithSubscriberConcurrency(0, 10)}},
+		{"negative buffer", []string{"a"}, handler, []SubscriberOption{WithSubscriberConcurrency(1, -1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSubscriber(client, tt.channels, tt.handler, tt.opts...); err == nil {
+				t.Error("NewSubscriber() should fail")
+			}
+		})
+	}
+
+	sub, err := NewSubscriber(client, []string{"events"}, handler, WithSubscriberReconnect(0, time.Second))
+	if err != nil {
+		t.Fatalf("NewSubscriber() error = %v", err)
+	}
+	if sub.opts.ReconnectDelay != DefaultSubscriberOptions().ReconnectDelay {
+		t.Errorf("ReconnectDelay = %v, want default", sub.opts.ReconnectDelay)
+	}
+	if got := sub.Channels(); len(got) != 1 || got[0] != "events" {
+		t.Errorf("Channels() = %v", got)
+	}
+}
+
+// TestSubscriber_RunClosedClient tests that Run fails on a closed client
+func TestSubscriber_RunClosedClient(t *testing.T) {
+	client := &Client{config: DefaultConfig(), closed: true}
+	sub, err := NewSubscriber(client, []string{"events"}, func(ctx context.Context, msg Message[string]) error { return nil })
+	if err != nil {
+		t.Fatalf("NewSubscriber() error = %v", err)
+	}
+
+	if err := sub.Run(context.Background()); err != ErrClientClosed {
+		t.Errorf("Run() error = %v, want %v", err, ErrClientClosed)
+	}
+}
+
+// TestSubscriber_Process tests decoding, prefix stripping and error reporting
+func TestSubscriber_Process(t *testing.T) {
+	config := DefaultConfig()
+	config.KeyPrefix = "app:"
+	client := &Client{config: config}
+
+	type event struct {
+		ID int `json:"id"`
+	}
+
+	var got []Message[event]
+	var errs []error
+	sub, err := NewSubscriber(client, []string{"events:*"}, func(ctx context.Context, msg Message[event]) error {
+		if msg.Payload.ID == 2 {
+			return errors.New("rejected")
+		}
+		if msg.Payload.ID == 3 {
+			panic("boom")
+		}
+		got = append(got, msg)
+		return nil
+	}, WithSubscriberPatterns(), WithSubscriberErrorHandler(func(err error) { errs = append(errs, err) }))
+	if err != nil {
+		t.Fatalf("NewSubscriber() error = %v", err)
+	}
+
+	ctx := context.Background()
+	sub.process(ctx, &redis.Message{Channel: "app:events:a", Pattern: "app:events:*", Payload: `{"id":1}`})
+	sub.process(ctx, &redis.Message{Channel: "app:events:a", Payload: `{"id":2}`})
+	sub.process(ctx, &redis.Message{Channel: "app:events:a", Payload: `{"id":3}`})
+	sub.process(ctx, &redis.Message{Channel: "app:events:b", Payload: `not json`})
+
+	if len(got) != 1 || got[0].Channel != "events:a" || got[0].Pattern != "events:*" || got[0].Payload.ID != 1 {
+		t.Errorf("handled messages = %+v", got)
+	}
+
+	want := []string{"rejected", "panic: boom", "failed to decode message on events:b"}
+	if len(errs) != len(want) {
+		t.Fatalf("reported errors = %v, want %d", errs, len(want))
+	}
+	for i, w := range want {
+		if !strings.Contains(errs[i].Error(), w) {
+			t.Errorf("error %d = %v, want it to contain %q", i, errs[i], w)
+		}
+	}
+}
+
+// TestSubscriber_NextDelay tests the reconnect backoff
+func TestSubscriber_NextDelay(t *testing.T) {
+	sub := &Subscriber[string]{opts: SubscriberOptions{MaxReconnectDelay: 300 * time.Millisecond}}
+
+	delay := 100 * time.Millisecond
+	for _, want := range []time.Duration{200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond} {
+		delay = sub.nextDelay(delay)
+		if delay != want {
+			t.Errorf("nextDelay() = %v, want %v", delay, want)
+		}
+	}
+}
@@ -0,0 +1,580 @@
+package redisclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ====================
+// Two-Tier Cache
+// ====================
+
+// Invalidation selects how local cache tiers learn about changes made by other instances
+type Invalidation int
+
+const (
+	// InvalidatePubSub publishes changed keys on a channel; every instance
+	// subscribes and drops them from its local tier
+	InvalidatePubSub Invalidation = iota
+	// InvalidateTracking uses Redis client-side caching (CLIENT TRACKING in
+	// broadcasting mode), so writes from any Redis client invalidate local tiers
+	InvalidateTracking
+	// InvalidateNone relies on the local TTL only
+	InvalidateNone
+)
+
+// trackingChannel is the channel Redis sends tracking invalidations to
+const trackingChannel = "__redis__:invalidate"
+
+// TieredOptions configures a TieredCache
+type TieredOptions struct {
+	LocalSize    int           // Maximum number of local entries
+	LocalTTL     time.Duration // Local entry lifetime, bounds staleness if an invalidation is missed
+	Eviction     Eviction      // Local eviction policy
+	Invalidation Invalidation  // Cross-instance invalidation mode
+	Channel      string        // Pub/sub channel for InvalidatePubSub
+}
+
+// DefaultTieredOptions returns the default two-tier cache options
+func DefaultTieredOptions() TieredOptions {
+	return TieredOptions{
+		LocalSize:    10000,
+		LocalTTL:     time.Minute,
+		Eviction:     EvictLRU,
+		Invalidation: InvalidatePubSub,
+		Channel:      "redisclient:invalidate",
+	}
+}
+
+// TieredOption is a functional option for configuring a TieredCache
+type TieredOption func(*TieredOptions)
+
+// WithLocalSize sets the maximum number of local entries
+func WithLocalSize(size int) TieredOption {
+	return func(o *TieredOptions) {
+		o.LocalSize = size
+	}
+}
+
+// WithLocalTTL sets the local entry lifetime
+func WithLocalTTL(ttl time.Duration) TieredOption {
+	return func(o *TieredOptions) {
+		o.LocalTTL = ttl
+	}
+}
+
+// WithEviction sets the local eviction policy
+func WithEviction(eviction Eviction) TieredOption {
+	return func(o *TieredOptions) {
+		o.Eviction = eviction
+	}
+}
+
+// WithInvalidation sets the cross-instance invalidation mode
+func WithInvalidation(mode Invalidation) TieredOption {
+	return func(o *TieredOptions) {
+		o.Invalidation = mode
+	}
+}
+
+// WithInvalidationChannel sets the pub/sub channel used by InvalidatePubSub
+func WithInvalidationChannel(channel string) TieredOption {
+	return func(o *TieredOptions) {
+		o.Channel = channel
+	}
+}
+
+// TieredStats holds hit and miss counters per tier
+type TieredStats struct {
+	LocalHits     uint64 `json:"local_hits"`
+	LocalMisses   uint64 `json:"local_misses"`
+	RemoteHits    uint64 `json:"remote_hits"`
+	RemoteMisses  uint64 `json:"remote_misses"`
+	Evictions     uint64 `json:"evictions"`
+	Invalidations uint64 `json:"invalidations"`
+	LocalEntries  int    `json:"local_entries"`
+}
+
+// invalidationMessage is published on the invalidation channel
+type invalidationMessage struct {
+	Source string   `json:"source"`
+	Keys   []string `json:"keys"`
+}
+
+// localValue is a local entry, including negatively cached keys
+type localValue[T any] struct {
+	value    T
+	negative bool
+}
+
+// TieredCache puts a bounded in-process cache in front of a Cache. Local
+// entries are invalidated across instances by pub/sub or client-side tracking.
+type TieredCache[T any] struct {
+	remote *Cache[T]
+	opts   TieredOptions
+	local  *localCache[localValue[T]]
+	id     string
+
+	// generation increases with every invalidation; a value read from Redis is
+	// only stored locally if no invalidation arrived while it was being read
+	generation atomic.Uint64
+
+	localHits, localMisses   atomic.Uint64
+	remoteHits, remoteMisses atomic.Uint64
+	invalidations            atomic.Uint64
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	mu     sync.Mutex
+	stop   func() // Closes the active subscription, unblocking the listener
+	closed bool
+}
+
+// NewTieredCache creates a two-tier cache in front of a Redis cache and starts
+// listening for invalidations. Call Close to stop listening.
+func NewTieredCache[T any](remote *Cache[T], opts ...TieredOption) (*TieredCache[T], error) {
+	options := DefaultTieredOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.LocalSize <= 0 {
+		return nil, fmt.Errorf("local size must be positive")
+	}
+	if options.Invalidation == InvalidatePubSub && options.Channel == "" {
+		return nil, fmt.Errorf("invalidation channel cannot be empty")
+	}
+
+	id, err := generateLockToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate instance id: %w", err)
+	}
+
+	tc := &TieredCache[T]{
+		remote: remote,
+		opts:   options,
+		local:  newLocalCache[localValue[T]](options.LocalSize, options.LocalTTL, options.Eviction),
+		id:     id,
+		done:   make(chan struct{}),
+	}
+
+	remote.client.mu.RLock()
+	closed := remote.client.closed
+	remote.client.mu.RUnlock()
+	if closed {
+		return nil, ErrClientClosed
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tc.cancel = cancel
+
+	switch options.Invalidation {
+	case InvalidatePubSub:
+		sub := remote.client.Subscribe(ctx, options.Channel)
+		// Wait for the subscription so no invalidation is missed after return
+		if _, err := sub.Receive(ctx); err != nil {
+			sub.Close()
+			cancel()
+			return nil, fmt.Errorf("failed to subscribe to %s: %w", options.Channel, err)
+		}
+		tc.setListener(func() { sub.Close() })
+		go tc.listenPubSub(ctx, sub)
+	case InvalidateTracking:
+		tracker, err := tc.startTracking(ctx)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		tc.setListener(tracker.close)
+		go tc.listenTracking(ctx, tracker)
+	default:
+		close(tc.done)
+	}
+
+	return tc, nil
+}
+
+// Get returns a value from the local tier or Redis. It returns ErrNil on a miss
+// and ErrNotFound for a negatively cached key.
+func (tc *TieredCache[T]) Get(ctx context.Context, key string) (T, error) {
+	var zero T
+
+	if v, ok := tc.getLocal(key); ok {
+		if v.negative {
+			return zero, ErrNotFound
+		}
+		return v.value, nil
+	}
+
+	generation := tc.generation.Load()
+	entry, err := tc.remote.getEntry(ctx, key)
+	if err != nil {
+		tc.remoteMisses.Add(1)
+		return zero, err
+	}
+	tc.remoteHits.Add(1)
+
+	tc.storeLocal(key, localValue[T]{value: entry.value, negative: entry.negative}, generation)
+	if entry.negative {
+		return zero, ErrNotFound
+	}
+	return entry.value, nil
+}
+
+// GetOrLoad returns a value from the local tier, Redis or the loader, filling
+// both tiers on a miss
+func (tc *TieredCache[T]) GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader Loader[T]) (T, error) {
+	var zero T
+
+	if v, ok := tc.getLocal(key); ok {
+		if v.negative {
+			return zero, ErrNotFound
+		}
+		return v.value, nil
+	}
+
+	generation := tc.generation.Load()
+	entry, hit, err := tc.remote.getOrLoad(ctx, key, ttl, loader)
+	if hit {
+		tc.remoteHits.Add(1)
+	} else {
+		tc.remoteMisses.Add(1)
+	}
+	if err != nil {
+		return zero, err
+	}
+
+	// Loaded values are announced so other instances drop stale copies
+	if !hit {
+		tc.publish(ctx, key)
+	}
+
+	if entry.negative && tc.remote.opts.NegativeTTL <= 0 {
+		return zero, ErrNotFound
+	}
+
+	tc.storeLocal(key, localValue[T]{value: entry.value, negative: entry.negative}, generation)
+	if entry.negative {
+		return zero, ErrNotFound
+	}
+	return entry.value, nil
+}
+
+// Set stores a value in both tiers and invalidates other instances
+func (tc *TieredCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if err := tc.remote.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+
+	tc.local.set(tc.remote.key(key), localValue[T]{value: value})
+	tc.publish(ctx, key)
+	return nil
+}
+
+// Delete removes values from both tiers and invalidates other instances
+func (tc *TieredCache[T]) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	for _, key := range keys {
+		tc.local.delete(tc.remote.key(key))
+	}
+
+	if err := tc.remote.Delete(ctx, keys...); err != nil {
+		return err
+	}
+
+	tc.publish(ctx, keys...)
+	return nil
+}
+
+// Stats returns hit and miss counters per tier
+func (tc *TieredCache[T]) Stats() TieredStats {
+	return TieredStats{
+		LocalHits:     tc.localHits.Load(),
+		LocalMisses:   tc.localMisses.Load(),
+		RemoteHits:    tc.remoteHits.Load(),
+		RemoteMisses:  tc.remoteMisses.Load(),
+		Evictions:     tc.local.evictionCount(),
+		Invalidations: tc.invalidations.Load(),
+		LocalEntries:  tc.local.len(),
+	}
+}
+
+// Close stops listening for invalidations and clears the local tier. The
+// underlying client is not closed.
+func (tc *TieredCache[T]) Close() error {
+	tc.mu.Lock()
+	if tc.closed {
+		tc.mu.Unlock()
+		return ErrAlreadyClosed
+	}
+	tc.closed = true
+	stop := tc.stop
+	tc.mu.Unlock()
+
+	tc.cancel()
+	if stop != nil {
+		stop()
+	}
+	<-tc.done
+	tc.local.clear()
+	return nil
+}
+
+// setListener records how to stop the active subscription. It reports false,
+// after stopping it, if the cache was closed in the meantime.
+func (tc *TieredCache[T]) setListener(stop func()) bool {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if tc.closed {
+		stop()
+		return false
+	}
+	tc.stop = stop
+	return true
+}
+
+// getLocal reads the local tier and records the hit or miss
+func (tc *TieredCache[T]) getLocal(key string) (localValue[T], bool) {
+	if key == "" {
+		return localValue[T]{}, false
+	}
+
+	v, ok := tc.local.get(tc.remote.key(key))
+	if ok {
+		tc.localHits.Add(1)
+	} else {
+		tc.localMisses.Add(1)
+	}
+	return v, ok
+}
+
+// storeLocal stores a value read from Redis unless an invalidation arrived
+// since the read started
+func (tc *TieredCache[T]) storeLocal(key string, v localValue[T], generation uint64) {
+	if tc.generation.Load() != generation {
+		return
+	}
+	tc.local.set(tc.remote.key(key), v)
+}
+
+// invalidate drops keys from the local tier; no keys clears it
+func (tc *TieredCache[T]) invalidate(keys []string) {
+	tc.generation.Add(1)
+	tc.invalidations.Add(1)
+
+	if len(keys) == 0 {
+		tc.local.clear()
+		return
+	}
+	tc.local.delete(keys...)
+}
+
+// publish announces changed keys to other instances in pub/sub mode
+func (tc *TieredCache[T]) publish(ctx context.Context, keys ...string) {
+	if tc.opts.Invalidation != InvalidatePubSub {
+		return
+	}
+
+	fullKeys := make([]string, len(keys))
+	for i, key := range keys {
+		fullKeys[i] = tc.remote.key(key)
+	}
+
+	payload, err := json.Marshal(invalidationMessage{Source: tc.id, Keys: fullKeys})
+	if err != nil {
+		return
+	}
+
+	// Best effort: other instances fall back to the local TTL
+	_, _ = tc.remote.client.Publish(ctx, tc.opts.Channel, payload)
+}
+
+// listenPubSub applies invalidations published by other instances
+func (tc *TieredCache[T]) listenPubSub(ctx context.Context, sub *redis.PubSub) {
+	defer close(tc.done)
+	defer sub.Close()
+
+	for {
+		msg, err := sub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			// Messages may have been lost while disconnected
+			tc.invalidate(nil)
+			sleepContext(ctx, 100*time.Millisecond)
+			continue
+		}
+
+		m, ok := msg.(*redis.Message)
+		if !ok {
+			continue
+		}
+
+		var inv invalidationMessage
+		if err := json.Unmarshal([]byte(m.Payload), &inv); err != nil || inv.Source == tc.id {
+			continue
+		}
+		tc.invalidate(inv.Keys)
+	}
+}
+
+// tracker holds the connections used for client-side tracking
+type tracker struct {
+	client *redis.Client
+	sub    *redis.PubSub
+	conn   *redis.Conn
+	once   sync.Once
+}
+
+// close releases the tracking connections; it is safe to call more than once
+func (t *tracker) close() {
+	t.once.Do(t.release)
+}
+
+// release closes the tracking connections
+func (t *tracker) release() {
+	if t.conn != nil {
+		t.conn.Close()
+	}
+	if t.sub != nil {
+		t.sub.Close()
+	}
+	t.client.Close()
+}
+
+// startTracking subscribes to invalidation messages and enables broadcasting
+// tracking for the cache prefix, redirected to the subscriber connection
+func (tc *TieredCache[T]) startTracking(ctx context.Context) (*tracker, error) {
+	// A dedicated client with a unique name lets us find the subscriber's ID
+	name := "redisclient-tracking-" + tc.id
+	client, err := tc.remote.client.nodeClient(name, 2)
+	if err != nil {
+		return nil, fmt.Errorf("client tracking: %w", err)
+	}
+
+	t := &tracker{client: client}
+
+	t.sub = t.client.Subscribe(ctx, trackingChannel)
+	if _, err := t.sub.Receive(ctx); err != nil {
+		t.close()
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", trackingChannel, err)
+	}
+
+	id, err := t.subscriberID(ctx, name)
+	if err != nil {
+		t.close()
+		return nil, err
+	}
+
+	args := []interface{}{"CLIENT", "TRACKING", "ON", "REDIRECT", id, "BCAST"}
+	if prefix := tc.remote.client.PrefixKey(tc.remote.opts.Prefix); prefix != "" {
+		args = append(args, "PREFIX", prefix)
+	}
+
+	// Tracking lives as long as this connection, so it is kept open
+	t.conn = t.client.Conn()
+	if err := t.conn.Do(ctx, args...).Err(); err != nil {
+		t.close()
+		return nil, fmt.Errorf("failed to enable client tracking: %w", err)
+	}
+
+	return t, nil
+}
+
+// subscriberID finds the ID of the pub/sub connection by its client name
+func (t *tracker) subscriberID(ctx context.Context, name string) (int64, error) {
+	list, err := t.client.Do(ctx, "CLIENT", "LIST", "TYPE", "pubsub").Text()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list clients: %w", err)
+	}
+
+	for _, line := range strings.Split(list, "\n") {
+		fields := make(map[string]string)
+		for _, field := range strings.Fields(line) {
+			if k, v, ok := strings.Cut(field, "="); ok {
+				fields[k] = v
+			}
+		}
+		if fields["name"] == name {
+			var id int64
+			if _, err := fmt.Sscan(fields["id"], &id); err == nil {
+				return id, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("tracking subscriber connection not found")
+}
+
+// listenTracking applies tracking invalidations, re-establishing tracking
+// after connection errors since the redirect target changes on reconnect
+func (tc *TieredCache[T]) listenTracking(ctx context.Context, t *tracker) {
+	defer close(tc.done)
+	defer func() {
+		if t != nil {
+			t.close()
+		}
+	}()
+
+	for {
+		if t == nil {
+			var err error
+			if t, err = tc.startTracking(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				sleepContext(ctx, time.Second)
+				continue
+			}
+			if !tc.setListener(t.close) {
+				return
+			}
+		}
+
+		msg, err := t.sub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			t.close()
+			t = nil
+			tc.invalidate(nil)
+			continue
+		}
+
+		m, ok := msg.(*redis.Message)
+		if !ok || m.Channel != trackingChannel {
+			continue
+		}
+
+		// A nil payload means the database was flushed
+		keys := m.PayloadSlice
+		for i, key := range keys {
+			keys[i] = tc.remote.client.StripPrefix(key)
+		}
+		tc.invalidate(keys)
+	}
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,157 @@
+package redisclient
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestLocalCache_LRU tests that the least recently used entry is evicted
+func TestLocalCache_LRU(t *testing.T) {
+	lc := newLocalCache[int](2, 0, EvictLRU)
+
+	lc.set("a", 1)
+	lc.set("b", 2)
+	lc.get("a")
+	lc.set("c", 3)
+
+	if _, ok := lc.get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	if v, ok := lc.get("a"); !ok || v != 1 {
+		t.Errorf("get(a) = %v, %v", v, ok)
+	}
+	if lc.evictionCount() != 1 {
+		t.Errorf("evictionCount() = %d, want 1", lc.evictionCount())
+	}
+}
+
+// TestLocalCache_LFU tests that the least frequently used entry is evicted
+func TestLocalCache_LFU(t *testing.T) {
+	lc := newLocalCache[int](2, 0, EvictLFU)
+
+	lc.set("a", 1)
+	lc.set("b", 2)
+	lc.get("a")
+	lc.get("a")
+	lc.get("b")
+	lc.set("c", 3)
+
+	if _, ok := lc.get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+
+	// c has the lowest count now, so it goes before a
+	lc.set("d", 4)
+	if _, ok := lc.get("c"); ok {
+		t.Error("c should have been evicted")
+	}
+	if _, ok := lc.get("a"); !ok {
+		t.Error("a should still be cached")
+	}
+
+	lc.delete("a", "d")
+	if lc.len() != 0 {
+		t.Errorf("len() = %d, want 0", lc.len())
+	}
+	lc.set("e", 5)
+	if v, ok := lc.get("e"); !ok || v != 5 {
+		t.Errorf("get(e) after delete = %v, %v", v, ok)
+	}
+}
+
+// TestLocalCache_TTL tests that expired entries are not returned
+func TestLocalCache_TTL(t *testing.T) {
+	lc := newLocalCache[string](10, 20*time.Millisecond, EvictLRU)
+
+	lc.set("a", "x")
+	if _, ok := lc.get("a"); !ok {
+		t.Fatal("a should be cached")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := lc.get("a"); ok {
+		t.Error("a should have expired")
+	}
+	if lc.len() != 0 {
+		t.Errorf("len() = %d, want 0", lc.len())
+	}
+}
+
+// TestNewTieredCache_Options tests option validation
+func TestNewTieredCache_Options(t *testing.T) {
+	cache := NewCache[string](&Client{config: DefaultConfig()})
+
+	if _, err := NewTieredCache(cache, WithLocalSize(0)); err == nil {
+		t.Error("expected error for zero local size")
+	}
+	if _, err := NewTieredCache(cache, WithInvalidationChannel("")); err == nil {
+		t.Error("expected error for empty channel")
+	}
+
+	closed := NewCache[string](&Client{config: DefaultConfig(), closed: true})
+	if _, err := NewTieredCache(closed); err != ErrClientClosed {
+		t.Errorf("NewTieredCache() error = %v, want ErrClientClosed", err)
+	}
+}
+
+// TestTieredCache_Local tests local hits, invalidation and stats
+func TestTieredCache_Local(t *testing.T) {
+	cache := NewCache[string](&Client{config: DefaultConfig()}, WithCachePrefix("user:"))
+
+	tc, err := NewTieredCache(cache,
+		WithInvalidation(InvalidateNone),
+		WithLocalSize(10),
+		WithEviction(EvictLFU),
+	)
+	if err != nil {
+		t.Fatalf("NewTieredCache() error = %v", err)
+	}
+
+	tc.storeLocal("1", localValue[string]{value: "Ada"}, tc.generation.Load())
+	tc.storeLocal("2", localValue[string]{negative: true}, tc.generation.Load())
+
+	if v, err := tc.Get(context.Background(), "1"); err != nil || v != "Ada" {
+		t.Errorf("Get(1) = %q, %v", v, err)
+	}
+	if _, err := tc.Get(context.Background(), "2"); err != ErrNotFound {
+		t.Errorf("Get(2) error = %v, want ErrNotFound", err)
+	}
+
+	// A read that started before an invalidation must not repopulate the tier
+	generation := tc.generation.Load()
+	tc.invalidate([]string{"user:1"})
+	tc.storeLocal("1", localValue[string]{value: "stale"}, generation)
+	if _, ok := tc.local.get("user:1"); ok {
+		t.Error("stale value should not be stored after invalidation")
+	}
+
+	stats := tc.Stats()
+	if stats.LocalHits != 2 || stats.Invalidations != 1 || stats.LocalEntries != 1 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+
+	if err := tc.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if err := tc.Close(); err != ErrAlreadyClosed {
+		t.Errorf("second Close() error = %v, want ErrAlreadyClosed", err)
+	}
+}
+
+// TestTieredCache_Unreachable tests that invalidation setup fails fast
+func TestTieredCache_Unreachable(t *testing.T) {
+	client, err := NewWithOptions(
+		WithAddr("127.0.0.1:1"),
+		WithMaxRetries(0),
+		WithDialTimeout(100*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("NewWithOptions() error = %v", err)
+	}
+	defer client.Close()
+
+	if _, err := NewTieredCache(NewCache[string](client)); err == nil {
+		t.Error("expected error subscribing to unreachable server")
+	}
+}
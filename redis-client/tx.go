@@ -0,0 +1,199 @@
+package redisclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ====================
+// Optimistic Transactions
+// ====================
+
+// TxOptions configures Transaction and the read-modify-write helpers
+type TxOptions struct {
+	MaxRetries    int           // Attempts after the first when a watched key changes, 0 runs once
+	RetryDelay    time.Duration // Initial delay between attempts
+	MaxRetryDelay time.Duration // Upper bound for the exponential backoff
+	TTL           time.Duration // TTL for values written by the helpers, 0 keeps the current TTL
+}
+
+// DefaultTxOptions returns the default transaction options
+func DefaultTxOptions() TxOptions {
+	return TxOptions{
+		MaxRetries:    10,
+		RetryDelay:    5 * time.Millisecond,
+		MaxRetryDelay: 200 * time.Millisecond,
+	}
+}
+
+// TxOption is a functional option for configuring a transaction
+type TxOption func(*TxOptions)
+
+// WithTxRetry sets how often and how fast a conflicting transaction is retried
+func WithTxRetry(maxRetries int, delay, maxDelay time.Duration) TxOption {
+	return func(o *TxOptions) {
+		o.MaxRetries = maxRetries
+		o.RetryDelay = delay
+		o.MaxRetryDelay = maxDelay
+	}
+}
+
+// WithTxTTL sets the TTL of values written by UpdateString, UpdateInt64 and
+// UpdateJSON. By default the key keeps its current TTL.
+func WithTxTTL(ttl time.Duration) TxOption {
+	return func(o *TxOptions) {
+		o.TTL = ttl
+	}
+}
+
+// Transaction watches keys and runs fn, retrying with exponential backoff and
+// jitter while another client modifies a watched key before fn's
+// MULTI/EXEC commits. fn must queue its writes with tx.TxPipelined so that
+// they only apply if no watched key changed; it may run several times.
+//
+// An error returned by fn aborts the transaction and is returned as is. When
+// all retries conflict, the error matches both ErrTxConflict and
+// redis.TxFailedErr.
+func (c *Client) Transaction(ctx context.Context, keys []string, fn func(*redis.Tx) error, opts ...TxOption) error {
+	if len(keys) == 0 {
+		return ErrInvalidKey
+	}
+	for _, key := range keys {
+		if key == "" {
+			return ErrInvalidKey
+		}
+	}
+
+	options := DefaultTxOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := c.Watch(ctx, fn, keys...)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+		if attempt >= options.MaxRetries {
+			return fmt.Errorf("%w after %d attempts: %w", ErrTxConflict, attempt+1, err)
+		}
+
+		timer := time.NewTimer(txBackoff(options, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// UpdateString runs a watched read-modify-write of a string value. fn receives
+// the current value and whether the key exists, and returns the new value.
+func (c *Client) UpdateString(ctx context.Context, key string, fn func(value string, exists bool) (string, error), opts ...TxOption) error {
+	options := DefaultTxOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return c.Transaction(ctx, []string{key}, func(tx *redis.Tx) error {
+		value, err := tx.Get(ctx, key).Result()
+		exists := err == nil
+		if err != nil && !IsNil(err) {
+			return err
+		}
+
+		updated, err := fn(value, exists)
+		if err != nil {
+			return err
+		}
+
+		return txSet(ctx, tx, key, updated, options.TTL)
+	}, opts...)
+}
+
+// UpdateInt64 runs a watched read-modify-write of an integer value. A missing
+// key reads as 0.
+func (c *Client) UpdateInt64(ctx context.Context, key string, fn func(value int64) (int64, error), opts ...TxOption) error {
+	return c.UpdateString(ctx, key, func(value string, exists bool) (string, error) {
+		var n int64
+		if exists {
+			var err error
+			if n, err = strconv.ParseInt(value, 10, 64); err != nil {
+				return "", fmt.Errorf("value of %q is not an integer: %w", key, err)
+			}
+		}
+
+		updated, err := fn(n)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(updated, 10), nil
+	}, opts...)
+}
+
+// UpdateJSON runs a watched read-modify-write of a JSON value. fn receives the
+// decoded value, or the zero value if the key does not exist, and modifies it
+// in place. A value that cannot be decoded returns ErrCacheCorrupt.
+//
+//	err := redisclient.UpdateJSON(ctx, client, "cart:42", func(cart *Cart) error {
+//	    cart.Items = append(cart.Items, item)
+//	    return nil
+//	})
+func UpdateJSON[T any](ctx context.Context, client *Client, key string, fn func(*T) error, opts ...TxOption) error {
+	return client.UpdateString(ctx, key, func(data string, exists bool) (string, error) {
+		var value T
+		if exists {
+			if err := json.Unmarshal([]byte(data), &value); err != nil {
+				return "", fmt.Errorf("%w: %q: %v", ErrCacheCorrupt, key, err)
+			}
+		}
+
+		if err := fn(&value); err != nil {
+			return "", err
+		}
+
+		encoded, err := json.Marshal(&value)
+		if err != nil {
+			return "", err
+		}
+		return string(encoded), nil
+	}, opts...)
+}
+
+// txSet queues a SET of the key in a MULTI/EXEC block
+func txSet(ctx context.Context, tx *redis.Tx, key, value string, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = redis.KeepTTL
+	}
+	_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, value, ttl)
+		return nil
+	})
+	return err
+}
+
+// txBackoff returns the delay before retrying after the given attempt, with jitter
+func txBackoff(options TxOptions, attempt int) time.Duration {
+	delay := options.RetryDelay
+	for i := 0; i < attempt && (options.MaxRetryDelay <= 0 || delay < options.MaxRetryDelay); i++ {
+		delay *= 2
+	}
+	if options.MaxRetryDelay > 0 && delay > options.MaxRetryDelay {
+		delay = options.MaxRetryDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	// Equal jitter keeps conflicting writers from retrying in lockstep
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
@@ -0,0 +1,76 @@
+package redisclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TestTransaction_Validation tests argument checks and closed clients
+func TestTransaction_Validation(t *testing.T) {
+	ctx := context.Background()
+	noop := func(*redis.Tx) error { return nil }
+
+	client := &Client{config: DefaultConfig()}
+	if err := client.Transaction(ctx, nil, noop); err != ErrInvalidKey {
+		t.Errorf("Transaction() without keys error = %v, want %v", err, ErrInvalidKey)
+	}
+	if err := client.Transaction(ctx, []string{"a", ""}, noop); err != ErrInvalidKey {
+		t.Errorf("Transaction() with an empty key error = %v, want %v", err, ErrInvalidKey)
+	}
+
+	closed := &Client{config: DefaultConfig(), closed: true}
+	if err := closed.Transaction(ctx, []string{"a"}, noop); err != ErrClientClosed {
+		t.Errorf("Transaction() on closed client error = %v, want %v", err, ErrClientClosed)
+	}
+	if err := closed.UpdateInt64(ctx, "n", func(n int64) (int64, error) { return n + 1, nil }); err != ErrClientClosed {
+		t.Errorf("UpdateInt64() on closed client error = %v, want %v", err, ErrClientClosed)
+	}
+	err := UpdateJSON(ctx, closed, "doc", func(v *map[string]int) error { return nil })
+	if err != ErrClientClosed {
+		t.Errorf("UpdateJSON() on closed client error = %v, want %v", err, ErrClientClosed)
+	}
+}
+
+// TestTxOptions tests the defaults and functional options
+func TestTxOptions(t *testing.T) {
+	options := DefaultTxOptions()
+	if options.MaxRetries != 10 || options.TTL != 0 {
+		t.Errorf("DefaultTxOptions() = %+v", options)
+	}
+
+	WithTxRetry(3, time.Millisecond, 10*time.Millisecond)(&options)
+	WithTxTTL(time.Minute)(&options)
+	if options.MaxRetries != 3 || options.RetryDelay != time.Millisecond ||
+		options.MaxRetryDelay != 10*time.Millisecond || options.TTL != time.Minute {
+		t.Errorf("options after WithTxRetry/WithTxTTL = %+v", options)
+	}
+}
+
+// TestTxBackoff tests that the retry delay grows, is capped and is jittered
+func TestTxBackoff(t *testing.T) {
+	options := TxOptions{RetryDelay: 10 * time.Millisecond, MaxRetryDelay: 40 * time.Millisecond}
+
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{0, 5 * time.Millisecond, 10 * time.Millisecond},
+		{1, 10 * time.Millisecond, 20 * time.Millisecond},
+		{2, 20 * time.Millisecond, 40 * time.Millisecond},
+		{10, 20 * time.Millisecond, 40 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			if d := txBackoff(options, tt.attempt); d < tt.min || d > tt.max {
+				t.Fatalf("txBackoff(%d) = %v, want between %v and %v", tt.attempt, d, tt.min, tt.max)
+			}
+		}
+	}
+
+	if d := txBackoff(TxOptions{}, 3); d != 0 {
+		t.Errorf("txBackoff() without delay = %v, want 0", d)
+	}
+}
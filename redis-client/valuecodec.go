@@ -0,0 +1,469 @@
+package redisclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	cryptoutils "github.com/isimtekin/go-packages/crypto-utils"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/redis/go-redis/v9"
+)
+
+// ====================
+// Value Compression and Encryption
+// ====================
+
+// Compression selects the algorithm a ValueCodec compresses with
+type Compression byte
+
+const (
+	CompressionNone   Compression = iota // Store values uncompressed
+	CompressionGzip                      // gzip, best ratio, slowest
+	CompressionZstd                      // zstd, good ratio, fast
+	CompressionSnappy                    // snappy, lowest ratio, fastest
+)
+
+// String returns the algorithm name
+func (c Compression) String() string {
+	switch c {
+	case CompressionNone:
+		return "none"
+	case CompressionGzip:
+		return "gzip"
+	case CompressionZstd:
+		return "zstd"
+	case CompressionSnappy:
+		return "snappy"
+	default:
+		return "compression(" + strconv.Itoa(int(c)) + ")"
+	}
+}
+
+// Encoded value layout: magic (2 bytes), flags, key ID if encrypted, payload.
+// The magic bytes are not valid UTF-8, so text and JSON values written before
+// the codec was enabled are read unchanged.
+const (
+	valueMagic0          = 0xFF
+	valueMagic1          = 0xC0
+	valueFlagEncrypted   = 1 << 7
+	valueCompressionMask = 0x0F
+)
+
+// ValueCodecOptions configures a ValueCodec
+type ValueCodecOptions struct {
+	Compression       Compression     // Compression algorithm, CompressionNone disables compression
+	CompressThreshold int             // Values shorter than this are not compressed
+	Keys              map[byte][]byte // AES keys (16, 24 or 32 bytes) by key ID; no keys disables encryption
+	ActiveKeyID       byte            // Key ID used to encrypt; all keys can decrypt
+}
+
+// DefaultValueCodecOptions returns the default value codec options
+func DefaultValueCodecOptions() ValueCodecOptions {
+	return ValueCodecOptions{
+		CompressThreshold: 1024,
+	}
+}
+
+// ValueCodecOption is a functional option for configuring a ValueCodec
+type ValueCodecOption func(*ValueCodecOptions)
+
+// WithCompression compresses values of at least threshold bytes with alg
+func WithCompression(alg Compression, threshold int) ValueCodecOption {
+	return func(o *ValueCodecOptions) {
+		o.Compression = alg
+		o.CompressThreshold = threshold
+	}
+}
+
+// WithEncryptionKey adds an AES-GCM key under the given ID and makes it the
+// active key. Keep retired keys registered so values they encrypted can still
+// be read; register the new key last.
+func WithEncryptionKey(id byte, key []byte) ValueCodecOption {
+	return func(o *ValueCodecOptions) {
+		if o.Keys == nil {
+			o.Keys = make(map[byte][]byte)
+		}
+		o.Keys[id] = key
+		o.ActiveKeyID = id
+	}
+}
+
+// WithActiveKey selects which registered key encrypts new values
+func WithActiveKey(id byte) ValueCodecOption {
+	return func(o *ValueCodecOptions) {
+		o.ActiveKeyID = id
+	}
+}
+
+// ValueCodec compresses and encrypts stored values. Values at or above the
+// compression threshold are compressed if that makes them smaller, and every
+// value is encrypted with AES-GCM when keys are configured. A header records
+// the algorithm and the key ID, so Decode reads values written with any
+// algorithm or registered key, and passes through plain values written
+// without a codec.
+//
+// A value that is neither compressed nor encrypted is stored unchanged, so
+// with compression only, small values such as counters keep working with
+// INCR and friends. With encryption they do not.
+type ValueCodec struct {
+	opts ValueCodecOptions
+
+	zstdOnce sync.Once
+	zstdEnc  *zstd.Encoder
+	zstdDec  *zstd.Decoder
+	zstdErr  error
+}
+
+// NewValueCodec creates a value codec
+func NewValueCodec(opts ...ValueCodecOption) (*ValueCodec, error) {
+	options := DefaultValueCodecOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.Compression > CompressionSnappy {
+		return nil, fmt.Errorf("unknown compression %s", options.Compression)
+	}
+	if options.CompressThreshold < 0 {
+		return nil, fmt.Errorf("compression threshold cannot be negative")
+	}
+	for id, key := range options.Keys {
+		if len(key) != 16 && len(key) != 24 && len(key) != 32 {
+			return nil, fmt.Errorf("encryption key %d: %w", id, cryptoutils.ErrInvalidKeySize)
+		}
+	}
+	if len(options.Keys) > 0 {
+		if _, ok := options.Keys[options.ActiveKeyID]; !ok {
+			return nil, fmt.Errorf("active key %d is not registered", options.ActiveKeyID)
+		}
+	}
+
+	return &ValueCodec{opts: options}, nil
+}
+
+// Encode compresses and encrypts a value
+func (vc *ValueCodec) Encode(data []byte) ([]byte, error) {
+	var flags byte
+	payload := data
+
+	if vc.opts.Compression != CompressionNone && len(data) >= vc.opts.CompressThreshold {
+		compressed, err := vc.compress(vc.opts.Compression, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compress value: %w", err)
+		}
+		if len(compressed) < len(data) {
+			payload = compressed
+			flags |= byte(vc.opts.Compression)
+		}
+	}
+
+	encrypted := len(vc.opts.Keys) > 0
+	if flags == 0 && !encrypted {
+		return data, nil
+	}
+
+	header := []byte{valueMagic0, valueMagic1, flags}
+	if encrypted {
+		header[2] |= valueFlagEncrypted
+		header = append(header, vc.opts.ActiveKeyID)
+
+		ciphertext, err := cryptoutils.EncryptAESGCM(vc.opts.Keys[vc.opts.ActiveKeyID], payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt value: %w", err)
+		}
+		payload = ciphertext
+	}
+
+	return append(header, payload...), nil
+}
+
+// Decode reverses Encode. Data without the codec header is returned unchanged.
+func (vc *ValueCodec) Decode(data []byte) ([]byte, error) {
+	if len(data) < 3 || data[0] != valueMagic0 || data[1] != valueMagic1 {
+		return data, nil
+	}
+
+	flags := data[2]
+	payload := data[3:]
+
+	if flags&valueFlagEncrypted != 0 {
+		if len(payload) == 0 {
+			return nil, fmt.Errorf("%w: missing key ID", ErrValueDecode)
+		}
+		id := payload[0]
+		key, ok := vc.opts.Keys[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrUnknownKeyID, id)
+		}
+
+		plaintext, err := cryptoutils.DecryptAESGCM(key, payload[1:])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrValueDecode, err)
+		}
+		payload = plaintext
+	}
+
+	if alg := Compression(flags & valueCompressionMask); alg != CompressionNone {
+		decompressed, err := vc.decompress(alg, payload)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrValueDecode, err)
+		}
+		payload = decompressed
+	}
+
+	return payload, nil
+}
+
+// compress compresses data with alg
+func (vc *ValueCodec) compress(alg Compression, data []byte) ([]byte, error) {
+	switch alg {
+	case CompressionGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case CompressionZstd:
+		if err := vc.initZstd(); err != nil {
+			return nil, err
+		}
+		return vc.zstdEnc.EncodeAll(data, nil), nil
+	case CompressionSnappy:
+		return snappy.Encode(nil, data), nil
+	default:
+		return nil, fmt.Errorf("unknown compression %s", alg)
+	}
+}
+
+// decompress decompresses data written by compress
+func (vc *ValueCodec) decompress(alg Compression, data []byte) ([]byte, error) {
+	switch alg {
+	case CompressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	case CompressionZstd:
+		if err := vc.initZstd(); err != nil {
+			return nil, err
+		}
+		return vc.zstdDec.DecodeAll(data, nil)
+	case CompressionSnappy:
+		return snappy.Decode(nil, data)
+	default:
+		return nil, fmt.Errorf("unknown compression %s", alg)
+	}
+}
+
+// initZstd creates the zstd encoder and decoder on first use. Both are safe
+// for concurrent EncodeAll and DecodeAll calls.
+func (vc *ValueCodec) initZstd() error {
+	vc.zstdOnce.Do(func() {
+		if vc.zstdEnc, vc.zstdErr = zstd.NewWriter(nil); vc.zstdErr != nil {
+			return
+		}
+		vc.zstdDec, vc.zstdErr = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+	})
+	return vc.zstdErr
+}
+
+// codecHook encodes values written by string and hash commands and decodes
+// the values they return
+type codecHook struct {
+	codec *ValueCodec
+}
+
+// DialHook implements redis.Hook
+func (h codecHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements redis.Hook
+func (h codecHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.encode(cmd); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		if err := next(ctx, cmd); err != nil {
+			return err
+		}
+		return h.decode(cmd)
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook
+func (h codecHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			if err := h.encode(cmd); err != nil {
+				cmd.SetErr(err)
+				return err
+			}
+		}
+		err := next(ctx, cmds)
+		for _, cmd := range cmds {
+			if cmd.Err() == nil {
+				_ = h.decode(cmd)
+			}
+		}
+		return err
+	}
+}
+
+// valueArgs returns the positions of the value arguments of a write command
+func valueArgs(name string, args []interface{}) []int {
+	var first, step int
+	switch name {
+	case "set", "setnx", "getset":
+		first, step = 2, 0
+	case "setex", "psetex", "hsetnx":
+		first, step = 3, 0
+	case "mset", "msetnx":
+		first, step = 2, 2
+	case "hset", "hmset":
+		first, step = 3, 2
+	default:
+		return nil
+	}
+
+	if step == 0 {
+		if first < len(args) {
+			return []int{first}
+		}
+		return nil
+	}
+	var positions []int
+	for i := first; i < len(args); i += step {
+		positions = append(positions, i)
+	}
+	return positions
+}
+
+// encode replaces the value arguments of cmd with their encoded form
+func (h codecHook) encode(cmd redis.Cmder) error {
+	args := cmd.Args()
+	for _, i := range valueArgs(cmd.Name(), args) {
+		data, err := argBytes(args[i])
+		if err != nil {
+			return err
+		}
+		if args[i], err = h.codec.Encode(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decode replaces the values in the reply of cmd with their decoded form
+func (h codecHook) decode(cmd redis.Cmder) error {
+	var err error
+	decodeString := func(s string) string {
+		data, decodeErr := h.codec.Decode([]byte(s))
+		if decodeErr != nil {
+			err = decodeErr
+			return s
+		}
+		return string(data)
+	}
+
+	switch c := cmd.(type) {
+	case *redis.StringCmd:
+		switch cmd.Name() {
+		case "get", "getset", "getdel", "getex", "hget", "set":
+			c.SetVal(decodeString(c.Val()))
+		}
+	case *redis.SliceCmd:
+		switch cmd.Name() {
+		case "mget", "hmget":
+			vals := c.Val()
+			for i, v := range vals {
+				if s, ok := v.(string); ok {
+					vals[i] = decodeString(s)
+				}
+			}
+		}
+	case *redis.StringSliceCmd:
+		if cmd.Name() == "hvals" {
+			vals := c.Val()
+			for i, v := range vals {
+				vals[i] = decodeString(v)
+			}
+		}
+	case *redis.MapStringStringCmd:
+		if cmd.Name() == "hgetall" {
+			vals := c.Val()
+			for field, v := range vals {
+				vals[field] = decodeString(v)
+			}
+		}
+	}
+
+	if err != nil {
+		cmd.SetErr(err)
+	}
+	return err
+}
+
+// argBytes converts a command argument to the bytes Redis would store
+func argBytes(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	case int:
+		return strconv.AppendInt(nil, int64(v), 10), nil
+	case int8:
+		return strconv.AppendInt(nil, int64(v), 10), nil
+	case int16:
+		return strconv.AppendInt(nil, int64(v), 10), nil
+	case int32:
+		return strconv.AppendInt(nil, int64(v), 10), nil
+	case int64:
+		return strconv.AppendInt(nil, v, 10), nil
+	case uint:
+		return strconv.AppendUint(nil, uint64(v), 10), nil
+	case uint8:
+		return strconv.AppendUint(nil, uint64(v), 10), nil
+	case uint16:
+		return strconv.AppendUint(nil, uint64(v), 10), nil
+	case uint32:
+		return strconv.AppendUint(nil, uint64(v), 10), nil
+	case uint64:
+		return strconv.AppendUint(nil, v, 10), nil
+	case float32:
+		return strconv.AppendFloat(nil, float64(v), 'f', -1, 64), nil
+	case float64:
+		return strconv.AppendFloat(nil, v, 'f', -1, 64), nil
+	case bool:
+		if v {
+			return []byte("1"), nil
+		}
+		return []byte("0"), nil
+	case time.Time:
+		return v.AppendFormat(nil, time.RFC3339Nano), nil
+	case time.Duration:
+		return strconv.AppendInt(nil, v.Nanoseconds(), 10), nil
+	case encoding.BinaryMarshaler:
+		return v.MarshalBinary()
+	default:
+		return nil, fmt.Errorf("redis: can't marshal %T (implement encoding.BinaryMarshaler)", v)
+	}
+}
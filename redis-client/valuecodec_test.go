@@ -0,0 +1,214 @@
+package redisclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	testKey1 = bytes.Repeat([]byte{1}, 32)
+	testKey2 = bytes.Repeat([]byte{2}, 16)
+)
+
+// TestValueCodec_RoundTrip tests every compression algorithm with and without encryption
+func TestValueCodec_RoundTrip(t *testing.T) {
+	large := []byte(strings.Repeat(`{"name":"John","email":"john@example.com"},`, 100))
+	small := []byte("hello")
+
+	for _, alg := range []Compression{CompressionNone, CompressionGzip, CompressionZstd, CompressionSnappy} {
+		for _, encrypt := range []bool{false, true} {
+			opts := []ValueCodecOption{WithCompression(alg, 256)}
+			if encrypt {
+				opts = append(opts, WithEncryptionKey(1, testKey1))
+			}
+			codec, err := NewValueCodec(opts...)
+			if err != nil {
+				t.Fatalf("NewValueCodec() error = %v", err)
+			}
+
+			for _, value := range [][]byte{large, small, {}} {
+				encoded, err := codec.Encode(value)
+				if err != nil {
+					t.Fatalf("%s encrypt=%v: Encode() error = %v", alg, encrypt, err)
+				}
+				if encrypt && bytes.Contains(encoded, []byte("john")) {
+					t.Errorf("%s: encrypted value contains plaintext", alg)
+				}
+				if alg != CompressionNone && !encrypt && len(value) == len(large) && len(encoded) >= len(large) {
+					t.Errorf("%s: value was not compressed", alg)
+				}
+
+				decoded, err := codec.Decode(encoded)
+				if err != nil {
+					t.Fatalf("%s encrypt=%v: Decode() error = %v", alg, encrypt, err)
+				}
+				if !bytes.Equal(decoded, value) {
+					t.Errorf("%s encrypt=%v: Decode() did not return the original value", alg, encrypt)
+				}
+			}
+		}
+	}
+}
+
+// TestValueCodec_Passthrough tests that small values and plain data stay unchanged
+func TestValueCodec_Passthrough(t *testing.T) {
+	codec, err := NewValueCodec(WithCompression(CompressionZstd, 1024))
+	if err != nil {
+		t.Fatalf("NewValueCodec() error = %v", err)
+	}
+
+	encoded, _ := codec.Encode([]byte("42"))
+	if string(encoded) != "42" {
+		t.Errorf("Encode() of a small value = %q, want it unchanged", encoded)
+	}
+
+	incompressible := make([]byte, 2048)
+	rand.Read(incompressible)
+	if encoded, _ := codec.Encode(incompressible); !bytes.Equal(encoded, incompressible) {
+		t.Error("Encode() should store values that do not compress unchanged")
+	}
+
+	for _, plain := range []string{"", "plain text", `{"a":1}`} {
+		if decoded, err := codec.Decode([]byte(plain)); err != nil || string(decoded) != plain {
+			t.Errorf("Decode(%q) = %q, %v", plain, decoded, err)
+		}
+	}
+}
+
+// TestValueCodec_KeyRotation tests that retired keys still decrypt
+func TestValueCodec_KeyRotation(t *testing.T) {
+	old, _ := NewValueCodec(WithEncryptionKey(1, testKey1))
+	rotated, err := NewValueCodec(WithEncryptionKey(1, testKey1), WithEncryptionKey(2, testKey2))
+	if err != nil {
+		t.Fatalf("NewValueCodec() error = %v", err)
+	}
+
+	written, _ := old.Encode([]byte("secret"))
+	if decoded, err := rotated.Decode(written); err != nil || string(decoded) != "secret" {
+		t.Errorf("Decode() with a retired key = %q, %v", decoded, err)
+	}
+
+	fresh, _ := rotated.Encode([]byte("secret"))
+	if fresh[3] != 2 {
+		t.Errorf("key ID = %d, want the active key 2", fresh[3])
+	}
+	if _, err := old.Decode(fresh); !errors.Is(err, ErrUnknownKeyID) {
+		t.Errorf("Decode() with an unknown key error = %v, want %v", err, ErrUnknownKeyID)
+	}
+
+	fresh[len(fresh)-1] ^= 0xFF
+	if _, err := rotated.Decode(fresh); !errors.Is(err, ErrValueDecode) {
+		t.Errorf("Decode() of a tampered value error = %v, want %v", err, ErrValueDecode)
+	}
+}
+
+// TestNewValueCodec_Validation tests option validation
+func TestNewValueCodec_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []ValueCodecOption
+	}{
+		{"unknown compression", []ValueCodecOption{WithCompression(Compression(9), 0)}},
+		{"negative threshold", []ValueCodecOption{WithCompression(CompressionGzip, -1)}},
+		{"bad key size", []ValueCodecOption{WithEncryptionKey(1, []byte("short"))}},
+		{"unregistered active key", []ValueCodecOption{WithEncryptionKey(1, testKey1), WithActiveKey(3)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewValueCodec(tt.opts...); err == nil {
+				t.Error("NewValueCodec() should fail")
+			}
+		})
+	}
+}
+
+// TestCodecHook tests that write commands are encoded and read replies decoded
+func TestCodecHook(t *testing.T) {
+	ctx := context.Background()
+	codec, _ := NewValueCodec(WithEncryptionKey(1, testKey1))
+	hook := codecHook{codec: codec}
+
+	set := redis.NewStatusCmd(ctx, "set", "k", "secret", "ex", 60)
+	mset := redis.NewStatusCmd(ctx, "mset", "a", 1, "b", 2.5)
+	hset := redis.NewIntCmd(ctx, "hset", "h", "f1", "v1", "f2", true)
+	incr := redis.NewIntCmd(ctx, "incrby", "n", 5)
+	for _, cmd := range []redis.Cmder{set, mset, hset, incr} {
+		if err := hook.encode(cmd); err != nil {
+			t.Fatalf("encode(%s) error = %v", cmd.Name(), err)
+		}
+	}
+
+	for _, arg := range []interface{}{set.Args()[2], mset.Args()[2], mset.Args()[4], hset.Args()[3], hset.Args()[5]} {
+		if _, ok := arg.([]byte); !ok {
+			t.Errorf("value argument %v was not encoded", arg)
+		}
+	}
+	if set.Args()[4] != 60 || mset.Args()[1] != "a" || hset.Args()[2] != "f1" || incr.Args()[2] != 5 {
+		t.Error("keys, fields and options must not be encoded")
+	}
+
+	get := redis.NewStringCmd(ctx, "get", "k")
+	get.SetVal(string(set.Args()[2].([]byte)))
+	mget := redis.NewSliceCmd(ctx, "mget", "a", "missing")
+	mget.SetVal([]interface{}{string(mset.Args()[2].([]byte)), nil})
+	hgetall := redis.NewMapStringStringCmd(ctx, "hgetall", "h")
+	hgetall.SetVal(map[string]string{"f2": string(hset.Args()[5].([]byte))})
+	for _, cmd := range []redis.Cmder{get, mget, hgetall} {
+		if err := hook.decode(cmd); err != nil {
+			t.Fatalf("decode(%s) error = %v", cmd.Name(), err)
+		}
+	}
+
+	if get.Val() != "secret" {
+		t.Errorf("GET = %q, want secret", get.Val())
+	}
+	if vals := mget.Val(); vals[0] != "1" || vals[1] != nil {
+		t.Errorf("MGET = %v, want [1 <nil>]", vals)
+	}
+	if hgetall.Val()["f2"] != "1" {
+		t.Errorf("HGETALL = %v, want f2=1", hgetall.Val())
+	}
+
+	unknown, _ := NewValueCodec(WithEncryptionKey(9, testKey2))
+	bad := redis.NewStringCmd(ctx, "get", "k")
+	bad.SetVal(string(set.Args()[2].([]byte)))
+	if err := (codecHook{codec: unknown}).decode(bad); !errors.Is(bad.Err(), ErrUnknownKeyID) || err == nil {
+		t.Errorf("decode() with an unknown key error = %v", bad.Err())
+	}
+
+	unsupported := redis.NewStatusCmd(ctx, "set", "k", struct{}{})
+	if err := hook.encode(unsupported); err == nil {
+		t.Error("encode() should reject values that cannot be marshaled")
+	}
+}
+
+// TestCache_ValueCodec tests cache entries encoded with a value codec
+func TestCache_ValueCodec(t *testing.T) {
+	codec, _ := NewValueCodec(WithCompression(CompressionSnappy, 0), WithEncryptionKey(1, testKey1))
+	cache := NewCache[string](&Client{config: DefaultConfig()}, WithCacheValueCodec(codec))
+
+	data, err := cache.encode("sensitive", false, 0, 0)
+	if err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+	if bytes.Contains(data, []byte("sensitive")) {
+		t.Error("entry should be encrypted")
+	}
+
+	entry, err := cache.decode(data)
+	if err != nil || entry.value != "sensitive" {
+		t.Errorf("decode() = %q, %v", entry.value, err)
+	}
+
+	plain := NewCache[string](&Client{config: DefaultConfig()})
+	if _, err := plain.decode(data); !errors.Is(err, ErrCacheCorrupt) {
+		t.Errorf("decode() without the codec error = %v, want %v", err, ErrCacheCorrupt)
+	}
+}